@@ -19,14 +19,27 @@ package apis
 
 import (
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	backblazev2 "github.com/rossigee/provider-backblaze/apis/backblaze/v2"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+	policyv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/policy/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/user/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
 
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
-	// v1 cluster-scoped APIs (Crossplane v2)
-	AddToSchemes = append(AddToSchemes, backblazev1.SchemeBuilder.AddToScheme)
+	// v1 and v2 cluster-scoped APIs, the v1beta1 namespaced .m. APIs (Crossplane v2)
+	// and the v1beta1 ProviderConfig APIs
+	AddToSchemes = append(AddToSchemes,
+		backblazev1.SchemeBuilder.AddToScheme,
+		backblazev2.SchemeBuilder.AddToScheme,
+		bucketv1beta1.SchemeBuilder.AddToScheme,
+		userv1beta1.SchemeBuilder.AddToScheme,
+		policyv1beta1.SchemeBuilder.AddToScheme,
+		apisv1beta1.SchemeBuilder.AddToScheme,
+	)
 }
 
 // AddToSchemes may be used to add all resources defined in the project to a Scheme
@@ -34,6 +34,8 @@ const (
 )
 
 // LifecycleRule defines automatic file lifecycle management.
+// +kubebuilder:validation:XValidation:rule="!has(self.daysFromUploadingToHiding) || self.daysFromUploadingToHiding >= 1",message="daysFromUploadingToHiding must be at least 1"
+// +kubebuilder:validation:XValidation:rule="!has(self.daysFromHidingToDeleting) || self.daysFromHidingToDeleting >= 1",message="daysFromHidingToDeleting must be at least 1"
 type LifecycleRule struct {
 	// FileNamePrefix limits the rule to files whose names start with this prefix.
 	// +optional
@@ -52,7 +54,8 @@ type CORSRule struct {
 	CorsRuleName string `json:"corsRuleName"`
 	// AllowedOrigins specifies the allowed origins for CORS requests.
 	AllowedOrigins []string `json:"allowedOrigins"`
-	// AllowedMethods specifies the allowed HTTP methods.
+	// AllowedMethods specifies the B2 operations allowed by this rule, such as
+	// s3_get, s3_head or b2_download_file_by_name.
 	AllowedMethods []string `json:"allowedMethods"`
 	// AllowedHeaders specifies the allowed headers.
 	// +optional
@@ -65,16 +68,156 @@ type CORSRule struct {
 	MaxAgeSeconds *int `json:"maxAgeSeconds,omitempty"`
 }
 
+// Server-side encryption modes.
+const (
+	// SSEModeB2 encrypts objects with keys managed by Backblaze.
+	SSEModeB2 = "SSE-B2"
+	// SSEModeNone disables default encryption.
+	SSEModeNone = "none"
+)
+
+// ServerSideEncryption is a default server-side encryption configuration.
+// SSE-C can't be a bucket default, because the customer supplies the key
+// with every request.
+type ServerSideEncryption struct {
+	// Mode is the encryption mode applied to new objects.
+	// +kubebuilder:validation:Enum=SSE-B2;none
+	Mode string `json:"mode"`
+	// Algorithm is the encryption algorithm. Only AES256 is supported.
+	// +kubebuilder:validation:Enum=AES256
+	// +kubebuilder:default=AES256
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// Default retention modes.
+const (
+	// RetentionModeGovernance allows users with the bypassGovernance
+	// capability to shorten or remove the retention of a file.
+	RetentionModeGovernance = "governance"
+	// RetentionModeCompliance prevents anyone, including the account owner,
+	// from shortening or removing the retention of a file.
+	RetentionModeCompliance = "compliance"
+)
+
+// RetentionPeriod is the length of a retention.
+type RetentionPeriod struct {
+	// Duration is the number of days or years.
+	// +kubebuilder:validation:Minimum=1
+	Duration int `json:"duration"`
+	// Unit is the unit of Duration.
+	// +kubebuilder:validation:Enum=days;years
+	Unit string `json:"unit"`
+}
+
+// DefaultRetention is the retention applied to files uploaded to a bucket
+// with file lock enabled, unless they specify their own.
+type DefaultRetention struct {
+	// Mode is the retention mode.
+	// +kubebuilder:validation:Enum=governance;compliance
+	Mode string `json:"mode"`
+	// Period is how long new files are retained.
+	Period RetentionPeriod `json:"period"`
+}
+
+// ReplicationRule replicates files from a source bucket to a destination
+// bucket.
+// +kubebuilder:validation:XValidation:rule="has(self.destinationBucketId) || has(self.destinationBucketRef)",message="either destinationBucketId or destinationBucketRef must be set"
+type ReplicationRule struct {
+	// Name of the rule. It must be unique within the source bucket.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-]{1,64}$`
+	Name string `json:"name"`
+	// DestinationBucketID is the ID of the bucket files are replicated to.
+	// It may belong to another account.
+	// +optional
+	DestinationBucketID *string `json:"destinationBucketId,omitempty"`
+	// DestinationBucketRef references a Bucket whose ID is used as the
+	// destination. It takes precedence over DestinationBucketID.
+	// +optional
+	DestinationBucketRef *xpv1.Reference `json:"destinationBucketRef,omitempty"`
+	// FileNamePrefix limits the rule to files whose names start with this
+	// prefix.
+	// +optional
+	FileNamePrefix string `json:"fileNamePrefix,omitempty"`
+	// IncludeExistingFiles also replicates files uploaded before the rule
+	// was created.
+	// +optional
+	IncludeExistingFiles bool `json:"includeExistingFiles,omitempty"`
+	// Enabled can be set to false to pause the rule without removing it.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Priority decides which rule applies when the prefixes of several rules
+	// match a file. Higher values take precedence.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +kubebuilder:default=1
+	// +optional
+	Priority int `json:"priority,omitempty"`
+}
+
+// ReplicationSource configures a bucket to replicate its files.
+// +kubebuilder:validation:XValidation:rule="has(self.sourceApplicationKeyId) || has(self.sourceApplicationKeyRef)",message="either sourceApplicationKeyId or sourceApplicationKeyRef must be set"
+type ReplicationSource struct {
+	// SourceApplicationKeyID is the ID of the application key B2 uses to
+	// read files from this bucket. It needs the readFiles, readFileLegalHolds
+	// and readFileRetentions capabilities.
+	// +optional
+	SourceApplicationKeyID *string `json:"sourceApplicationKeyId,omitempty"`
+	// SourceApplicationKeyRef references a User whose application key is
+	// used to read files. It takes precedence over SourceApplicationKeyID.
+	// +optional
+	SourceApplicationKeyRef *xpv1.Reference `json:"sourceApplicationKeyRef,omitempty"`
+	// Rules select the files to replicate and where to.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Rules []ReplicationRule `json:"rules"`
+}
+
+// ReplicationDestination allows a bucket to receive replicated files.
+type ReplicationDestination struct {
+	// SourceToDestinationKeyMapping maps the ID of each source bucket's
+	// replication key to the ID of a key in this account that B2 uses to
+	// write the replicated files.
+	// +kubebuilder:validation:MinProperties=1
+	SourceToDestinationKeyMapping map[string]string `json:"sourceToDestinationKeyMapping"`
+}
+
+// ReplicationConfiguration configures Backblaze Cloud Replication. A bucket
+// can be a replication source, a destination, or both.
+type ReplicationConfiguration struct {
+	// AsSource replicates files from this bucket to other buckets.
+	// +optional
+	AsSource *ReplicationSource `json:"asSource,omitempty"`
+	// AsDestination allows this bucket to receive files replicated from
+	// other buckets.
+	// +optional
+	AsDestination *ReplicationDestination `json:"asDestination,omitempty"`
+}
+
 // BucketParameters are the configurable fields of a Bucket.
+// +kubebuilder:validation:XValidation:rule="(has(self.fileLockEnabled) && self.fileLockEnabled) == (has(oldSelf.fileLockEnabled) && oldSelf.fileLockEnabled)",message="fileLockEnabled can only be set when the bucket is created"
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultRetention) || (has(self.fileLockEnabled) && self.fileLockEnabled)",message="defaultRetention requires fileLockEnabled"
+// +kubebuilder:validation:XValidation:rule="self.bucketName.matches('^[a-zA-Z0-9-]{6,63}$')",message="bucketName must be 6 to 63 letters, digits and hyphens"
+// +kubebuilder:validation:XValidation:rule="!self.bucketName.matches('^[bB]2-')",message="bucketName must not start with b2-, which B2 reserves"
+// +kubebuilder:validation:XValidation:rule="self.bucketName == oldSelf.bucketName",message="bucketName is immutable"
+// +kubebuilder:validation:XValidation:rule="has(self.region) == has(oldSelf.region) && (!has(self.region) || self.region == oldSelf.region)",message="region is immutable"
 type BucketParameters struct {
-	// BucketName is the name of the bucket. Must be globally unique.
+	// BucketName is the name of the bucket. Must be globally unique, 6 to 63
+	// letters, digits and hyphens, and must not start with b2-. To import an
+	// existing bucket, also set the crossplane.io/external-name annotation to
+	// its name.
+	// +kubebuilder:validation:MaxLength=63
 	BucketName string `json:"bucketName"`
 	// BucketType defines the access permissions for the bucket.
 	// +kubebuilder:validation:Enum=allPublic;allPrivate
 	// +kubebuilder:default=allPrivate
 	BucketType string `json:"bucketType,omitempty"`
 	// Region is the Backblaze B2 region where the bucket should be created.
-	// +kubebuilder:default=us-west-001
+	// If unset, the bucket is created in the region of the ProviderConfig.
+	// Buckets can't be moved between regions, so it can't be set, changed or
+	// removed once the Bucket is created.
 	Region string `json:"region,omitempty"`
 	// BucketDeletionPolicy defines how to handle bucket deletion.
 	// +optional
@@ -85,6 +228,66 @@ type BucketParameters struct {
 	// CorsRules define CORS configuration for the bucket.
 	// +optional
 	CorsRules []CORSRule `json:"corsRules,omitempty"`
+	// BucketInfo is custom metadata stored with the bucket, such as
+	// cost-center tags or a Cache-Control default for downloads. It is
+	// merged with any default bucket info from the ProviderConfig, and then
+	// into the bucket's existing bucket info. Entries the provider set that
+	// are no longer desired are removed, and any other bucket info is left
+	// unchanged. B2 allows at most 10 entries in all.
+	// +optional
+	// +kubebuilder:validation:MaxProperties=10
+	BucketInfo map[string]string `json:"bucketInfo,omitempty"`
+	// DefaultServerSideEncryption is applied to objects uploaded without
+	// their own encryption settings. If unset, the bucket's existing
+	// encryption settings are left unchanged.
+	// +optional
+	DefaultServerSideEncryption *ServerSideEncryption `json:"defaultServerSideEncryption,omitempty"`
+	// FileLockEnabled enables file lock (Object Lock), which allows files
+	// to be protected from deletion by a retention period or legal hold.
+	// It can only be enabled when the bucket is created.
+	// +optional
+	FileLockEnabled bool `json:"fileLockEnabled,omitempty"`
+	// DefaultRetention is applied to new files uploaded without their own
+	// retention settings. Requires fileLockEnabled. If unset, the bucket's
+	// existing default retention is left unchanged.
+	// +optional
+	DefaultRetention *DefaultRetention `json:"defaultRetention,omitempty"`
+	// ReplicationConfiguration replaces the bucket's entire replication
+	// configuration. If unset, existing replication is left unchanged.
+	// +optional
+	ReplicationConfiguration *ReplicationConfiguration `json:"replicationConfiguration,omitempty"`
+}
+
+// Replication rule states.
+const (
+	// ReplicationRuleEnabled means B2 is replicating files matching the rule.
+	ReplicationRuleEnabled = "Enabled"
+	// ReplicationRuleDisabled means the rule exists but is paused.
+	ReplicationRuleDisabled = "Disabled"
+)
+
+// ReplicationRuleObservation is the observed state of a replication rule.
+type ReplicationRuleObservation struct {
+	// Name of the rule.
+	Name string `json:"name"`
+	// DestinationBucketID is the bucket files are replicated to.
+	DestinationBucketID string `json:"destinationBucketId,omitempty"`
+	// FileNamePrefix is the prefix of the files the rule replicates.
+	FileNamePrefix string `json:"fileNamePrefix,omitempty"`
+	// Priority of the rule.
+	Priority int `json:"priority,omitempty"`
+	// State is Enabled or Disabled.
+	State string `json:"state"`
+}
+
+// ReplicationObservation is the observed replication configuration.
+type ReplicationObservation struct {
+	// SourceApplicationKeyID is the key B2 uses to read replicated files.
+	SourceApplicationKeyID string `json:"sourceApplicationKeyId,omitempty"`
+	// Rules are the replication rules of the bucket as a source.
+	Rules []ReplicationRuleObservation `json:"rules,omitempty"`
+	// SourceToDestinationKeyMapping is set if the bucket is a destination.
+	SourceToDestinationKeyMapping map[string]string `json:"sourceToDestinationKeyMapping,omitempty"`
 }
 
 // BucketObservation are the observable fields of a Bucket.
@@ -95,13 +298,38 @@ type BucketObservation struct {
 	BucketID string `json:"bucketId,omitempty"`
 	// AccountID is the account that owns the bucket.
 	AccountID string `json:"accountId,omitempty"`
-	// Region is the region where the bucket is located.
+	// Region is the region where the bucket is located, as reported by B2.
 	Region string `json:"region,omitempty"`
+	// BucketType is the observed access permissions of the bucket.
+	BucketType string `json:"bucketType,omitempty"`
+	// BucketInfo is the observed custom metadata of the bucket.
+	BucketInfo map[string]string `json:"bucketInfo,omitempty"`
+	// ManagedBucketInfo are the keys of the bucket info entries set by the
+	// provider. They are removed once no bucket info is desired.
+	ManagedBucketInfo []string `json:"managedBucketInfo,omitempty"`
+	// DefaultServerSideEncryption is the observed default encryption. It is
+	// unset if the provider's key lacks the readBucketEncryption capability.
+	DefaultServerSideEncryption *ServerSideEncryption `json:"defaultServerSideEncryption,omitempty"`
+	// FileLockEnabled is true if file lock is enabled on the bucket.
+	FileLockEnabled bool `json:"fileLockEnabled,omitempty"`
+	// DefaultRetention is the observed default retention. It is unset if
+	// the bucket has none, or if the provider's key lacks the
+	// readBucketRetentions capability.
+	DefaultRetention *DefaultRetention `json:"defaultRetention,omitempty"`
+	// Replication is the observed replication configuration. It is unset if
+	// the bucket has none, or if the provider's key can't read it.
+	Replication *ReplicationObservation `json:"replication,omitempty"`
+	// Revision is the bucket revision, which increases every time the bucket
+	// is updated. Updates are only applied to the revision last observed.
+	Revision int `json:"revision,omitempty"`
 }
 
 // A BucketSpec defines the desired state of a Bucket.
 type BucketSpec struct {
-	DeletionPolicy                   xpv1.DeletionPolicy     `json:"deletionPolicy,omitempty"`
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ManagementPolicies specify the actions the controller may take on the
+	// bucket. Use [Observe] to import an existing bucket without changing it.
+	// +kubebuilder:default={"*"}
 	ManagementPolicies               xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
 	ProviderConfigReference          *xpv1.Reference         `json:"providerConfigReference,omitempty"`
 	WriteConnectionSecretToReference *xpv1.SecretReference   `json:"writeConnectionSecretToRef,omitempty"`
@@ -124,15 +352,32 @@ func (s *BucketStatus) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return xpv1.Condition{Type: ct, Status: corev1.ConditionUnknown}
 }
 
-// SetConditions sets the status conditions.
+// SetConditions sets the supplied conditions, replacing any existing
+// condition of the same type. A condition that has not changed keeps its
+// original LastTransitionTime.
 func (s *BucketStatus) SetConditions(c ...xpv1.Condition) {
-	s.Conditions = c
+	for _, nc := range c {
+		exists := false
+		for i, ec := range s.Conditions {
+			if ec.Type != nc.Type {
+				continue
+			}
+			exists = true
+			if !ec.Equal(nc) {
+				s.Conditions[i] = nc
+			}
+		}
+		if !exists {
+			s.Conditions = append(s.Conditions, nc)
+		}
+	}
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-backblaze-crossplane-io-v1-bucket,mutating=false,failurePolicy=fail,sideEffects=None,groups=backblaze.crossplane.io,resources=buckets,verbs=create;update,versions=v1,name=buckets.backblaze.crossplane.io,admissionReviewVersions=v1
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
@@ -143,7 +388,7 @@ func (s *BucketStatus) SetConditions(c ...xpv1.Condition) {
 // A Bucket is an example API type.
 type Bucket struct {
 	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 	Spec              BucketSpec   `json:"spec"`
 	Status            BucketStatus `json:"status,omitempty"`
 }
@@ -218,3 +463,13 @@ var (
 func (mg *Bucket) GetBucketName() string {
 	return mg.Spec.ForProvider.BucketName
 }
+
+// GetForProvider returns the parameters of the Bucket.
+func (mg *Bucket) GetForProvider() *BucketParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetAtProvider returns the observed state of the Bucket.
+func (mg *Bucket) GetAtProvider() *BucketObservation {
+	return &mg.Status.AtProvider
+}
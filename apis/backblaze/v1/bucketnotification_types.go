@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+)
+
+// NotificationEventType is a type of B2 event that triggers a notification.
+// +kubebuilder:validation:Enum="b2:ObjectCreated:*";"b2:ObjectCreated:Upload";"b2:ObjectCreated:MultipartUpload";"b2:ObjectCreated:Copy";"b2:ObjectCreated:Replica";"b2:ObjectCreated:MultipartReplica";"b2:ObjectDeleted:*";"b2:ObjectDeleted:Delete";"b2:ObjectDeleted:LifecycleRule";"b2:HideMarkerCreated:*";"b2:HideMarkerCreated:Hide";"b2:HideMarkerCreated:LifecycleRule"
+type NotificationEventType string
+
+// BucketNotificationParameters are the configurable fields of a
+// BucketNotification.
+// +kubebuilder:validation:XValidation:rule="has(self.bucketId) || has(self.bucketRef)",message="either bucketId or bucketRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.bucketId) || (has(self.bucketId) && self.bucketId == oldSelf.bucketId)",message="bucketId is immutable"
+type BucketNotificationParameters struct {
+	// BucketID is the ID of the bucket whose events are notified.
+	// +optional
+	BucketID *string `json:"bucketId,omitempty"`
+	// BucketRef references a Bucket whose ID is used. It takes precedence
+	// over BucketID.
+	// +optional
+	BucketRef *xpv1.Reference `json:"bucketRef,omitempty"`
+	// RuleName is the name of the notification rule. It must be unique
+	// within the bucket. Defaults to the name of this resource.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9-]{1,63}$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ruleName is immutable"
+	// +optional
+	RuleName string `json:"ruleName,omitempty"`
+	// EventTypes are the events that trigger a notification.
+	// +kubebuilder:validation:MinItems=1
+	EventTypes []NotificationEventType `json:"eventTypes"`
+	// ObjectNamePrefix limits notifications to objects whose names start
+	// with this prefix.
+	// +optional
+	ObjectNamePrefix string `json:"objectNamePrefix,omitempty"`
+	// URL is the webhook that notifications are posted to.
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
+	// CustomHeadersSecretRef references a Secret whose entries are sent as
+	// HTTP headers with every notification, for example to authenticate
+	// with the webhook. At most 10 headers are supported.
+	// +optional
+	CustomHeadersSecretRef *xpv1.SecretReference `json:"customHeadersSecretRef,omitempty"`
+	// SigningSecretRef selects a 32 character alphanumeric secret. B2 uses
+	// it to sign every notification with HMAC-SHA256, so the webhook can
+	// verify that notifications came from B2.
+	// +optional
+	SigningSecretRef *xpv1.SecretKeySelector `json:"signingSecretRef,omitempty"`
+	// Enabled can be set to false to pause notifications without removing
+	// the rule.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// BucketNotificationObservation are the observable fields of a
+// BucketNotification.
+type BucketNotificationObservation struct {
+	// BucketID is the bucket the rule belongs to.
+	BucketID string `json:"bucketId,omitempty"`
+	// RuleName is the name of the rule.
+	RuleName string `json:"ruleName,omitempty"`
+	// Enabled is true if the rule is enabled.
+	Enabled bool `json:"enabled,omitempty"`
+	// IsSuspended is true if B2 suspended the rule, usually because the
+	// webhook repeatedly failed.
+	IsSuspended bool `json:"isSuspended,omitempty"`
+	// SuspensionReason explains why the rule was suspended.
+	SuspensionReason string `json:"suspensionReason,omitempty"`
+}
+
+// A BucketNotificationSpec defines the desired state of a
+// BucketNotification.
+type BucketNotificationSpec struct {
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ManagementPolicies specify the actions the controller may take on the
+	// notification rule.
+	// +kubebuilder:default={"*"}
+	ManagementPolicies               xpv1.ManagementPolicies      `json:"managementPolicies,omitempty"`
+	ProviderConfigReference          *xpv1.Reference              `json:"providerConfigReference,omitempty"`
+	WriteConnectionSecretToReference *xpv1.SecretReference        `json:"writeConnectionSecretToRef,omitempty"`
+	ForProvider                      BucketNotificationParameters `json:"forProvider"`
+}
+
+// A BucketNotificationStatus represents the observed state of a
+// BucketNotification.
+type BucketNotificationStatus struct {
+	Conditions []xpv1.Condition              `json:"conditions,omitempty"`
+	AtProvider BucketNotificationObservation `json:"atProvider,omitempty"`
+}
+
+// GetCondition returns the status condition by type.
+func (s *BucketNotificationStatus) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	for _, c := range s.Conditions {
+		if c.Type == ct {
+			return c
+		}
+	}
+	return xpv1.Condition{Type: ct, Status: corev1.ConditionUnknown}
+}
+
+// SetConditions sets the supplied conditions, replacing any existing
+// condition of the same type. A condition that has not changed keeps its
+// original LastTransitionTime.
+func (s *BucketNotificationStatus) SetConditions(c ...xpv1.Condition) {
+	for _, nc := range c {
+		exists := false
+		for i, ec := range s.Conditions {
+			if ec.Type != nc.Type {
+				continue
+			}
+			exists = true
+			if !ec.Equal(nc) {
+				s.Conditions[i] = nc
+			}
+		}
+		if !exists {
+			s.Conditions = append(s.Conditions, nc)
+		}
+	}
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-backblaze-crossplane-io-v1-bucketnotification,mutating=false,failurePolicy=fail,sideEffects=None,groups=backblaze.crossplane.io,resources=bucketnotifications,verbs=create;update,versions=v1,name=bucketnotifications.backblaze.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.forProvider.url"
+
+// A BucketNotification is a B2 event notification rule that posts object
+// events to a webhook.
+type BucketNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              BucketNotificationSpec   `json:"spec"`
+	Status            BucketNotificationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BucketNotificationList contains a list of BucketNotification
+type BucketNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+	Items           []BucketNotification `json:"items"`
+}
+
+// GetCondition returns the status condition by type.
+func (n *BucketNotification) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return n.Status.GetCondition(ct)
+}
+
+// SetConditions sets the status conditions.
+func (n *BucketNotification) SetConditions(c ...xpv1.Condition) {
+	n.Status.SetConditions(c...)
+}
+
+// GetDeletionPolicy returns the deletion policy.
+func (n *BucketNotification) GetDeletionPolicy() xpv1.DeletionPolicy {
+	return n.Spec.DeletionPolicy
+}
+
+// SetDeletionPolicy sets the deletion policy.
+func (n *BucketNotification) SetDeletionPolicy(dp xpv1.DeletionPolicy) {
+	n.Spec.DeletionPolicy = dp
+}
+
+// GetManagementPolicies returns the management policies.
+func (n *BucketNotification) GetManagementPolicies() xpv1.ManagementPolicies {
+	return n.Spec.ManagementPolicies
+}
+
+// SetManagementPolicies sets the management policies.
+func (n *BucketNotification) SetManagementPolicies(mp xpv1.ManagementPolicies) {
+	n.Spec.ManagementPolicies = mp
+}
+
+// GetProviderConfigReference returns the provider config reference.
+func (n *BucketNotification) GetProviderConfigReference() *xpv1.Reference {
+	return n.Spec.ProviderConfigReference
+}
+
+// SetProviderConfigReference sets the provider config reference.
+func (n *BucketNotification) SetProviderConfigReference(r *xpv1.Reference) {
+	n.Spec.ProviderConfigReference = r
+}
+
+// GetWriteConnectionSecretToReference returns the write connection secret to reference.
+func (n *BucketNotification) GetWriteConnectionSecretToReference() *xpv1.SecretReference {
+	return n.Spec.WriteConnectionSecretToReference
+}
+
+// SetWriteConnectionSecretToReference sets the write connection secret to reference.
+func (n *BucketNotification) SetWriteConnectionSecretToReference(r *xpv1.SecretReference) {
+	n.Spec.WriteConnectionSecretToReference = r
+}
+
+// BucketNotification type metadata.
+var (
+	BucketNotificationKind             = reflect.TypeOf(BucketNotification{}).Name()
+	BucketNotificationGroupKind        = schema.GroupKind{Group: Group, Kind: BucketNotificationKind}
+	BucketNotificationKindAPIVersion   = BucketNotificationKind + "." + SchemeGroupVersion.String()
+	BucketNotificationGroupVersionKind = SchemeGroupVersion.WithKind(BucketNotificationKind)
+)
+
+// GetRuleName returns the name of the notification rule.
+func (mg *BucketNotification) GetRuleName() string {
+	if mg.Spec.ForProvider.RuleName != "" {
+		return mg.Spec.ForProvider.RuleName
+	}
+	return mg.GetName()
+}
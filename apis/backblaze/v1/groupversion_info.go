@@ -22,6 +22,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -49,6 +50,9 @@ func addKnownTypes(s *runtime.Scheme) error {
 		&BucketList{},
 		&Policy{},
 		&PolicyList{},
+		&BucketNotification{},
+		&BucketNotificationList{},
 	)
+	metav1.AddToGroupVersion(s, SchemeGroupVersion)
 	return nil
 }
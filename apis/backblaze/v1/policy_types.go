@@ -24,6 +24,9 @@ import (
 )
 
 // PolicyParameters are the configurable fields of a Policy.
+// +kubebuilder:validation:XValidation:rule="(has(self.allowBucket) ? 1 : 0) + (has(self.rawPolicy) ? 1 : 0) + (has(self.statements) ? 1 : 0) <= 1",message="only one of allowBucket, rawPolicy and statements may be set"
+// +kubebuilder:validation:XValidation:rule="has(self.allowBucket) || has(self.rawPolicy) || has(self.statements) || has(self.publicReadPrefix) || has(self.readOnlyBucket) || (has(self.denyInsecureTransport) && self.denyInsecureTransport)",message="one of allowBucket, rawPolicy, statements, publicReadPrefix, readOnlyBucket or denyInsecureTransport must be set"
+// +kubebuilder:validation:XValidation:rule="(!has(self.publicReadPrefix) && !(has(self.denyInsecureTransport) && self.denyInsecureTransport)) || has(self.bucket) || has(self.bucketRef) || has(self.allowBucket) || has(self.readOnlyBucket)",message="publicReadPrefix and denyInsecureTransport require bucket, bucketRef, allowBucket or readOnlyBucket"
 type PolicyParameters struct {
 	// PolicyName is the name for this policy.
 	// +optional
@@ -32,13 +35,105 @@ type PolicyParameters struct {
 	// +optional
 	Description *string `json:"description,omitempty"`
 	// AllowBucket creates a simple policy that allows all operations for the specified bucket.
-	// This is mutually exclusive with RawPolicy.
+	// This is mutually exclusive with RawPolicy and Statements.
+	// The generated statement has no principal, so the policy is reported as
+	// invalid and never applied; use Statements with principals instead.
 	// +optional
 	AllowBucket *string `json:"allowBucket,omitempty"`
 	// RawPolicy contains the complete S3-compatible policy document as JSON.
-	// This is mutually exclusive with AllowBucket.
+	// This is mutually exclusive with AllowBucket and Statements.
 	// +optional
 	RawPolicy *string `json:"rawPolicy,omitempty"`
+	// Statements build the policy document from typed statements. This is
+	// mutually exclusive with AllowBucket and RawPolicy.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	Statements []PolicyStatement `json:"statements,omitempty"`
+	// PublicReadPrefix adds a statement that allows everyone to read the
+	// objects of the target bucket whose names start with it. An empty
+	// prefix means all objects. Deleting the Policy revokes this access
+	// unless the bucket policy is orphaned.
+	// +optional
+	PublicReadPrefix *string `json:"publicReadPrefix,omitempty"`
+	// ReadOnlyBucket adds statements that allow ReadOnlyPrincipals to list
+	// the named bucket and read its objects.
+	// +optional
+	ReadOnlyBucket *string `json:"readOnlyBucket,omitempty"`
+	// ReadOnlyPrincipals are the principals ReadOnlyBucket allows. Defaults
+	// to everyone.
+	// +optional
+	ReadOnlyPrincipals []string `json:"readOnlyPrincipals,omitempty"`
+	// DenyInsecureTransport adds a statement that denies all operations on
+	// the target bucket and its objects over plain HTTP.
+	// +optional
+	DenyInsecureTransport *bool `json:"denyInsecureTransport,omitempty"`
+	// Bucket is the name of the bucket the policy applies to. Every resource
+	// of the policy must be this bucket or objects in it. Defaults to
+	// AllowBucket or ReadOnlyBucket.
+	// +optional
+	Bucket *string `json:"bucket,omitempty"`
+	// BucketRef references the Bucket the policy applies to. It takes
+	// precedence over Bucket.
+	// +optional
+	BucketRef *xpv1.Reference `json:"bucketRef,omitempty"`
+}
+
+// Effects of a PolicyStatement.
+const (
+	PolicyEffectAllow = "Allow"
+	PolicyEffectDeny  = "Deny"
+)
+
+// A PolicyStatement allows or denies actions on resources.
+type PolicyStatement struct {
+	// SID identifies the statement within the policy.
+	// +optional
+	SID string `json:"sid,omitempty"`
+	// Effect of the statement.
+	// +kubebuilder:validation:Enum=Allow;Deny
+	// +kubebuilder:default=Allow
+	// +optional
+	Effect string `json:"effect,omitempty"`
+	// Actions the statement applies to, for example s3:GetObject.
+	// +kubebuilder:validation:MinItems=1
+	Actions []string `json:"actions"`
+	// Principals the statement applies to. "*" means everyone.
+	// +optional
+	Principals []string `json:"principals,omitempty"`
+	// Resources the statement applies to.
+	// +kubebuilder:validation:MinItems=1
+	Resources []PolicyResource `json:"resources"`
+	// Conditions that must all hold for the statement to apply.
+	// +optional
+	Conditions []PolicyCondition `json:"conditions,omitempty"`
+}
+
+// A PolicyResource is a bucket, or the objects in it under a prefix.
+// +kubebuilder:validation:XValidation:rule="has(self.bucket) || has(self.bucketRef)",message="either bucket or bucketRef must be set"
+type PolicyResource struct {
+	// Bucket is the name of the bucket.
+	// +optional
+	Bucket *string `json:"bucket,omitempty"`
+	// BucketRef references a Bucket whose name is used. It takes precedence
+	// over Bucket.
+	// +optional
+	BucketRef *xpv1.Reference `json:"bucketRef,omitempty"`
+	// Prefix makes the resource the objects whose names start with it,
+	// rather than the bucket itself. An empty prefix means all objects.
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+}
+
+// A PolicyCondition restricts when a PolicyStatement applies, for example
+// operator Bool, key aws:SecureTransport and values ["true"].
+type PolicyCondition struct {
+	// Operator is the condition operator, for example StringEquals.
+	Operator string `json:"operator"`
+	// Key is the condition key, for example s3:prefix.
+	Key string `json:"key"`
+	// Values the key is compared with.
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
 }
 
 // PolicyObservation are the observable fields of a Policy.
@@ -55,7 +150,13 @@ type PolicyObservation struct {
 
 // A PolicySpec defines the desired state of a Policy.
 type PolicySpec struct {
-	DeletionPolicy                   xpv1.DeletionPolicy     `json:"deletionPolicy,omitempty"`
+	// DeletionPolicy specifies whether the policy put on the bucket is
+	// deleted, or left in place, when the Policy is deleted.
+	// +kubebuilder:default=Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ManagementPolicies specify the actions the controller may take on the
+	// bucket policy. Leave out Delete to keep it when the Policy is deleted.
+	// +kubebuilder:default={"*"}
 	ManagementPolicies               xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
 	ProviderConfigReference          *xpv1.Reference         `json:"providerConfigReference,omitempty"`
 	WriteConnectionSecretToReference *xpv1.SecretReference   `json:"writeConnectionSecretToRef,omitempty"`
@@ -78,15 +179,32 @@ func (s *PolicyStatus) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return xpv1.Condition{Type: ct, Status: corev1.ConditionUnknown}
 }
 
-// SetConditions sets the status conditions.
+// SetConditions sets the supplied conditions, replacing any existing
+// condition of the same type. A condition that has not changed keeps its
+// original LastTransitionTime.
 func (s *PolicyStatus) SetConditions(c ...xpv1.Condition) {
-	s.Conditions = c
+	for _, nc := range c {
+		exists := false
+		for i, ec := range s.Conditions {
+			if ec.Type != nc.Type {
+				continue
+			}
+			exists = true
+			if !ec.Equal(nc) {
+				s.Conditions[i] = nc
+			}
+		}
+		if !exists {
+			s.Conditions = append(s.Conditions, nc)
+		}
+	}
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-backblaze-crossplane-io-v1-policy,mutating=false,failurePolicy=fail,sideEffects=None,groups=backblaze.crossplane.io,resources=policies,verbs=create;update,versions=v1,name=policies.backblaze.crossplane.io,admissionReviewVersions=v1
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
@@ -96,7 +214,7 @@ func (s *PolicyStatus) SetConditions(c ...xpv1.Condition) {
 // A Policy represents a Backblaze B2 S3-compatible policy.
 type Policy struct {
 	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 	Spec              PolicySpec   `json:"spec"`
 	Status            PolicyStatus `json:"status,omitempty"`
 }
@@ -174,3 +292,28 @@ func (mg *Policy) GetPolicyName() string {
 	}
 	return mg.GetName()
 }
+
+// GetForProvider returns the parameters of the Policy.
+func (mg *Policy) GetForProvider() *PolicyParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetAtProvider returns the observed state of the Policy.
+func (mg *Policy) GetAtProvider() *PolicyObservation {
+	return &mg.Status.AtProvider
+}
+
+// GetTargetBucket returns the name of the bucket the policy applies to, or
+// an empty string if it is not known.
+func (p *PolicyParameters) GetTargetBucket() string {
+	if p.Bucket != nil {
+		return *p.Bucket
+	}
+	if p.AllowBucket != nil {
+		return *p.AllowBucket
+	}
+	if p.ReadOnlyBucket != nil {
+		return *p.ReadOnlyBucket
+	}
+	return ""
+}
@@ -14,33 +14,221 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 )
 
+// Capability is a B2 application key capability.
+// +kubebuilder:validation:Enum=listKeys;writeKeys;deleteKeys;listAllBucketNames;listBuckets;readBuckets;writeBuckets;deleteBuckets;readBucketRetentions;writeBucketRetentions;readBucketEncryption;writeBucketEncryption;readBucketReplications;writeBucketReplications;readBucketNotifications;writeBucketNotifications;readBucketLogging;writeBucketLogging;listFiles;readFiles;shareFiles;writeFiles;deleteFiles;readFileLegalHolds;writeFileLegalHolds;readFileRetentions;writeFileRetentions;bypassGovernance
+type Capability string
+
+// Capabilities of B2 application keys.
+const (
+	CapabilityListKeys                 Capability = "listKeys"
+	CapabilityWriteKeys                Capability = "writeKeys"
+	CapabilityDeleteKeys               Capability = "deleteKeys"
+	CapabilityListAllBucketNames       Capability = "listAllBucketNames"
+	CapabilityListBuckets              Capability = "listBuckets"
+	CapabilityReadBuckets              Capability = "readBuckets"
+	CapabilityWriteBuckets             Capability = "writeBuckets"
+	CapabilityDeleteBuckets            Capability = "deleteBuckets"
+	CapabilityReadBucketRetentions     Capability = "readBucketRetentions"
+	CapabilityWriteBucketRetentions    Capability = "writeBucketRetentions"
+	CapabilityReadBucketEncryption     Capability = "readBucketEncryption"
+	CapabilityWriteBucketEncryption    Capability = "writeBucketEncryption"
+	CapabilityReadBucketReplications   Capability = "readBucketReplications"
+	CapabilityWriteBucketReplications  Capability = "writeBucketReplications"
+	CapabilityReadBucketNotifications  Capability = "readBucketNotifications"
+	CapabilityWriteBucketNotifications Capability = "writeBucketNotifications"
+	CapabilityReadBucketLogging        Capability = "readBucketLogging"
+	CapabilityWriteBucketLogging       Capability = "writeBucketLogging"
+	CapabilityListFiles                Capability = "listFiles"
+	CapabilityReadFiles                Capability = "readFiles"
+	CapabilityShareFiles               Capability = "shareFiles"
+	CapabilityWriteFiles               Capability = "writeFiles"
+	CapabilityDeleteFiles              Capability = "deleteFiles"
+	CapabilityReadFileLegalHolds       Capability = "readFileLegalHolds"
+	CapabilityWriteFileLegalHolds      Capability = "writeFileLegalHolds"
+	CapabilityReadFileRetentions       Capability = "readFileRetentions"
+	CapabilityWriteFileRetentions      Capability = "writeFileRetentions"
+	CapabilityBypassGovernance         Capability = "bypassGovernance"
+)
+
+// CapabilityPreset is a named set of capabilities.
+// +kubebuilder:validation:Enum=readOnly;readWrite;backupWriter;admin
+type CapabilityPreset string
+
+// Capability presets.
+const (
+	// CapabilityPresetReadOnly can list and download files.
+	CapabilityPresetReadOnly CapabilityPreset = "readOnly"
+	// CapabilityPresetReadWrite can also upload and delete files.
+	CapabilityPresetReadWrite CapabilityPreset = "readWrite"
+	// CapabilityPresetBackupWriter can upload files and set their
+	// retention, but not delete them.
+	CapabilityPresetBackupWriter CapabilityPreset = "backupWriter"
+	// CapabilityPresetAdmin has every capability.
+	CapabilityPresetAdmin CapabilityPreset = "admin"
+)
+
+// CapabilityPresets are the capabilities each preset expands to.
+var CapabilityPresets = map[CapabilityPreset][]Capability{
+	CapabilityPresetReadOnly: {
+		CapabilityListBuckets, CapabilityReadBuckets, CapabilityListFiles, CapabilityReadFiles,
+	},
+	CapabilityPresetReadWrite: {
+		CapabilityListBuckets, CapabilityReadBuckets, CapabilityListFiles, CapabilityReadFiles,
+		CapabilityWriteFiles, CapabilityDeleteFiles,
+	},
+	CapabilityPresetBackupWriter: {
+		CapabilityListBuckets, CapabilityReadBuckets, CapabilityListFiles, CapabilityReadFiles,
+		CapabilityWriteFiles, CapabilityReadFileRetentions, CapabilityWriteFileRetentions,
+	},
+	CapabilityPresetAdmin: {
+		CapabilityListKeys, CapabilityWriteKeys, CapabilityDeleteKeys,
+		CapabilityListAllBucketNames, CapabilityListBuckets, CapabilityReadBuckets, CapabilityWriteBuckets, CapabilityDeleteBuckets,
+		CapabilityReadBucketRetentions, CapabilityWriteBucketRetentions,
+		CapabilityReadBucketEncryption, CapabilityWriteBucketEncryption,
+		CapabilityReadBucketReplications, CapabilityWriteBucketReplications,
+		CapabilityReadBucketNotifications, CapabilityWriteBucketNotifications,
+		CapabilityReadBucketLogging, CapabilityWriteBucketLogging,
+		CapabilityListFiles, CapabilityReadFiles, CapabilityShareFiles, CapabilityWriteFiles, CapabilityDeleteFiles,
+		CapabilityReadFileLegalHolds, CapabilityWriteFileLegalHolds,
+		CapabilityReadFileRetentions, CapabilityWriteFileRetentions,
+		CapabilityBypassGovernance,
+	},
+}
+
+// Replacement policies of a User.
+const (
+	// ReplacementPolicyReplace creates a new application key when a
+	// parameter that B2 can't change is updated, even if the key was
+	// imported.
+	ReplacementPolicyReplace = "Replace"
+	// ReplacementPolicyReject rejects updates to parameters that B2 can't
+	// change.
+	ReplacementPolicyReject = "Reject"
+)
+
+// Reasons an application key was replaced.
+const (
+	KeyReplacedRotation   = "Rotation"
+	KeyReplacedSpecChange = "SpecChange"
+	KeyReplacedNotFound   = "NotFound"
+)
+
+// Annotations that record an in-flight replacement of the application key of
+// a User until its status does. They are set before the new key is created,
+// so a replacement whose status update is lost can be resumed rather than
+// repeated.
+const (
+	// AnnotationReplacedKeyID is the ID of the key being replaced.
+	AnnotationReplacedKeyID = "backblaze.crossplane.io/replaced-key-id"
+
+	// AnnotationReplacementReason is why the key is being replaced.
+	AnnotationReplacementReason = "backblaze.crossplane.io/replacement-reason"
+)
+
+// TypeSecretUnrecoverable is a warning condition of a User whose application
+// key was imported rather than created, and whose secret therefore isn't held
+// by any of its connection secrets. B2 only returns the secret of a key when it
+// is created.
+const TypeSecretUnrecoverable xpv1.ConditionType = "SecretUnrecoverable"
+
+// KeyRotation configures when the application key of a User is replaced.
+// +kubebuilder:validation:XValidation:rule="has(self.rotateBefore) || has(self.rotationInterval)",message="either rotateBefore or rotationInterval must be set"
+type KeyRotation struct {
+	// RotateBefore rotates the key this long before it expires. Requires
+	// validDurationInSeconds.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+	// RotationInterval rotates the key once it is this old.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+	// GracePeriod is how long a rotated key is kept before it is deleted, so
+	// that workloads have time to pick up the new key from the Secret.
+	// +kubebuilder:default="1h"
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:MaxLength=32
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
 // UserParameters are the configurable fields of a User (Application Key).
+// +kubebuilder:validation:XValidation:rule="!has(self.replacementPolicy) || self.replacementPolicy != 'Reject' || (self.keyName == oldSelf.keyName && has(self.capabilities) == has(oldSelf.capabilities) && (!has(self.capabilities) || (self.capabilities.all(c, c in oldSelf.capabilities) && oldSelf.capabilities.all(c, c in self.capabilities))) && has(self.capabilityPreset) == has(oldSelf.capabilityPreset) && (!has(self.capabilityPreset) || self.capabilityPreset == oldSelf.capabilityPreset) && has(self.bucketId) == has(oldSelf.bucketId) && (!has(self.bucketId) || self.bucketId == oldSelf.bucketId) && has(self.namePrefix) == has(oldSelf.namePrefix) && (!has(self.namePrefix) || self.namePrefix == oldSelf.namePrefix))",message="keyName, capabilities, capabilityPreset, bucketId and namePrefix can't be changed when replacementPolicy is Reject"
+// +kubebuilder:validation:XValidation:rule="!has(self.replacementPolicy) || self.replacementPolicy != 'Reject' || (has(self.bucketRefs) ? (has(oldSelf.bucketRefs) && self.bucketRefs == oldSelf.bucketRefs) : (!has(oldSelf.bucketRefs) && has(self.bucketIds) == has(oldSelf.bucketIds) && (!has(self.bucketIds) || (self.bucketIds.all(b, b in oldSelf.bucketIds) && oldSelf.bucketIds.all(b, b in self.bucketIds)))))",message="bucketIds and bucketRefs can't be changed when replacementPolicy is Reject"
+// +kubebuilder:validation:XValidation:rule="has(self.capabilities) || has(self.capabilityPreset)",message="either capabilities or capabilityPreset must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || !has(self.rotation.rotateBefore) || has(self.validDurationInSeconds)",message="rotation.rotateBefore requires validDurationInSeconds"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || !has(self.rotation.rotateBefore) || !has(self.validDurationInSeconds) || duration(self.rotation.rotateBefore).getSeconds() < self.validDurationInSeconds",message="rotation.rotateBefore must be shorter than validDurationInSeconds"
+// +kubebuilder:validation:XValidation:rule="self.keyName.matches('^[a-zA-Z0-9-]{1,100}$')",message="keyName must be 1 to 100 letters, digits and hyphens"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || ((!has(self.rotation.rotateBefore) || duration(self.rotation.rotateBefore) >= duration('0s')) && (!has(self.rotation.rotationInterval) || duration(self.rotation.rotationInterval) >= duration('0s')) && (!has(self.rotation.gracePeriod) || duration(self.rotation.gracePeriod) >= duration('0s')))",message="rotation durations must not be negative"
 type UserParameters struct {
-	// KeyName is the human-readable name for the application key.
+	// KeyName is the human-readable name for the application key. It may
+	// contain up to 100 letters, digits and hyphens.
+	// +kubebuilder:validation:MaxLength=100
 	KeyName string `json:"keyName"`
-	// Capabilities define what this application key can do.
-	// Available capabilities:
+	// Capabilities define what this application key can do, in addition to
+	// those of CapabilityPreset. For example:
 	// - listKeys, writeKeys, deleteKeys: manage application keys
 	// - listBuckets, writeBuckets: manage buckets
-	// - listFiles, readFiles, shareFiles, writeFiles, deleteFile: manage files
-	Capabilities []string `json:"capabilities"`
+	// - listFiles, readFiles, shareFiles, writeFiles, deleteFiles: manage files
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Capabilities []Capability `json:"capabilities,omitempty"`
+	// CapabilityPreset grants a named set of capabilities: readOnly,
+	// readWrite, backupWriter (upload without delete) or admin.
+	// +optional
+	CapabilityPreset *CapabilityPreset `json:"capabilityPreset,omitempty"`
 	// BucketID restricts the key to operations on this specific bucket only.
+	// Deprecated: Use BucketIDs.
 	// +optional
 	BucketID *string `json:"bucketId,omitempty"`
+	// BucketIDs restrict the key to operations on these buckets only.
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:MaxLength=64
+	// +optional
+	BucketIDs []string `json:"bucketIds,omitempty"`
+	// BucketRefs reference Buckets whose IDs are used. They take precedence
+	// over BucketIDs.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	BucketRefs []xpv1.Reference `json:"bucketRefs,omitempty"`
 	// NamePrefix restricts file operations to files whose names start with this prefix.
 	// +optional
 	NamePrefix *string `json:"namePrefix,omitempty"`
 	// ValidDurationInSeconds sets how long the key will be valid (max 1000 days).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400000
 	// +optional
 	ValidDurationInSeconds *int64 `json:"validDurationInSeconds,omitempty"`
+	// ReplacementPolicy decides what happens when keyName, capabilities,
+	// capabilityPreset, the buckets or namePrefix change, which B2 can't do
+	// to an existing key.
+	// Replace creates a new key, switches the Secret to it and deletes the
+	// old key after the rotation grace period. Reject refuses the change.
+	// If unset, keys the provider created are replaced, while an imported
+	// key is left alone and the User reports that it differs.
+	// +kubebuilder:validation:Enum=Replace;Reject
+	// +optional
+	ReplacementPolicy string `json:"replacementPolicy,omitempty"`
+	// Rotation replaces the application key before it expires or once it
+	// reaches a given age. The Secret is updated with the new key and the old
+	// key is deleted after a grace period.
+	// +optional
+	Rotation *KeyRotation `json:"rotation,omitempty"`
 	// WriteSecretToRef specifies the secret where the application key credentials will be stored.
-	WriteSecretToRef xpv1.SecretReference `json:"writeSecretToRef"`
+	// Deprecated: Use spec.writeConnectionSecretToRef, which also publishes
+	// the endpoint, region and S3-style credential aliases.
+	// +optional
+	WriteSecretToRef *xpv1.SecretReference `json:"writeSecretToRef,omitempty"`
 }
 
 // UserObservation are the observable fields of a User.
@@ -51,17 +239,47 @@ type UserObservation struct {
 	AccountID string `json:"accountId,omitempty"`
 	// Capabilities are the capabilities granted to this key.
 	Capabilities []string `json:"capabilities,omitempty"`
-	// BucketID is the bucket this key is restricted to (if any).
+	// BucketID is the bucket this key is restricted to, if it is restricted
+	// to exactly one.
+	// Deprecated: Use BucketIDs.
 	BucketID *string `json:"bucketId,omitempty"`
+	// BucketIDs are the buckets this key is restricted to (if any).
+	BucketIDs []string `json:"bucketIds,omitempty"`
 	// NamePrefix is the prefix this key is restricted to (if any).
 	NamePrefix *string `json:"namePrefix,omitempty"`
 	// ExpirationTimestamp is when this key will expire (if set).
 	ExpirationTimestamp *int64 `json:"expirationTimestamp,omitempty"`
+	// CreationTime is when the current application key was created.
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+	// RotationHistory lists the keys that have been replaced, oldest first.
+	RotationHistory []RotatedKey `json:"rotationHistory,omitempty"`
+}
+
+// A RotatedKey is an application key that was replaced by another.
+type RotatedKey struct {
+	// ApplicationKeyID is the ID of the replaced key.
+	ApplicationKeyID string `json:"applicationKeyId"`
+	// Reason the key was replaced: Rotation, SpecChange, or NotFound if it
+	// was deleted outside of Crossplane.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// RotationTime is when the key was replaced.
+	RotationTime metav1.Time `json:"rotationTime"`
+	// DeletionTime is when the key is, or was, deleted from B2.
+	DeletionTime metav1.Time `json:"deletionTime"`
+	// Deleted is true once the key has been deleted from B2.
+	// +optional
+	Deleted bool `json:"deleted,omitempty"`
 }
 
 // A UserSpec defines the desired state of a User.
+// +kubebuilder:validation:XValidation:rule="has(self.writeConnectionSecretToRef) || has(self.forProvider.writeSecretToRef) || (has(self.managementPolicies) && self.managementPolicies == ['Observe'])",message="either writeConnectionSecretToRef or forProvider.writeSecretToRef must be set unless the application key is only observed"
 type UserSpec struct {
-	DeletionPolicy                   xpv1.DeletionPolicy     `json:"deletionPolicy,omitempty"`
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ManagementPolicies specify the actions the controller may take on the
+	// application key. Use [Observe] to import an existing key without
+	// changing it or publishing its connection details.
+	// +kubebuilder:default={"*"}
 	ManagementPolicies               xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
 	ProviderConfigReference          *xpv1.Reference         `json:"providerConfigReference,omitempty"`
 	WriteConnectionSecretToReference *xpv1.SecretReference   `json:"writeConnectionSecretToRef,omitempty"`
@@ -84,15 +302,31 @@ func (s *UserStatus) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return xpv1.Condition{Type: ct, Status: corev1.ConditionUnknown}
 }
 
-// SetConditions sets the status conditions.
+// SetConditions sets the supplied conditions, replacing any existing
+// condition of the same type. A condition that has not changed keeps its
+// original LastTransitionTime.
 func (s *UserStatus) SetConditions(c ...xpv1.Condition) {
-	s.Conditions = c
+	for _, nc := range c {
+		exists := false
+		for i, ec := range s.Conditions {
+			if ec.Type != nc.Type {
+				continue
+			}
+			exists = true
+			if !ec.Equal(nc) {
+				s.Conditions[i] = nc
+			}
+		}
+		if !exists {
+			s.Conditions = append(s.Conditions, nc)
+		}
+	}
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-backblaze-crossplane-io-v1-user,mutating=false,failurePolicy=fail,sideEffects=None,groups=backblaze.crossplane.io,resources=users,verbs=create;update,versions=v1,name=users.backblaze.crossplane.io,admissionReviewVersions=v1
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
@@ -100,10 +334,11 @@ func (s *UserStatus) SetConditions(c ...xpv1.Condition) {
 // +kubebuilder:printcolumn:name="KEY NAME",type="string",JSONPath=".spec.forProvider.keyName"
 // +kubebuilder:printcolumn:name="KEY ID",type="string",JSONPath=".status.atProvider.applicationKeyId"
 
-// A User represents a Backblaze B2 application key.
+// A User represents a Backblaze B2 application key. Users are stored as v2
+// Users, and converted to and from them by the conversion webhook.
 type User struct {
 	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 	Spec              UserSpec   `json:"spec"`
 	Status            UserStatus `json:"status,omitempty"`
 }
@@ -172,3 +407,53 @@ func (u *User) SetWriteConnectionSecretToReference(r *xpv1.SecretReference) {
 func (mg *User) GetKeyName() string {
 	return mg.Spec.ForProvider.KeyName
 }
+
+// GetForProvider returns the parameters of the User.
+func (mg *User) GetForProvider() *UserParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetAtProvider returns the observed state of the User.
+func (mg *User) GetAtProvider() *UserObservation {
+	return &mg.Status.AtProvider
+}
+
+// GetCapabilities returns the capabilities of the key, being those of the
+// CapabilityPreset and Capabilities combined, sorted and without duplicates.
+func (p *UserParameters) GetCapabilities() []string {
+	seen := map[Capability]bool{}
+	if p.CapabilityPreset != nil {
+		for _, c := range CapabilityPresets[*p.CapabilityPreset] {
+			seen[c] = true
+		}
+	}
+	for _, c := range p.Capabilities {
+		seen[c] = true
+	}
+
+	caps := make([]string, 0, len(seen))
+	for c := range seen {
+		caps = append(caps, string(c))
+	}
+	sort.Strings(caps)
+	return caps
+}
+
+// GetBucketIDs returns the buckets the key is restricted to, being those of
+// BucketID and BucketIDs combined, sorted and without duplicates.
+func (p *UserParameters) GetBucketIDs() []string {
+	seen := map[string]bool{}
+	if p.BucketID != nil {
+		seen[*p.BucketID] = true
+	}
+	for _, id := range p.BucketIDs {
+		seen[id] = true
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
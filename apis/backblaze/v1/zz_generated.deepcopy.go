@@ -22,6 +22,7 @@ package v1
 
 import (
 	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -84,9 +85,209 @@ func (in *BucketList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotification) DeepCopyInto(out *BucketNotification) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotification.
+func (in *BucketNotification) DeepCopy() *BucketNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BucketNotification) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationList) DeepCopyInto(out *BucketNotificationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BucketNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationList.
+func (in *BucketNotificationList) DeepCopy() *BucketNotificationList {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BucketNotificationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationObservation) DeepCopyInto(out *BucketNotificationObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationObservation.
+func (in *BucketNotificationObservation) DeepCopy() *BucketNotificationObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationParameters) DeepCopyInto(out *BucketNotificationParameters) {
+	*out = *in
+	if in.BucketID != nil {
+		in, out := &in.BucketID, &out.BucketID
+		*out = new(string)
+		**out = **in
+	}
+	if in.BucketRef != nil {
+		in, out := &in.BucketRef, &out.BucketRef
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]NotificationEventType, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomHeadersSecretRef != nil {
+		in, out := &in.CustomHeadersSecretRef, &out.CustomHeadersSecretRef
+		*out = new(v2.SecretReference)
+		**out = **in
+	}
+	if in.SigningSecretRef != nil {
+		in, out := &in.SigningSecretRef, &out.SigningSecretRef
+		*out = new(v2.SecretKeySelector)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationParameters.
+func (in *BucketNotificationParameters) DeepCopy() *BucketNotificationParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationSpec) DeepCopyInto(out *BucketNotificationSpec) {
+	*out = *in
+	if in.ManagementPolicies != nil {
+		in, out := &in.ManagementPolicies, &out.ManagementPolicies
+		*out = make(v2.ManagementPolicies, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderConfigReference != nil {
+		in, out := &in.ProviderConfigReference, &out.ProviderConfigReference
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WriteConnectionSecretToReference != nil {
+		in, out := &in.WriteConnectionSecretToReference, &out.WriteConnectionSecretToReference
+		*out = new(v2.SecretReference)
+		**out = **in
+	}
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationSpec.
+func (in *BucketNotificationSpec) DeepCopy() *BucketNotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotificationStatus) DeepCopyInto(out *BucketNotificationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v2.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.AtProvider = in.AtProvider
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotificationStatus.
+func (in *BucketNotificationStatus) DeepCopy() *BucketNotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BucketObservation) DeepCopyInto(out *BucketObservation) {
 	*out = *in
+	if in.BucketInfo != nil {
+		in, out := &in.BucketInfo, &out.BucketInfo
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ManagedBucketInfo != nil {
+		in, out := &in.ManagedBucketInfo, &out.ManagedBucketInfo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultServerSideEncryption != nil {
+		in, out := &in.DefaultServerSideEncryption, &out.DefaultServerSideEncryption
+		*out = new(ServerSideEncryption)
+		**out = **in
+	}
+	if in.DefaultRetention != nil {
+		in, out := &in.DefaultRetention, &out.DefaultRetention
+		*out = new(DefaultRetention)
+		**out = **in
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationObservation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketObservation.
@@ -116,6 +317,28 @@ func (in *BucketParameters) DeepCopyInto(out *BucketParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BucketInfo != nil {
+		in, out := &in.BucketInfo, &out.BucketInfo
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultServerSideEncryption != nil {
+		in, out := &in.DefaultServerSideEncryption, &out.DefaultServerSideEncryption
+		*out = new(ServerSideEncryption)
+		**out = **in
+	}
+	if in.DefaultRetention != nil {
+		in, out := &in.DefaultRetention, &out.DefaultRetention
+		*out = new(DefaultRetention)
+		**out = **in
+	}
+	if in.ReplicationConfiguration != nil {
+		in, out := &in.ReplicationConfiguration, &out.ReplicationConfiguration
+		*out = new(ReplicationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketParameters.
@@ -169,7 +392,7 @@ func (in *BucketStatus) DeepCopyInto(out *BucketStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketStatus.
@@ -222,6 +445,52 @@ func (in *CORSRule) DeepCopy() *CORSRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultRetention) DeepCopyInto(out *DefaultRetention) {
+	*out = *in
+	out.Period = in.Period
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultRetention.
+func (in *DefaultRetention) DeepCopy() *DefaultRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotation) DeepCopyInto(out *KeyRotation) {
+	*out = *in
+	if in.RotateBefore != nil {
+		in, out := &in.RotateBefore, &out.RotateBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotation.
+func (in *KeyRotation) DeepCopy() *KeyRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LifecycleRule) DeepCopyInto(out *LifecycleRule) {
 	*out = *in
@@ -274,6 +543,26 @@ func (in *Policy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyCondition) DeepCopyInto(out *PolicyCondition) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyCondition.
+func (in *PolicyCondition) DeepCopy() *PolicyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyList) DeepCopyInto(out *PolicyList) {
 	*out = *in
@@ -348,6 +637,43 @@ func (in *PolicyParameters) DeepCopyInto(out *PolicyParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Statements != nil {
+		in, out := &in.Statements, &out.Statements
+		*out = make([]PolicyStatement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PublicReadPrefix != nil {
+		in, out := &in.PublicReadPrefix, &out.PublicReadPrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReadOnlyBucket != nil {
+		in, out := &in.ReadOnlyBucket, &out.ReadOnlyBucket
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReadOnlyPrincipals != nil {
+		in, out := &in.ReadOnlyPrincipals, &out.ReadOnlyPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DenyInsecureTransport != nil {
+		in, out := &in.DenyInsecureTransport, &out.DenyInsecureTransport
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(string)
+		**out = **in
+	}
+	if in.BucketRef != nil {
+		in, out := &in.BucketRef, &out.BucketRef
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyParameters.
@@ -360,6 +686,36 @@ func (in *PolicyParameters) DeepCopy() *PolicyParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyResource) DeepCopyInto(out *PolicyResource) {
+	*out = *in
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(string)
+		**out = **in
+	}
+	if in.BucketRef != nil {
+		in, out := &in.BucketRef, &out.BucketRef
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyResource.
+func (in *PolicyResource) DeepCopy() *PolicyResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 	*out = *in
@@ -391,6 +747,45 @@ func (in *PolicySpec) DeepCopy() *PolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyStatement) DeepCopyInto(out *PolicyStatement) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Principals != nil {
+		in, out := &in.Principals, &out.Principals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]PolicyResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]PolicyCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyStatement.
+func (in *PolicyStatement) DeepCopy() *PolicyStatement {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyStatement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyStatus) DeepCopyInto(out *PolicyStatus) {
 	*out = *in
@@ -414,6 +809,204 @@ func (in *PolicyStatus) DeepCopy() *PolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConfiguration) DeepCopyInto(out *ReplicationConfiguration) {
+	*out = *in
+	if in.AsSource != nil {
+		in, out := &in.AsSource, &out.AsSource
+		*out = new(ReplicationSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AsDestination != nil {
+		in, out := &in.AsDestination, &out.AsDestination
+		*out = new(ReplicationDestination)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConfiguration.
+func (in *ReplicationConfiguration) DeepCopy() *ReplicationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationDestination) DeepCopyInto(out *ReplicationDestination) {
+	*out = *in
+	if in.SourceToDestinationKeyMapping != nil {
+		in, out := &in.SourceToDestinationKeyMapping, &out.SourceToDestinationKeyMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationDestination.
+func (in *ReplicationDestination) DeepCopy() *ReplicationDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationObservation) DeepCopyInto(out *ReplicationObservation) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ReplicationRuleObservation, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceToDestinationKeyMapping != nil {
+		in, out := &in.SourceToDestinationKeyMapping, &out.SourceToDestinationKeyMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationObservation.
+func (in *ReplicationObservation) DeepCopy() *ReplicationObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationRule) DeepCopyInto(out *ReplicationRule) {
+	*out = *in
+	if in.DestinationBucketID != nil {
+		in, out := &in.DestinationBucketID, &out.DestinationBucketID
+		*out = new(string)
+		**out = **in
+	}
+	if in.DestinationBucketRef != nil {
+		in, out := &in.DestinationBucketRef, &out.DestinationBucketRef
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationRule.
+func (in *ReplicationRule) DeepCopy() *ReplicationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationRuleObservation) DeepCopyInto(out *ReplicationRuleObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationRuleObservation.
+func (in *ReplicationRuleObservation) DeepCopy() *ReplicationRuleObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationRuleObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationSource) DeepCopyInto(out *ReplicationSource) {
+	*out = *in
+	if in.SourceApplicationKeyID != nil {
+		in, out := &in.SourceApplicationKeyID, &out.SourceApplicationKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SourceApplicationKeyRef != nil {
+		in, out := &in.SourceApplicationKeyRef, &out.SourceApplicationKeyRef
+		*out = new(v2.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ReplicationRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSource.
+func (in *ReplicationSource) DeepCopy() *ReplicationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPeriod) DeepCopyInto(out *RetentionPeriod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPeriod.
+func (in *RetentionPeriod) DeepCopy() *RetentionPeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotatedKey) DeepCopyInto(out *RotatedKey) {
+	*out = *in
+	in.RotationTime.DeepCopyInto(&out.RotationTime)
+	in.DeletionTime.DeepCopyInto(&out.DeletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotatedKey.
+func (in *RotatedKey) DeepCopy() *RotatedKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RotatedKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSideEncryption) DeepCopyInto(out *ServerSideEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSideEncryption.
+func (in *ServerSideEncryption) DeepCopy() *ServerSideEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSideEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -486,6 +1079,11 @@ func (in *UserObservation) DeepCopyInto(out *UserObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.BucketIDs != nil {
+		in, out := &in.BucketIDs, &out.BucketIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.NamePrefix != nil {
 		in, out := &in.NamePrefix, &out.NamePrefix
 		*out = new(string)
@@ -496,6 +1094,17 @@ func (in *UserObservation) DeepCopyInto(out *UserObservation) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RotationHistory != nil {
+		in, out := &in.RotationHistory, &out.RotationHistory
+		*out = make([]RotatedKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserObservation.
@@ -513,14 +1122,31 @@ func (in *UserParameters) DeepCopyInto(out *UserParameters) {
 	*out = *in
 	if in.Capabilities != nil {
 		in, out := &in.Capabilities, &out.Capabilities
-		*out = make([]string, len(*in))
+		*out = make([]Capability, len(*in))
 		copy(*out, *in)
 	}
+	if in.CapabilityPreset != nil {
+		in, out := &in.CapabilityPreset, &out.CapabilityPreset
+		*out = new(CapabilityPreset)
+		**out = **in
+	}
 	if in.BucketID != nil {
 		in, out := &in.BucketID, &out.BucketID
 		*out = new(string)
 		**out = **in
 	}
+	if in.BucketIDs != nil {
+		in, out := &in.BucketIDs, &out.BucketIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BucketRefs != nil {
+		in, out := &in.BucketRefs, &out.BucketRefs
+		*out = make([]v2.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NamePrefix != nil {
 		in, out := &in.NamePrefix, &out.NamePrefix
 		*out = new(string)
@@ -531,7 +1157,16 @@ func (in *UserParameters) DeepCopyInto(out *UserParameters) {
 		*out = new(int64)
 		**out = **in
 	}
-	out.WriteSecretToRef = in.WriteSecretToRef
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(KeyRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WriteSecretToRef != nil {
+		in, out := &in.WriteSecretToRef, &out.WriteSecretToRef
+		*out = new(v2.SecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserParameters.
@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/utils/ptr"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// AnnotationV1BucketID records the spec.forProvider.bucketId of a v1 User.
+// v2 Users have no bucketId, so it is added to spec.forProvider.bucketIds and
+// restored from there when the User is converted back to v1.
+const AnnotationV1BucketID = "backblaze.crossplane.io/v1-bucket-id"
+
+// ConvertUserToHub converts a v1 User to a v2 User.
+func ConvertUserToHub(_ context.Context, src *backblazev1.User, dst *User) error {
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	removeAnnotation(dst.Annotations, AnnotationV1BucketID, &dst.Annotations)
+
+	in := src.Spec.DeepCopy()
+	p := in.ForProvider
+	dst.Spec = UserSpec{
+		DeletionPolicy:                   in.DeletionPolicy,
+		ManagementPolicies:               in.ManagementPolicies,
+		ProviderConfigReference:          in.ProviderConfigReference,
+		WriteConnectionSecretToReference: in.WriteConnectionSecretToReference,
+		ForProvider: UserParameters{
+			KeyName: p.KeyName,
+			Capabilities: UserCapabilities{
+				Preset: p.CapabilityPreset,
+				Grants: p.Capabilities,
+			},
+			BucketIDs:              p.BucketIDs,
+			BucketRefs:             p.BucketRefs,
+			NamePrefix:             p.NamePrefix,
+			ValidDurationInSeconds: p.ValidDurationInSeconds,
+			ReplacementPolicy:      p.ReplacementPolicy,
+			Rotation:               p.Rotation,
+			WriteSecretToRef:       p.WriteSecretToRef,
+		},
+	}
+	if p.BucketID != nil {
+		dst.Spec.ForProvider.BucketIDs = append([]string{*p.BucketID}, p.BucketIDs...)
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[AnnotationV1BucketID] = *p.BucketID
+	}
+
+	st := src.Status.DeepCopy()
+	o := st.AtProvider
+	dst.Status = UserStatus{
+		AtProvider: UserObservation{
+			ApplicationKeyID:    o.ApplicationKeyID,
+			AccountID:           o.AccountID,
+			Capabilities:        o.Capabilities,
+			BucketIDs:           o.BucketIDs,
+			NamePrefix:          o.NamePrefix,
+			ExpirationTimestamp: o.ExpirationTimestamp,
+			CreationTime:        o.CreationTime,
+			RotationHistory:     o.RotationHistory,
+		},
+	}
+	dst.Status.Conditions = st.Conditions
+	return nil
+}
+
+// ConvertUserFromHub converts a v2 User to a v1 User.
+func ConvertUserFromHub(_ context.Context, src *User, dst *backblazev1.User) error {
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	in := src.Spec.DeepCopy()
+	p := in.ForProvider
+	dst.Spec = backblazev1.UserSpec{
+		DeletionPolicy:                   in.DeletionPolicy,
+		ManagementPolicies:               in.ManagementPolicies,
+		ProviderConfigReference:          in.ProviderConfigReference,
+		WriteConnectionSecretToReference: in.WriteConnectionSecretToReference,
+		ForProvider: backblazev1.UserParameters{
+			KeyName:                p.KeyName,
+			Capabilities:           p.Capabilities.Grants,
+			CapabilityPreset:       p.Capabilities.Preset,
+			BucketIDs:              p.BucketIDs,
+			BucketRefs:             p.BucketRefs,
+			NamePrefix:             p.NamePrefix,
+			ValidDurationInSeconds: p.ValidDurationInSeconds,
+			ReplacementPolicy:      p.ReplacementPolicy,
+			Rotation:               p.Rotation,
+			WriteSecretToRef:       p.WriteSecretToRef,
+		},
+	}
+
+	// Move the bucket that was the v1 bucketId back out of bucketIds, unless
+	// it has since been removed from the v2 User.
+	if id, ok := dst.Annotations[AnnotationV1BucketID]; ok {
+		removeAnnotation(dst.Annotations, AnnotationV1BucketID, &dst.Annotations)
+		fp := &dst.Spec.ForProvider
+		if i := slices.Index(fp.BucketIDs, id); i >= 0 {
+			fp.BucketID = ptr.To(id)
+			fp.BucketIDs = slices.Delete(fp.BucketIDs, i, i+1)
+			if len(fp.BucketIDs) == 0 {
+				fp.BucketIDs = nil
+			}
+		}
+	}
+
+	st := src.Status.DeepCopy()
+	o := st.AtProvider
+	dst.Status = backblazev1.UserStatus{
+		Conditions: st.Conditions,
+		AtProvider: backblazev1.UserObservation{
+			ApplicationKeyID:    o.ApplicationKeyID,
+			AccountID:           o.AccountID,
+			Capabilities:        o.Capabilities,
+			BucketIDs:           o.BucketIDs,
+			NamePrefix:          o.NamePrefix,
+			ExpirationTimestamp: o.ExpirationTimestamp,
+			CreationTime:        o.CreationTime,
+			RotationHistory:     o.RotationHistory,
+		},
+	}
+	// The v1 status.atProvider.bucketId is set when the key is restricted to
+	// exactly one bucket.
+	if len(o.BucketIDs) == 1 {
+		dst.Status.AtProvider.BucketID = ptr.To(o.BucketIDs[0])
+	}
+	return nil
+}
+
+// removeAnnotation removes the annotation k from a, setting *to to nil if no
+// annotations are left.
+func removeAnnotation(a map[string]string, k string, to *map[string]string) {
+	delete(a, k)
+	if len(a) == 0 {
+		*to = nil
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/randfill"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// fuzzIterations is how many random Users each round trip test converts.
+const fuzzIterations = 1000
+
+func TestUserRoundTripFromV1(t *testing.T) {
+	f := randfill.NewWithSeed(1).NilChance(0.3).NumElements(0, 3)
+	for i := range fuzzIterations {
+		in := &backblazev1.User{}
+		f.Fill(in)
+		in.TypeMeta = metav1.TypeMeta{}
+		// status.atProvider.bucketId is derived from bucketIds, so v2 has no
+		// field for it.
+		in.Status.AtProvider.BucketID = nil
+		if len(in.Status.AtProvider.BucketIDs) == 1 {
+			in.Status.AtProvider.BucketID = ptr.To(in.Status.AtProvider.BucketIDs[0])
+		}
+
+		hub := &User{}
+		if err := ConvertUserToHub(context.Background(), in.DeepCopy(), hub); err != nil {
+			t.Fatalf("%d: ConvertUserToHub(...): %v", i, err)
+		}
+		got := &backblazev1.User{}
+		if err := ConvertUserFromHub(context.Background(), hub, got); err != nil {
+			t.Fatalf("%d: ConvertUserFromHub(...): %v", i, err)
+		}
+		if diff := cmp.Diff(in, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("%d: v1 -> v2 -> v1: -want, +got:\n%s", i, diff)
+		}
+	}
+}
+
+func TestUserRoundTripFromV2(t *testing.T) {
+	f := randfill.NewWithSeed(1).NilChance(0.3).NumElements(0, 3)
+	for i := range fuzzIterations {
+		in := &User{}
+		f.Fill(in)
+		in.TypeMeta = metav1.TypeMeta{}
+
+		spoke := &backblazev1.User{}
+		if err := ConvertUserFromHub(context.Background(), in.DeepCopy(), spoke); err != nil {
+			t.Fatalf("%d: ConvertUserFromHub(...): %v", i, err)
+		}
+		got := &User{}
+		if err := ConvertUserToHub(context.Background(), spoke, got); err != nil {
+			t.Fatalf("%d: ConvertUserToHub(...): %v", i, err)
+		}
+		if diff := cmp.Diff(in, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("%d: v2 -> v1 -> v2: -want, +got:\n%s", i, diff)
+		}
+	}
+}
+
+func TestConvertUserBucketID(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		v1     backblazev1.UserParameters
+		v2     UserParameters
+		ann    map[string]string
+	}{
+		"BucketIDOnly": {
+			reason: "A v1 bucketId should become the only v2 bucketIds entry.",
+			v1:     backblazev1.UserParameters{BucketID: ptr.To("b1")},
+			v2:     UserParameters{BucketIDs: []string{"b1"}},
+			ann:    map[string]string{AnnotationV1BucketID: "b1"},
+		},
+		"BucketIDAndBucketIDs": {
+			reason: "A v1 bucketId should be the first v2 bucketIds entry.",
+			v1:     backblazev1.UserParameters{BucketID: ptr.To("b1"), BucketIDs: []string{"b2", "b3"}},
+			v2:     UserParameters{BucketIDs: []string{"b1", "b2", "b3"}},
+			ann:    map[string]string{AnnotationV1BucketID: "b1"},
+		},
+		"BucketIDsOnly": {
+			reason: "v1 bucketIds should be kept as they are.",
+			v1:     backblazev1.UserParameters{BucketIDs: []string{"b2"}},
+			v2:     UserParameters{BucketIDs: []string{"b2"}},
+		},
+		"CapabilityPreset": {
+			reason: "A v1 capabilityPreset and capabilities should become v2 capabilities.",
+			v1: backblazev1.UserParameters{
+				CapabilityPreset: ptr.To(backblazev1.CapabilityPreset("readOnly")),
+				Capabilities:     []backblazev1.Capability{"writeFiles"},
+			},
+			v2: UserParameters{Capabilities: UserCapabilities{
+				Preset: ptr.To(backblazev1.CapabilityPreset("readOnly")),
+				Grants: []backblazev1.Capability{"writeFiles"},
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hub := &User{}
+			in := &backblazev1.User{Spec: backblazev1.UserSpec{ForProvider: tc.v1}}
+			if err := ConvertUserToHub(context.Background(), in, hub); err != nil {
+				t.Fatalf("\n%s\nConvertUserToHub(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.v2, hub.Spec.ForProvider, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nConvertUserToHub(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.ann, hub.GetAnnotations(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nConvertUserToHub(...): -want annotations, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConvertUserFromHubBucketIDRemoved(t *testing.T) {
+	in := &User{}
+	in.SetAnnotations(map[string]string{AnnotationV1BucketID: "b1"})
+	in.Spec.ForProvider.BucketIDs = []string{"b2"}
+
+	got := &backblazev1.User{}
+	if err := ConvertUserFromHub(context.Background(), in, got); err != nil {
+		t.Fatalf("ConvertUserFromHub(...): %v", err)
+	}
+	want := backblazev1.UserParameters{BucketIDs: []string{"b2"}}
+	if diff := cmp.Diff(want, got.Spec.ForProvider, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("ConvertUserFromHub(...): a bucketId removed from v2 bucketIds should not be restored: -want, +got:\n%s", diff)
+	}
+	if got.GetAnnotations() != nil {
+		t.Errorf("ConvertUserFromHub(...): annotations: want nil, got %v", got.GetAnnotations())
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// UserCapabilities are the capabilities of an application key.
+// +kubebuilder:validation:XValidation:rule="has(self.preset) || has(self.grants)",message="either preset or grants must be set"
+type UserCapabilities struct {
+	// Preset grants a named set of capabilities: readOnly, readWrite,
+	// backupWriter (upload without delete) or admin.
+	// +optional
+	Preset *backblazev1.CapabilityPreset `json:"preset,omitempty"`
+	// Grants are capabilities granted in addition to those of the Preset.
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Grants []backblazev1.Capability `json:"grants,omitempty"`
+}
+
+// UserParameters are the configurable fields of a User (Application Key).
+// +kubebuilder:validation:XValidation:rule="!has(self.replacementPolicy) || self.replacementPolicy != 'Reject' || (self.keyName == oldSelf.keyName && has(self.capabilities.grants) == has(oldSelf.capabilities.grants) && (!has(self.capabilities.grants) || (self.capabilities.grants.all(c, c in oldSelf.capabilities.grants) && oldSelf.capabilities.grants.all(c, c in self.capabilities.grants))) && has(self.capabilities.preset) == has(oldSelf.capabilities.preset) && (!has(self.capabilities.preset) || self.capabilities.preset == oldSelf.capabilities.preset) && has(self.namePrefix) == has(oldSelf.namePrefix) && (!has(self.namePrefix) || self.namePrefix == oldSelf.namePrefix))",message="keyName, capabilities and namePrefix can't be changed when replacementPolicy is Reject"
+// +kubebuilder:validation:XValidation:rule="!has(self.replacementPolicy) || self.replacementPolicy != 'Reject' || (has(self.bucketRefs) ? (has(oldSelf.bucketRefs) && self.bucketRefs == oldSelf.bucketRefs) : (!has(oldSelf.bucketRefs) && has(self.bucketIds) == has(oldSelf.bucketIds) && (!has(self.bucketIds) || (self.bucketIds.all(b, b in oldSelf.bucketIds) && oldSelf.bucketIds.all(b, b in self.bucketIds)))))",message="bucketIds and bucketRefs can't be changed when replacementPolicy is Reject"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || !has(self.rotation.rotateBefore) || has(self.validDurationInSeconds)",message="rotation.rotateBefore requires validDurationInSeconds"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || !has(self.rotation.rotateBefore) || !has(self.validDurationInSeconds) || duration(self.rotation.rotateBefore).getSeconds() < self.validDurationInSeconds",message="rotation.rotateBefore must be shorter than validDurationInSeconds"
+// +kubebuilder:validation:XValidation:rule="self.keyName.matches('^[a-zA-Z0-9-]{1,100}$')",message="keyName must be 1 to 100 letters, digits and hyphens"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotation) || ((!has(self.rotation.rotateBefore) || duration(self.rotation.rotateBefore) >= duration('0s')) && (!has(self.rotation.rotationInterval) || duration(self.rotation.rotationInterval) >= duration('0s')) && (!has(self.rotation.gracePeriod) || duration(self.rotation.gracePeriod) >= duration('0s')))",message="rotation durations must not be negative"
+type UserParameters struct {
+	// KeyName is the human-readable name for the application key. It may
+	// contain up to 100 letters, digits and hyphens.
+	// +kubebuilder:validation:MaxLength=100
+	KeyName string `json:"keyName"`
+	// Capabilities define what this application key can do.
+	Capabilities UserCapabilities `json:"capabilities"`
+	// BucketIDs restrict the key to operations on these buckets only.
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:MaxLength=64
+	// +optional
+	BucketIDs []string `json:"bucketIds,omitempty"`
+	// BucketRefs reference Buckets whose IDs are used. They take precedence
+	// over BucketIDs.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	BucketRefs []xpv1.Reference `json:"bucketRefs,omitempty"`
+	// NamePrefix restricts file operations to files whose names start with this prefix.
+	// +optional
+	NamePrefix *string `json:"namePrefix,omitempty"`
+	// ValidDurationInSeconds sets how long the key will be valid (max 1000 days).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400000
+	// +optional
+	ValidDurationInSeconds *int64 `json:"validDurationInSeconds,omitempty"`
+	// ReplacementPolicy decides what happens when keyName, capabilities,
+	// the buckets or namePrefix change, which B2 can't do to an existing key.
+	// Replace creates a new key, switches the Secret to it and deletes the
+	// old key after the rotation grace period. Reject refuses the change.
+	// If unset, keys the provider created are replaced, while an imported
+	// key is left alone and the User reports that it differs.
+	// +kubebuilder:validation:Enum=Replace;Reject
+	// +optional
+	ReplacementPolicy string `json:"replacementPolicy,omitempty"`
+	// Rotation replaces the application key before it expires or once it
+	// reaches a given age. The Secret is updated with the new key and the old
+	// key is deleted after a grace period.
+	// +optional
+	Rotation *backblazev1.KeyRotation `json:"rotation,omitempty"`
+	// WriteSecretToRef specifies the secret where the application key credentials will be stored.
+	// Deprecated: Use spec.writeConnectionSecretToRef, which also publishes
+	// the endpoint, region and S3-style credential aliases.
+	// +optional
+	WriteSecretToRef *xpv1.SecretReference `json:"writeSecretToRef,omitempty"`
+}
+
+// UserObservation are the observable fields of a User.
+type UserObservation struct {
+	// ApplicationKeyID is the ID of the created application key.
+	ApplicationKeyID string `json:"applicationKeyId,omitempty"`
+	// AccountID is the account that owns this application key.
+	AccountID string `json:"accountId,omitempty"`
+	// Capabilities are the capabilities granted to this key.
+	Capabilities []string `json:"capabilities,omitempty"`
+	// BucketIDs are the buckets this key is restricted to (if any).
+	BucketIDs []string `json:"bucketIds,omitempty"`
+	// NamePrefix is the prefix this key is restricted to (if any).
+	NamePrefix *string `json:"namePrefix,omitempty"`
+	// ExpirationTimestamp is when this key will expire (if set).
+	ExpirationTimestamp *int64 `json:"expirationTimestamp,omitempty"`
+	// CreationTime is when the current application key was created.
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+	// RotationHistory lists the keys that have been replaced, oldest first.
+	RotationHistory []backblazev1.RotatedKey `json:"rotationHistory,omitempty"`
+}
+
+// A UserSpec defines the desired state of a User.
+// +kubebuilder:validation:XValidation:rule="has(self.writeConnectionSecretToRef) || has(self.forProvider.writeSecretToRef) || (has(self.managementPolicies) && self.managementPolicies == ['Observe'])",message="either writeConnectionSecretToRef or forProvider.writeSecretToRef must be set unless the application key is only observed"
+type UserSpec struct {
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ManagementPolicies specify the actions the controller may take on the
+	// application key. Use [Observe] to import an existing key without
+	// changing it or publishing its connection details.
+	// +kubebuilder:default={"*"}
+	ManagementPolicies               xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
+	ProviderConfigReference          *xpv1.Reference         `json:"providerConfigRef,omitempty"`
+	WriteConnectionSecretToReference *xpv1.SecretReference   `json:"writeConnectionSecretToRef,omitempty"`
+	ForProvider                      UserParameters          `json:"forProvider"`
+}
+
+// A UserStatus represents the observed state of a User.
+type UserStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	AtProvider             UserObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,backblaze}
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="KEY NAME",type="string",JSONPath=".spec.forProvider.keyName"
+// +kubebuilder:printcolumn:name="KEY ID",type="string",JSONPath=".status.atProvider.applicationKeyId"
+
+// A User represents a Backblaze B2 application key.
+type User struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              UserSpec   `json:"spec"`
+	Status            UserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserList contains a list of User
+type UserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+	Items           []User `json:"items"`
+}
+
+// User type metadata.
+var (
+	UserKind             = reflect.TypeOf(User{}).Name()
+	UserGroupKind        = schema.GroupKind{Group: Group, Kind: UserKind}
+	UserKindAPIVersion   = UserKind + "." + SchemeGroupVersion.String()
+	UserGroupVersionKind = SchemeGroupVersion.WithKind(UserKind)
+)
+
+// GetCondition returns the status condition by type.
+func (u *User) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return u.Status.GetCondition(ct)
+}
+
+// SetConditions sets the status conditions.
+func (u *User) SetConditions(c ...xpv1.Condition) {
+	u.Status.SetConditions(c...)
+}
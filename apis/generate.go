@@ -23,6 +23,9 @@ limitations under the License.
 // Generate deepcopy methodsets and CRD manifests
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:allowDangerousTypes=true output:artifacts:config=../package/crds
 
+// Serve CRDs with more than one version through the conversion webhook
+//go:generate go run -tags generate ../hack/crdconversion ../package/crds
+
 // Generate webhook manifests
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen webhook paths=./... output:artifacts:config=../package/webhook
 
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// A BucketSpec defines the desired state of a namespaced Bucket.
+// +kubebuilder:validation:XValidation:rule="!has(self.providerConfigRef) || self.providerConfigRef.kind == 'ProviderConfig'",message="providerConfigRef.kind must be ProviderConfig"
+type BucketSpec struct {
+	// ManagementPolicies specify the actions the controller may take on the
+	// bucket. Use [Observe] to import an existing bucket without changing
+	// it, and leave out Delete to keep the bucket when the Bucket is deleted.
+	// +kubebuilder:default={"*"}
+	ManagementPolicies xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
+	// ProviderConfigReference names the ProviderConfig, in the namespace of
+	// the Bucket, whose credentials are used to manage the bucket.
+	// +kubebuilder:default={"kind": "ProviderConfig", "name": "default"}
+	ProviderConfigReference *xpv1.ProviderConfigReference `json:"providerConfigRef,omitempty"`
+	// WriteConnectionSecretToReference names a Secret, in the namespace of
+	// the Bucket, that connection details are written to.
+	WriteConnectionSecretToReference *xpv1.LocalSecretReference   `json:"writeConnectionSecretToRef,omitempty"`
+	ForProvider                      backblazev1.BucketParameters `json:"forProvider"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-bucket-backblaze-m-crossplane-io-v1beta1-bucket,mutating=false,failurePolicy=fail,sideEffects=None,groups=bucket.backblaze.m.crossplane.io,resources=buckets,verbs=create;update,versions=v1beta1,name=buckets.bucket.backblaze.m.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="BUCKET NAME",type="string",JSONPath=".spec.forProvider.bucketName"
+// +kubebuilder:printcolumn:name="REGION",type="string",JSONPath=".spec.forProvider.region"
+
+// A Bucket is a Backblaze B2 bucket managed from a namespace. References to
+// other resources, and the ProviderConfig, are resolved in that namespace.
+type Bucket struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              BucketSpec               `json:"spec"`
+	Status            backblazev1.BucketStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BucketList contains a list of Bucket
+type BucketList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+	Items           []Bucket `json:"items"`
+}
+
+// GetCondition returns the status condition by type.
+func (b *Bucket) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return b.Status.GetCondition(ct)
+}
+
+// SetConditions sets the status conditions.
+func (b *Bucket) SetConditions(c ...xpv1.Condition) {
+	b.Status.SetConditions(c...)
+}
+
+// GetManagementPolicies returns the management policies.
+func (b *Bucket) GetManagementPolicies() xpv1.ManagementPolicies {
+	return b.Spec.ManagementPolicies
+}
+
+// SetManagementPolicies sets the management policies.
+func (b *Bucket) SetManagementPolicies(mp xpv1.ManagementPolicies) {
+	b.Spec.ManagementPolicies = mp
+}
+
+// GetProviderConfigReference returns the provider config reference.
+func (b *Bucket) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return b.Spec.ProviderConfigReference
+}
+
+// SetProviderConfigReference sets the provider config reference.
+func (b *Bucket) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	b.Spec.ProviderConfigReference = r
+}
+
+// GetWriteConnectionSecretToReference returns the write connection secret to reference.
+func (b *Bucket) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return b.Spec.WriteConnectionSecretToReference
+}
+
+// SetWriteConnectionSecretToReference sets the write connection secret to reference.
+func (b *Bucket) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	b.Spec.WriteConnectionSecretToReference = r
+}
+
+// GetBucketName returns the bucket name from the Bucket resource.
+func (b *Bucket) GetBucketName() string {
+	return b.Spec.ForProvider.BucketName
+}
+
+// GetForProvider returns the parameters of the Bucket.
+func (b *Bucket) GetForProvider() *backblazev1.BucketParameters {
+	return &b.Spec.ForProvider
+}
+
+// GetAtProvider returns the observed state of the Bucket.
+func (b *Bucket) GetAtProvider() *backblazev1.BucketObservation {
+	return &b.Status.AtProvider
+}
+
+// Bucket type metadata.
+var (
+	BucketKind             = reflect.TypeOf(Bucket{}).Name()
+	BucketGroupKind        = schema.GroupKind{Group: Group, Kind: BucketKind}
+	BucketKindAPIVersion   = BucketKind + "." + SchemeGroupVersion.String()
+	BucketGroupVersionKind = SchemeGroupVersion.WithKind(BucketKind)
+)
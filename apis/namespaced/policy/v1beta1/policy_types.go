@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// A PolicySpec defines the desired state of a namespaced Policy.
+// +kubebuilder:validation:XValidation:rule="!has(self.providerConfigRef) || self.providerConfigRef.kind == 'ProviderConfig'",message="providerConfigRef.kind must be ProviderConfig"
+type PolicySpec struct {
+	// ManagementPolicies specify the actions the controller may take on the
+	// policy.
+	// +kubebuilder:default={"*"}
+	ManagementPolicies xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
+	// ProviderConfigReference names the ProviderConfig, in the namespace of
+	// the Policy, whose credentials are used to manage the policy.
+	// +kubebuilder:default={"kind": "ProviderConfig", "name": "default"}
+	ProviderConfigReference *xpv1.ProviderConfigReference `json:"providerConfigRef,omitempty"`
+	// WriteConnectionSecretToReference names a Secret, in the namespace of
+	// the Policy, that connection details are written to.
+	WriteConnectionSecretToReference *xpv1.LocalSecretReference   `json:"writeConnectionSecretToRef,omitempty"`
+	ForProvider                      backblazev1.PolicyParameters `json:"forProvider"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-policy-backblaze-m-crossplane-io-v1beta1-policy,mutating=false,failurePolicy=fail,sideEffects=None,groups=policy.backblaze.m.crossplane.io,resources=policies,verbs=create;update,versions=v1beta1,name=policies.policy.backblaze.m.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="POLICY NAME",type="string",JSONPath=".status.atProvider.policyName"
+
+// A Policy is a Backblaze B2 S3-compatible policy managed from a namespace.
+// Referenced Buckets, and the ProviderConfig, are resolved in that namespace.
+type Policy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              PolicySpec               `json:"spec"`
+	Status            backblazev1.PolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// PolicyList contains a list of Policy
+type PolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+	Items           []Policy `json:"items"`
+}
+
+// GetCondition returns the status condition by type.
+func (p *Policy) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return p.Status.GetCondition(ct)
+}
+
+// SetConditions sets the status conditions.
+func (p *Policy) SetConditions(c ...xpv1.Condition) {
+	p.Status.SetConditions(c...)
+}
+
+// GetManagementPolicies returns the management policies.
+func (p *Policy) GetManagementPolicies() xpv1.ManagementPolicies {
+	return p.Spec.ManagementPolicies
+}
+
+// SetManagementPolicies sets the management policies.
+func (p *Policy) SetManagementPolicies(mp xpv1.ManagementPolicies) {
+	p.Spec.ManagementPolicies = mp
+}
+
+// GetProviderConfigReference returns the provider config reference.
+func (p *Policy) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return p.Spec.ProviderConfigReference
+}
+
+// SetProviderConfigReference sets the provider config reference.
+func (p *Policy) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	p.Spec.ProviderConfigReference = r
+}
+
+// GetWriteConnectionSecretToReference returns the write connection secret to reference.
+func (p *Policy) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return p.Spec.WriteConnectionSecretToReference
+}
+
+// SetWriteConnectionSecretToReference sets the write connection secret to reference.
+func (p *Policy) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	p.Spec.WriteConnectionSecretToReference = r
+}
+
+// GetPolicyName returns the policy name from the Policy resource.
+func (p *Policy) GetPolicyName() string {
+	if p.Spec.ForProvider.PolicyName != nil {
+		return *p.Spec.ForProvider.PolicyName
+	}
+	return p.GetName()
+}
+
+// GetForProvider returns the parameters of the Policy.
+func (p *Policy) GetForProvider() *backblazev1.PolicyParameters {
+	return &p.Spec.ForProvider
+}
+
+// GetAtProvider returns the observed state of the Policy.
+func (p *Policy) GetAtProvider() *backblazev1.PolicyObservation {
+	return &p.Status.AtProvider
+}
+
+// Policy type metadata.
+var (
+	PolicyKind             = reflect.TypeOf(Policy{}).Name()
+	PolicyGroupKind        = schema.GroupKind{Group: Group, Kind: PolicyKind}
+	PolicyKindAPIVersion   = PolicyKind + "." + SchemeGroupVersion.String()
+	PolicyGroupVersionKind = SchemeGroupVersion.WithKind(PolicyKind)
+)
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// A UserSpec defines the desired state of a namespaced User.
+// +kubebuilder:validation:XValidation:rule="!has(self.providerConfigRef) || self.providerConfigRef.kind == 'ProviderConfig'",message="providerConfigRef.kind must be ProviderConfig"
+// +kubebuilder:validation:XValidation:rule="!has(self.forProvider.writeSecretToRef)",message="forProvider.writeSecretToRef is not supported by namespaced Users: use writeConnectionSecretToRef"
+// +kubebuilder:validation:XValidation:rule="has(self.writeConnectionSecretToRef) || (has(self.managementPolicies) && self.managementPolicies == ['Observe'])",message="writeConnectionSecretToRef must be set unless the application key is only observed"
+type UserSpec struct {
+	// ManagementPolicies specify the actions the controller may take on the
+	// application key. Use [Observe] to import an existing key without
+	// changing it or publishing its connection details.
+	// +kubebuilder:default={"*"}
+	ManagementPolicies xpv1.ManagementPolicies `json:"managementPolicies,omitempty"`
+	// ProviderConfigReference names the ProviderConfig, in the namespace of
+	// the User, whose credentials are used to manage the application key.
+	// +kubebuilder:default={"kind": "ProviderConfig", "name": "default"}
+	ProviderConfigReference *xpv1.ProviderConfigReference `json:"providerConfigRef,omitempty"`
+	// WriteConnectionSecretToReference names the Secret, in the namespace of
+	// the User, that the application key is written to.
+	WriteConnectionSecretToReference *xpv1.LocalSecretReference `json:"writeConnectionSecretToRef,omitempty"`
+	ForProvider                      backblazev1.UserParameters `json:"forProvider"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,backblaze}
+// +kubebuilder:webhook:path=/validate-user-backblaze-m-crossplane-io-v1beta1-user,mutating=false,failurePolicy=fail,sideEffects=None,groups=user.backblaze.m.crossplane.io,resources=users,verbs=create;update,versions=v1beta1,name=users.user.backblaze.m.crossplane.io,admissionReviewVersions=v1
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL NAME",type="string",JSONPath=".metadata.annotations.crossplane.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="KEY NAME",type="string",JSONPath=".spec.forProvider.keyName"
+// +kubebuilder:printcolumn:name="KEY ID",type="string",JSONPath=".status.atProvider.applicationKeyId"
+
+// A User is a Backblaze B2 application key managed from a namespace. Its
+// credentials are written to a Secret in that namespace.
+type User struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              UserSpec               `json:"spec"`
+	Status            backblazev1.UserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// UserList contains a list of User
+type UserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+	Items           []User `json:"items"`
+}
+
+// GetCondition returns the status condition by type.
+func (u *User) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return u.Status.GetCondition(ct)
+}
+
+// SetConditions sets the status conditions.
+func (u *User) SetConditions(c ...xpv1.Condition) {
+	u.Status.SetConditions(c...)
+}
+
+// GetManagementPolicies returns the management policies.
+func (u *User) GetManagementPolicies() xpv1.ManagementPolicies {
+	return u.Spec.ManagementPolicies
+}
+
+// SetManagementPolicies sets the management policies.
+func (u *User) SetManagementPolicies(mp xpv1.ManagementPolicies) {
+	u.Spec.ManagementPolicies = mp
+}
+
+// GetProviderConfigReference returns the provider config reference.
+func (u *User) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return u.Spec.ProviderConfigReference
+}
+
+// SetProviderConfigReference sets the provider config reference.
+func (u *User) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	u.Spec.ProviderConfigReference = r
+}
+
+// GetWriteConnectionSecretToReference returns the write connection secret to reference.
+func (u *User) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return u.Spec.WriteConnectionSecretToReference
+}
+
+// SetWriteConnectionSecretToReference sets the write connection secret to reference.
+func (u *User) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	u.Spec.WriteConnectionSecretToReference = r
+}
+
+// GetKeyName returns the key name from the User resource.
+func (u *User) GetKeyName() string {
+	return u.Spec.ForProvider.KeyName
+}
+
+// GetForProvider returns the parameters of the User.
+func (u *User) GetForProvider() *backblazev1.UserParameters {
+	return &u.Spec.ForProvider
+}
+
+// GetAtProvider returns the observed state of the User.
+func (u *User) GetAtProvider() *backblazev1.UserObservation {
+	return &u.Status.AtProvider
+}
+
+// User type metadata.
+var (
+	UserKind             = reflect.TypeOf(User{}).Name()
+	UserGroupKind        = schema.GroupKind{Group: Group, Kind: UserKind}
+	UserKindAPIVersion   = UserKind + "." + SchemeGroupVersion.String()
+	UserGroupVersionKind = SchemeGroupVersion.WithKind(UserKind)
+)
@@ -21,6 +21,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -43,5 +44,6 @@ func addKnownTypes(s *runtime.Scheme) error {
 		&ProviderConfigUsage{},
 		&ProviderConfigUsageList{},
 	)
+	metav1.AddToGroupVersion(s, SchemeGroupVersion)
 	return nil
 }
@@ -29,6 +29,28 @@ type ProviderConfigSpec struct {
 
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// DefaultBucketInfo configures bucketInfo entries that are added to every
+	// bucket managed using this ProviderConfig.
+	// +optional
+	DefaultBucketInfo *DefaultBucketInfo `json:"defaultBucketInfo,omitempty"`
+}
+
+// DefaultBucketInfo configures bucketInfo entries added to every bucket.
+// Entries set in a Bucket's spec.forProvider.bucketInfo take precedence.
+type DefaultBucketInfo struct {
+	// Tags are added to the bucketInfo of every bucket, e.g. cost-center.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// ClusterName is recorded in the crossplane-cluster bucketInfo entry.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// IncludeResourceName records the namespace and name of the managing
+	// resource in the crossplane-namespace and crossplane-name entries.
+	// +optional
+	IncludeResourceName bool `json:"includeResourceName,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -52,7 +74,7 @@ type ProviderConfigStatus struct {
 // A ProviderConfig configures a Backblaze provider.
 type ProviderConfig struct {
 	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec   ProviderConfigSpec   `json:"spec"`
 	Status ProviderConfigStatus `json:"status,omitempty"`
@@ -32,6 +32,7 @@ import (
 	"github.com/rossigee/provider-backblaze/apis"
 	backblazecontroller "github.com/rossigee/provider-backblaze/internal/controller"
 	"github.com/rossigee/provider-backblaze/internal/features"
+	"github.com/rossigee/provider-backblaze/internal/metrics"
 	"github.com/rossigee/provider-backblaze/internal/tracing"
 	"github.com/rossigee/provider-backblaze/internal/version"
 
@@ -39,6 +40,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 func main() {
@@ -51,8 +53,9 @@ func main() {
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
 
 		_                          = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
-		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Bool()
+		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Deprecated: has no effect, because Crossplane v2 removed external secret stores.").Hidden().Default("false").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Bool()
+		webhookTLSCertDir          = app.Flag("webhook-tls-cert-dir", "The directory of the TLS certificate used by the webhook server. The API server converts Users between versions through its conversion webhook, so it must hold tls.crt and tls.key.").Default("/tmp/k8s-webhook-server/serving-certs").Envar("WEBHOOK_TLS_CERT_DIR").String()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -85,6 +88,9 @@ func main() {
 		"max-reconcile-rate", *maxReconcileRate,
 		"leader-election", *leaderElection,
 		"debug-mode", *debug)
+	if *enableExternalSecretStores {
+		log.Info("Warning: ignoring the deprecated --enable-external-secret-stores flag, because Crossplane v2 removed external secret stores")
+	}
 
 	log.Debug("Detailed startup configuration",
 		"sync-interval", syncInterval.String(),
@@ -101,6 +107,11 @@ func main() {
 		Cache: cache.Options{
 			SyncPeriod: syncInterval,
 		},
+		// Crossplane's webhook Service targets port 9443.
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    9443,
+			CertDir: *webhookTLSCertDir,
+		}),
 		// controller-runtime uses both ConfigMaps and Leases for leader
 		// election by default. Leases expire after 15 seconds, with a
 		// 10 second renewal deadline. We've observed leader loss due to
@@ -116,10 +127,6 @@ func main() {
 
 	// Initialize feature flags
 	featureFlags := &feature.Flags{}
-	if *enableExternalSecretStores {
-		featureFlags.Enable(features.EnableAlphaExternalSecretStores)
-		log.Info("Alpha feature enabled", "flag", features.EnableAlphaExternalSecretStores)
-	}
 	if *enableManagementPolicies {
 		featureFlags.Enable(features.EnableAlphaManagementPolicies)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaManagementPolicies)
@@ -135,6 +142,8 @@ func main() {
 
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Backblaze APIs to scheme")
 	kingpin.FatalIfError(backblazecontroller.Setup(mgr, o), "Cannot setup controllers")
+	kingpin.FatalIfError(metrics.SetupManagedResourceMetrics(mgr), "Cannot setup metrics")
+	kingpin.FatalIfError(backblazecontroller.SetupWebhooks(mgr), "Cannot setup webhooks")
 
 	kingpin.FatalIfError(mgr.AddHealthzCheck("healthz", healthz.Ping), "Cannot add health check")
 	kingpin.FatalIfError(mgr.AddReadyzCheck("readyz", healthz.Ping), "Cannot add ready check")
@@ -0,0 +1,87 @@
+//go:build generate
+// +build generate
+
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command crdconversion sets the conversion strategy of every CRD with more
+// than one version to Webhook. controller-gen has no marker for it. Crossplane
+// fills in the webhook clientConfig when the provider is installed.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const conversion = `  conversion:
+    strategy: Webhook
+    webhook:
+      conversionReviewVersions:
+      - v1
+`
+
+type crd struct {
+	Spec struct {
+		Conversion *struct{}  `json:"conversion"`
+		Versions   []struct{} `json:"versions"`
+	} `json:"spec"`
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: crdconversion <crd directory>")
+		os.Exit(2)
+	}
+	if err := run(os.Args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return errors.Wrap(err, "cannot list CRDs")
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f) //nolint:gosec // Reading generated CRDs is the point.
+		if err != nil {
+			return errors.Wrapf(err, "cannot read %s", f)
+		}
+		c := &crd{}
+		if err := yaml.Unmarshal(b, c); err != nil {
+			return errors.Wrapf(err, "cannot parse %s", f)
+		}
+		if len(c.Spec.Versions) < 2 || c.Spec.Conversion != nil {
+			continue
+		}
+		// controller-gen sorts keys, so conversion belongs first in spec.
+		out := bytes.Replace(b, []byte("\nspec:\n"), []byte("\nspec:\n"+conversion), 1)
+		if bytes.Equal(out, b) {
+			return errors.Errorf("cannot find spec in %s", f)
+		}
+		if err := os.WriteFile(f, out, 0o644); err != nil { //nolint:gosec // CRDs are not secret.
+			return errors.Wrapf(err, "cannot write %s", f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucketpolicy
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errCanonicalize = "cannot canonicalize policy document"
+)
+
+// Canonicalize returns the canonical form of the supplied policy document,
+// so that documents that differ only in formatting, ordering or in writing a
+// single value rather than a list are equal. Actions are lower cased, lists
+// are sorted and deduplicated, a principal of {"AWS": "*"} becomes "*", and
+// statements are sorted.
+func Canonicalize(doc string) (string, error) {
+	d, err := Parse(doc)
+	if err != nil {
+		return "", err
+	}
+
+	stmts := make([]string, 0, len(d.Statement))
+	byJSON := make(map[string]Statement, len(d.Statement))
+	for _, s := range d.Statement {
+		s = canonicalStatement(s)
+		b, err := json.Marshal(s)
+		if err != nil {
+			return "", errors.Wrap(err, errCanonicalize)
+		}
+		if _, ok := byJSON[string(b)]; !ok {
+			stmts = append(stmts, string(b))
+		}
+		byJSON[string(b)] = s
+	}
+	sort.Strings(stmts)
+
+	d.Statement = make([]Statement, 0, len(stmts))
+	for _, s := range stmts {
+		d.Statement = append(d.Statement, byJSON[s])
+	}
+
+	b, err := json.MarshalIndent(d, "", "  ")
+	return string(b), errors.Wrap(err, errCanonicalize)
+}
+
+// Equal returns true if the supplied policy documents have the same
+// canonical form. A document that can't be parsed is only equal to an
+// identical document.
+func Equal(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ca, err := Canonicalize(a)
+	if err != nil {
+		return false
+	}
+	cb, err := Canonicalize(b)
+	return err == nil && ca == cb
+}
+
+func canonicalStatement(s Statement) Statement {
+	actions := make([]string, len(s.Action))
+	for i, a := range s.Action {
+		actions[i] = strings.ToLower(a)
+	}
+	s.Action = SortedSet(actions)
+	s.Resource = SortedSet(s.Resource)
+
+	if s.Principal != nil && !s.Principal.All {
+		p := &Principal{Types: make(map[string]StringList, len(s.Principal.Types))}
+		for t, ids := range s.Principal.Types {
+			p.Types[t] = SortedSet(ids)
+		}
+		if len(p.Types) == 1 && len(p.Types["AWS"]) == 1 && p.Types["AWS"][0] == "*" {
+			p = &Principal{All: true}
+		}
+		s.Principal = p
+	}
+
+	if s.Condition != nil {
+		c := make(map[string]map[string]StringList, len(s.Condition))
+		for op, keys := range s.Condition {
+			c[op] = make(map[string]StringList, len(keys))
+			for k, values := range keys {
+				c[op][k] = SortedSet(values)
+			}
+		}
+		s.Condition = c
+	}
+	return s
+}
+
+// SortedSet returns the supplied strings sorted and without duplicates, or
+// nil if there are none.
+func SortedSet(in []string) StringList {
+	if len(in) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(in))
+	out := make(StringList, 0, len(in))
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Diff returns a human-readable, line based diff of the canonical forms of
+// the supplied policy documents. Lines only in from are prefixed with "-",
+// and lines only in to with "+". Unchanged lines are omitted. A document
+// that can't be parsed is compared as written.
+func Diff(from, to string) string {
+	a := lines(canonicalOrRaw(from))
+	b := lines(canonicalOrRaw(to))
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+				continue
+			}
+			lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "- "+strings.TrimSpace(a[i]))
+			i++
+		default:
+			out = append(out, "+ "+strings.TrimSpace(b[j]))
+			j++
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func canonicalOrRaw(doc string) string {
+	if doc == "" {
+		return ""
+	}
+	c, err := Canonicalize(doc)
+	if err != nil {
+		return doc
+	}
+	return c
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucketpolicy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCanonicalize(t *testing.T) {
+	cases := map[string]struct {
+		doc     string
+		want    string
+		wantErr bool
+	}{
+		"Normalized": {
+			doc: `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Deny","Principal":{"AWS":"*"},"Action":"s3:*","Resource":"arn:aws:s3:::b/*","Condition":{"Bool":{"aws:SecureTransport":"false"}}},
+				{"Effect":"Allow","Principal":{"AWS":["key-b","key-a","key-b"]},"Action":["S3:PutObject","s3:GetObject"],"Resource":["arn:aws:s3:::b/*","arn:aws:s3:::b"]}
+			]}`,
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": {
+        "AWS": [
+          "key-a",
+          "key-b"
+        ]
+      },
+      "Action": [
+        "s3:getobject",
+        "s3:putobject"
+      ],
+      "Resource": [
+        "arn:aws:s3:::b",
+        "arn:aws:s3:::b/*"
+      ]
+    },
+    {
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": [
+        "s3:*"
+      ],
+      "Resource": [
+        "arn:aws:s3:::b/*"
+      ],
+      "Condition": {
+        "Bool": {
+          "aws:SecureTransport": [
+            "false"
+          ]
+        }
+      }
+    }
+  ]
+}`,
+		},
+		"NotParsed": {
+			doc:     `[]`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Canonicalize(tc.doc)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Canonicalize(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Canonicalize(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"Identical": {
+			a:    `not json`,
+			b:    `not json`,
+			want: true,
+		},
+		"ReorderedAndCollapsed": {
+			a:    `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::b/*"]},{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::b"}]}`,
+			b:    `{"Statement":[{"Resource":"arn:aws:s3:::b","Action":["s3:*"],"Effect":"Deny"},{"Effect":"Allow","Action":"s3:getobject","Resource":"arn:aws:s3:::b/*"}]}`,
+			want: true,
+		},
+		"BoolCondition": {
+			a:    `{"Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::b","Condition":{"Bool":{"aws:SecureTransport":false}}}]}`,
+			b:    `{"Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::b","Condition":{"Bool":{"aws:SecureTransport":["false"]}}}]}`,
+			want: true,
+		},
+		"DifferentResource": {
+			a: `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/a*"}]}`,
+			b: `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/b*"}]}`,
+		},
+		"OneNotParsed": {
+			a: `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b"}]}`,
+			b: ``,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Equal(tc.a, tc.b); got != tc.want {
+				t.Errorf("Equal(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := map[string]struct {
+		from, to string
+		want     string
+	}{
+		"Same": {
+			from: `{"Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b"}}`,
+			to:   `{"Statement":[{"Effect":"Allow","Action":["S3:GetObject"],"Resource":["arn:aws:s3:::b"]}]}`,
+			want: "",
+		},
+		"ChangedResource": {
+			from: `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::b/a*","arn:aws:s3:::b/c*"]}]}`,
+			to:   `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::b/b*","arn:aws:s3:::b/c*"]}]}`,
+			want: "- \"arn:aws:s3:::b/a*\",\n+ \"arn:aws:s3:::b/b*\",",
+		},
+		"FromNothing": {
+			from: ``,
+			to:   `{"Statement":[]}`,
+			want: "+ {\n+ \"Statement\": []\n+ }",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Diff(tc.from, tc.to)); diff != "" {
+				t.Errorf("Diff(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
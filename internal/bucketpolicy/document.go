@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bucketpolicy parses and validates S3-compatible bucket policy
+// documents.
+package bucketpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParse = "cannot parse policy document"
+)
+
+// A Document is a parsed bucket policy document. Elements that may be
+// either a single value or a list are always parsed as lists.
+type Document struct {
+	Version   string      `json:"Version,omitempty"`
+	ID        string      `json:"Id,omitempty"`
+	Statement []Statement `json:"Statement"`
+}
+
+// A Statement of a Document. NotPrincipal, NotAction and NotResource are
+// parsed only so that they can be rejected.
+type Statement struct {
+	Sid          string                           `json:"Sid,omitempty"`
+	Effect       string                           `json:"Effect"`
+	Principal    *Principal                       `json:"Principal,omitempty"`
+	NotPrincipal json.RawMessage                  `json:"NotPrincipal,omitempty"`
+	Action       StringList                       `json:"Action,omitempty"`
+	NotAction    json.RawMessage                  `json:"NotAction,omitempty"`
+	Resource     StringList                       `json:"Resource,omitempty"`
+	NotResource  json.RawMessage                  `json:"NotResource,omitempty"`
+	Condition    map[string]map[string]StringList `json:"Condition,omitempty"`
+}
+
+// A Principal is either everyone, written "*", or principals by type, for
+// example {"AWS": ["..."]}.
+type Principal struct {
+	All   bool
+	Types map[string]StringList
+}
+
+// UnmarshalJSON parses "*" or an object of principal types.
+func (p *Principal) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		if s != "*" {
+			return errors.Errorf("principal %q must be \"*\" or an object", s)
+		}
+		p.All = true
+		return nil
+	}
+	return json.Unmarshal(b, &p.Types)
+}
+
+// MarshalJSON writes "*" or the object of principal types.
+func (p Principal) MarshalJSON() ([]byte, error) {
+	if p.All {
+		return json.Marshal("*")
+	}
+	return json.Marshal(p.Types)
+}
+
+// A StringList is a list of strings that may be written as a single string.
+// Booleans and numbers, as condition values are often written, are kept as
+// their string form.
+type StringList []string
+
+// UnmarshalJSON parses a string, boolean or number, or a list of them.
+func (l *StringList) UnmarshalJSON(b []byte) error {
+	var vs []json.RawMessage
+	if err := json.Unmarshal(b, &vs); err != nil {
+		vs = []json.RawMessage{b}
+	}
+	ss := make(StringList, 0, len(vs))
+	for _, v := range vs {
+		s, err := scalarString(v)
+		if err != nil {
+			return err
+		}
+		ss = append(ss, s)
+	}
+	*l = ss
+	return nil
+}
+
+// scalarString returns the string form of a JSON string, boolean or number.
+func scalarString(b []byte) (string, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	}
+	return "", errors.Errorf("%s must be a string, boolean or number", bytes.TrimSpace(b))
+}
+
+// Parse parses the supplied policy document. Its Statement may be a single
+// statement or a list, and unknown elements are an error.
+func Parse(doc string) (*Document, error) {
+	var raw struct {
+		Version   string          `json:"Version,omitempty"`
+		ID        string          `json:"Id,omitempty"`
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := decodeStrict([]byte(doc), &raw); err != nil {
+		return nil, errors.Wrap(err, errParse)
+	}
+
+	d := &Document{Version: raw.Version, ID: raw.ID}
+	stmts := bytes.TrimSpace(raw.Statement)
+	switch {
+	case len(stmts) == 0:
+	case stmts[0] == '{':
+		d.Statement = make([]Statement, 1)
+		if err := decodeStrict(stmts, &d.Statement[0]); err != nil {
+			return nil, errors.Wrap(err, errParse)
+		}
+	default:
+		if err := decodeStrict(stmts, &d.Statement); err != nil {
+			return nil, errors.Wrap(err, errParse)
+		}
+	}
+	return d, nil
+}
+
+// decodeStrict decodes b into v, rejecting unknown fields.
+func decodeStrict(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucketpolicy
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ResourcePrefix is the prefix of the ARN of every bucket and object.
+const ResourcePrefix = "arn:aws:s3:::"
+
+// Versions of the policy language that may be used.
+var supportedVersions = map[string]bool{
+	"":           true,
+	"2012-10-17": true,
+	"2008-10-17": true,
+}
+
+// SupportedActions are the policy actions the B2 S3-compatible API supports,
+// in lower case.
+var SupportedActions = []string{
+	"s3:*",
+	"s3:abortmultipartupload",
+	"s3:bypassgovernanceretention",
+	"s3:deleteobject",
+	"s3:deleteobjectversion",
+	"s3:getbucketlocation",
+	"s3:getobject",
+	"s3:getobjectlegalhold",
+	"s3:getobjectretention",
+	"s3:getobjectversion",
+	"s3:listbucket",
+	"s3:listbucketmultipartuploads",
+	"s3:listbucketversions",
+	"s3:listmultipartuploadparts",
+	"s3:putobject",
+	"s3:putobjectlegalhold",
+	"s3:putobjectretention",
+}
+
+// SupportedConditionKeys are the condition keys the B2 S3-compatible API
+// supports, in lower case.
+var SupportedConditionKeys = []string{
+	"aws:currenttime",
+	"aws:epochtime",
+	"aws:referer",
+	"aws:securetransport",
+	"aws:sourceip",
+	"aws:useragent",
+	"s3:delimiter",
+	"s3:max-keys",
+	"s3:prefix",
+	"s3:x-amz-server-side-encryption",
+}
+
+// bucketPattern matches a B2 bucket name, or a pattern of bucket names.
+var bucketPattern = regexp.MustCompile(`^[a-zA-Z0-9*?-]{1,63}$`)
+
+// A ValidationError lists the problems with a policy document.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid policy: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate returns a *ValidationError listing the problems with the supplied
+// policy document, or nil if there are none. If bucket is not empty every
+// resource must be that bucket or objects in it.
+func Validate(doc, bucket string) error {
+	d, err := Parse(doc)
+	if err != nil {
+		return &ValidationError{Problems: []string{err.Error()}}
+	}
+	return ValidateDocument(d, bucket)
+}
+
+// ValidateDocument is Validate for a parsed document.
+func ValidateDocument(d *Document, bucket string) error {
+	v := &validator{bucket: bucket}
+	if !supportedVersions[d.Version] {
+		v.addf("", "unsupported version %q", d.Version)
+	}
+	if len(d.Statement) == 0 {
+		v.addf("", "at least one statement is required")
+	}
+	for i := range d.Statement {
+		v.statement(i, &d.Statement[i])
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+type validator struct {
+	bucket   string
+	problems []string
+}
+
+func (v *validator) addf(where, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if where != "" {
+		msg = where + ": " + msg
+	}
+	v.problems = append(v.problems, msg)
+}
+
+func (v *validator) statement(i int, s *Statement) {
+	where := fmt.Sprintf("statement %d", i)
+	if s.Sid != "" {
+		where = fmt.Sprintf("statement %q", s.Sid)
+	}
+
+	if s.Effect != "Allow" && s.Effect != "Deny" {
+		v.addf(where, "effect must be Allow or Deny, not %q", s.Effect)
+	}
+	for _, e := range []struct {
+		name string
+		raw  []byte
+	}{{"NotPrincipal", s.NotPrincipal}, {"NotAction", s.NotAction}, {"NotResource", s.NotResource}} {
+		if len(e.raw) > 0 {
+			v.addf(where, "%s is not supported", e.name)
+		}
+	}
+
+	if s.Principal == nil && len(s.NotPrincipal) == 0 {
+		v.addf(where, "a Principal is required")
+	}
+	v.principal(where, s.Principal)
+
+	if len(s.Action) == 0 {
+		v.addf(where, "at least one action is required")
+	}
+	for _, a := range s.Action {
+		if !isSupportedAction(a) {
+			v.addf(where, "action %q is not supported by B2", a)
+		}
+	}
+
+	if len(s.Resource) == 0 {
+		v.addf(where, "at least one resource is required")
+	}
+	for _, r := range s.Resource {
+		v.resource(where, r)
+	}
+
+	for _, op := range sortedKeys(s.Condition) {
+		for _, k := range sortedKeys(s.Condition[op]) {
+			if !contains(SupportedConditionKeys, strings.ToLower(k)) {
+				v.addf(where, "condition key %q of %s is not supported by B2", k, op)
+			}
+		}
+	}
+}
+
+func (v *validator) principal(where string, p *Principal) {
+	if p == nil || p.All {
+		return
+	}
+	for _, t := range sortedKeys(p.Types) {
+		ids := p.Types[t]
+		if t != "AWS" {
+			v.addf(where, "principal type %q is not supported", t)
+			continue
+		}
+		for _, id := range ids {
+			if strings.HasPrefix(id, "arn:") {
+				v.addf(where, "principal %q is an IAM ARN, which B2 does not support", id)
+			}
+		}
+	}
+}
+
+func (v *validator) resource(where, r string) {
+	if !strings.HasPrefix(r, ResourcePrefix) {
+		v.addf(where, "resource %q is not an ARN starting with %s", r, ResourcePrefix)
+		return
+	}
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(r, ResourcePrefix), "/")
+	if !bucketPattern.MatchString(bucket) {
+		v.addf(where, "resource %q does not name a valid bucket", r)
+		return
+	}
+	if v.bucket != "" && bucket != v.bucket {
+		v.addf(where, "resource %q is not in bucket %q", r, v.bucket)
+	}
+}
+
+// isSupportedAction returns true if the supplied action, which may contain
+// wildcards, matches a supported action. Actions are case insensitive.
+func isSupportedAction(action string) bool {
+	a := strings.ToLower(action)
+	if !strings.ContainsAny(a, "*?") {
+		return contains(SupportedActions, a)
+	}
+	for _, s := range SupportedActions {
+		if ok, _ := path.Match(a, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of the supplied map in order, so that problems
+// are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
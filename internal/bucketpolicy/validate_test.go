@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucketpolicy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		doc     string
+		want    *Document
+		wantErr bool
+	}{
+		"ScalarsAndSingleStatement": {
+			doc: `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/*"}}`,
+			want: &Document{
+				Version: "2012-10-17",
+				Statement: []Statement{{
+					Effect:    "Allow",
+					Principal: &Principal{All: true},
+					Action:    StringList{"s3:GetObject"},
+					Resource:  StringList{"arn:aws:s3:::bucket/*"},
+				}},
+			},
+		},
+		"Lists": {
+			doc: `{"Statement":[{"Effect":"Deny","Principal":{"AWS":["a","b"]},"Action":["s3:GetObject","s3:PutObject"],"Resource":["arn:aws:s3:::bucket"],"Condition":{"Bool":{"aws:SecureTransport":"false"}}}]}`,
+			want: &Document{
+				Statement: []Statement{{
+					Effect:    "Deny",
+					Principal: &Principal{Types: map[string]StringList{"AWS": {"a", "b"}}},
+					Action:    StringList{"s3:GetObject", "s3:PutObject"},
+					Resource:  StringList{"arn:aws:s3:::bucket"},
+					Condition: map[string]map[string]StringList{"Bool": {"aws:SecureTransport": {"false"}}},
+				}},
+			},
+		},
+		"BoolAndNumberConditions": {
+			doc: `{"Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::bucket","Condition":{"Bool":{"aws:SecureTransport":false},"NumericLessThan":{"s3:TlsVersion":[1.2,1]}}}]}`,
+			want: &Document{
+				Statement: []Statement{{
+					Effect:   "Deny",
+					Action:   StringList{"s3:*"},
+					Resource: StringList{"arn:aws:s3:::bucket"},
+					Condition: map[string]map[string]StringList{
+						"Bool":            {"aws:SecureTransport": {"false"}},
+						"NumericLessThan": {"s3:TlsVersion": {"1.2", "1"}},
+					},
+				}},
+			},
+		},
+		"ObjectConditionValue": {
+			doc:     `{"Statement":[{"Effect":"Deny","Action":"s3:*","Condition":{"Bool":{"aws:SecureTransport":{"a":"b"}}}}]}`,
+			wantErr: true,
+		},
+		"UnknownElement": {
+			doc:     `{"Statement":[{"Effect":"Allow","Actions":"s3:*"}]}`,
+			wantErr: true,
+		},
+		"NotJSON": {
+			doc:     `{`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.doc)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parse(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Parse(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		doc    string
+		bucket string
+		want   []string
+	}{
+		"Valid": {
+			doc: `{
+				"Version": "2012-10-17",
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": "*",
+					"Action": ["s3:GetObject", "S3:ListBucket", "s3:Get*"],
+					"Resource": ["arn:aws:s3:::my-bucket", "arn:aws:s3:::my-bucket/public/*"],
+					"Condition": {"Bool": {"aws:SecureTransport": "true"}}
+				}]
+			}`,
+			bucket: "my-bucket",
+		},
+		"AnyBucket": {
+			doc: `{"Statement":{"Effect":"Allow","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::other-bucket/*"}}`,
+		},
+		"NotParsed": {
+			doc:  `{"Statement":"nope"}`,
+			want: []string{"cannot parse policy document: json: cannot unmarshal string into Go value of type []bucketpolicy.Statement"},
+		},
+		"NoStatements": {
+			doc:  `{"Version":"2012-10-17","Statement":[]}`,
+			want: []string{"at least one statement is required"},
+		},
+		"UnsupportedElements": {
+			doc: `{"Version":"2020-01-01","Statement":[{"Sid":"x","Effect":"allow","NotAction":"s3:PutObject","NotResource":"arn:aws:s3:::b"}]}`,
+			want: []string{
+				`unsupported version "2020-01-01"`,
+				`statement "x": effect must be Allow or Deny, not "allow"`,
+				`statement "x": NotAction is not supported`,
+				`statement "x": NotResource is not supported`,
+				`statement "x": a Principal is required`,
+				`statement "x": at least one action is required`,
+				`statement "x": at least one resource is required`,
+			},
+		},
+		"UnsupportedActions": {
+			doc: `{"Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:PutBucketPolicy","iam:*","s3:Tag*"],"Resource":"arn:aws:s3:::my-bucket"}]}`,
+			want: []string{
+				`statement 0: action "s3:PutBucketPolicy" is not supported by B2`,
+				`statement 0: action "iam:*" is not supported by B2`,
+				`statement 0: action "s3:Tag*" is not supported by B2`,
+			},
+		},
+		"UnsupportedPrincipals": {
+			doc: `{"Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:root","key-id"],"Service":"s3.amazonaws.com"},"Action":"s3:*","Resource":"arn:aws:s3:::my-bucket"}]}`,
+			want: []string{
+				`statement 0: principal "arn:aws:iam::123456789012:root" is an IAM ARN, which B2 does not support`,
+				`statement 0: principal type "Service" is not supported`,
+			},
+		},
+		"NoPrincipal": {
+			doc:  `{"Statement":[{"Sid":"AllowAll","Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::my-bucket/*"}]}`,
+			want: []string{`statement "AllowAll": a Principal is required`},
+		},
+		"MalformedResources": {
+			doc: `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*","Resource":["my-bucket/*","arn:aws:s3:::bad_bucket","arn:aws:s3:::"]}]}`,
+			want: []string{
+				`statement 0: resource "my-bucket/*" is not an ARN starting with arn:aws:s3:::`,
+				`statement 0: resource "arn:aws:s3:::bad_bucket" does not name a valid bucket`,
+				`statement 0: resource "arn:aws:s3:::" does not name a valid bucket`,
+			},
+		},
+		"OtherBucket": {
+			doc:    `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*","Resource":["arn:aws:s3:::my-bucket/*","arn:aws:s3:::other-bucket/*"]}]}`,
+			bucket: "my-bucket",
+			want:   []string{`statement 0: resource "arn:aws:s3:::other-bucket/*" is not in bucket "my-bucket"`},
+		},
+		"UnsupportedConditionKey": {
+			doc:  `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::my-bucket","Condition":{"StringEquals":{"aws:PrincipalOrgID":"o-123"}}}]}`,
+			want: []string{`statement 0: condition key "aws:PrincipalOrgID" of StringEquals is not supported by B2`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(tc.doc, tc.bucket)
+			var got []string
+			if err != nil {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("Validate(...): want *ValidationError, got %T", err)
+				}
+				got = verr.Problems
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Validate(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,10 +22,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
 
@@ -36,6 +38,7 @@ import (
 	"time"
 
 	v1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/metrics"
 )
 
 const (
@@ -48,13 +51,23 @@ const (
 	DefaultRegion         = "us-west-001"
 	DefaultEndpointFormat = "https://s3.%s.backblazeb2.com"
 
-	// Backblaze B2 Native API constants
+	// Backblaze B2 Native API constants. Keys are created and listed with
+	// v4 of the API, the first to support keys restricted to more than one
+	// bucket through bucketIds.
 	B2AuthorizeAccountURL = "https://api.backblazeb2.com/b2api/v3/b2_authorize_account"
-	B2CreateKeyURL        = "https://api.backblazeb2.com/b2api/v3/b2_create_key"
+	B2CreateKeyURL        = "https://api.backblazeb2.com/b2api/v4/b2_create_key"
 	B2DeleteKeyURL        = "https://api.backblazeb2.com/b2api/v3/b2_delete_key"
-	B2ListKeysURL         = "https://api.backblazeb2.com/b2api/v3/b2_list_keys"
+	B2ListKeysURL         = "https://api.backblazeb2.com/b2api/v4/b2_list_keys"
+
+	// S3ErrorCodeNoSuchBucketPolicy is returned by the S3 API when a bucket
+	// has no policy.
+	S3ErrorCodeNoSuchBucketPolicy = "NoSuchBucketPolicy"
 )
 
+// ErrApplicationKeyNotFound is returned when the requested application key
+// does not exist.
+var ErrApplicationKeyNotFound = errors.New("application key not found")
+
 // BackblazeClient represents a client for Backblaze B2 using S3-compatible API and native B2 API
 type BackblazeClient struct {
 	S3Client *s3.Client
@@ -69,9 +82,46 @@ type BackblazeClient struct {
 	APIURL           string
 	DownloadURL      string
 	AccountID        string
+	Capabilities     []string
 	tokenExpiration  time.Time
 }
 
+// Client is the set of Backblaze B2 operations used by the controllers. It is
+// satisfied by *BackblazeClient and by the in-memory fake used in tests.
+type Client interface {
+	CreateBucket(ctx context.Context, bucketName, bucketType, region string, fileLockEnabled bool) error
+	DeleteBucket(ctx context.Context, bucketName string) error
+	BucketExists(ctx context.Context, bucketName string) (bool, error)
+	GetBucketLocation(ctx context.Context, bucketName string) (string, error)
+	DeleteAllObjectsInBucket(ctx context.Context, bucketName string) error
+	LockedObject(ctx context.Context, bucketName string) (string, error)
+	GetBucket(ctx context.Context, bucketName string) (*B2Bucket, error)
+	GetBucketByID(ctx context.Context, bucketID string) (*B2Bucket, error)
+	UpdateBucket(ctx context.Context, req *B2UpdateBucketRequest) (*B2Bucket, error)
+
+	GetBucketNotificationRules(ctx context.Context, bucketID string) ([]B2NotificationRule, error)
+	SetBucketNotificationRules(ctx context.Context, bucketID string, rules []B2NotificationRule, ifRevisionIs int) ([]B2NotificationRule, error)
+
+	CreateApplicationKey(ctx context.Context, keyName string, capabilities []string, bucketIDs []string, namePrefix string, validDurationInSeconds *int) (*B2CreateKeyResponse, error)
+	DeleteApplicationKey(ctx context.Context, applicationKeyID string) error
+	GetApplicationKey(ctx context.Context, applicationKeyID string) (*B2CreateKeyResponse, error)
+	GetApplicationKeyByName(ctx context.Context, keyName string) (*B2CreateKeyResponse, error)
+	GetApplicationKeysByName(ctx context.Context, keyName string) ([]B2CreateKeyResponse, error)
+	AuthorizedCapabilities(ctx context.Context) ([]string, error)
+
+	GetBucketPolicy(ctx context.Context, bucketName string) (string, error)
+	PutBucketPolicy(ctx context.Context, bucketName, policy string) error
+	DeleteBucketPolicy(ctx context.Context, bucketName string) error
+}
+
+// NewClientFn creates a Client from the supplied configuration.
+type NewClientFn func(cfg Config) (Client, error)
+
+// NewClient creates a Client backed by the real Backblaze B2 APIs.
+func NewClient(cfg Config) (Client, error) {
+	return NewBackblazeClient(cfg)
+}
+
 // Config contains configuration for connecting to Backblaze B2
 type Config struct {
 	ApplicationKeyID string
@@ -106,13 +156,16 @@ func NewBackblazeClient(cfg Config) (*BackblazeClient, error) {
 	s3Client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
 		o.BaseEndpoint = aws.String(endpoint)
 		o.UsePathStyle = true // Required for Backblaze B2
+		o.HTTPClient = &http.Client{
+			Transport: metrics.NewTransport(metrics.APIS3, awshttp.NewBuildableClient().GetTransport()),
+		}
 	})
 
 	return &BackblazeClient{
 		S3Client:         s3Client,
 		Region:           cfg.Region,
 		Endpoint:         endpoint,
-		HTTPClient:       &http.Client{Timeout: 30 * time.Second},
+		HTTPClient:       &http.Client{Timeout: 30 * time.Second, Transport: metrics.NewTransport(metrics.APINative, http.DefaultTransport)},
 		ApplicationKeyID: cfg.ApplicationKeyID,
 		ApplicationKey:   cfg.ApplicationKey,
 	}, nil
@@ -159,11 +212,15 @@ func GetProviderConfig(ctx context.Context, c client.Client, pc *v1beta1.Provide
 	return cfg, nil
 }
 
-// CreateBucket creates a new bucket in Backblaze B2
-func (c *BackblazeClient) CreateBucket(ctx context.Context, bucketName, bucketType, region string) error {
+// CreateBucket creates a new bucket in Backblaze B2. File lock (Object Lock)
+// can only be enabled when the bucket is created.
+func (c *BackblazeClient) CreateBucket(ctx context.Context, bucketName, bucketType, region string, fileLockEnabled bool) error {
 	input := &s3.CreateBucketInput{
 		Bucket: aws.String(bucketName),
 	}
+	if fileLockEnabled {
+		input.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
 
 	// Set the region constraint if different from client region
 	if region != "" && region != c.Region {
@@ -237,6 +294,8 @@ func (c *BackblazeClient) ListBuckets(ctx context.Context) ([]types.Bucket, erro
 
 // DeleteAllObjectsInBucket deletes all objects in a bucket (for DeleteAll policy)
 func (c *BackblazeClient) DeleteAllObjectsInBucket(ctx context.Context, bucketName string) error {
+	defer metrics.StartPurge(bucketName)()
+
 	// List all objects
 	listInput := &s3.ListObjectsV2Input{
 		Bucket: aws.String(bucketName),
@@ -267,10 +326,11 @@ func (c *BackblazeClient) DeleteAllObjectsInBucket(ctx context.Context, bucketNa
 			},
 		}
 
-		_, err = c.S3Client.DeleteObjects(ctx, deleteInput)
+		deleted, err := c.S3Client.DeleteObjects(ctx, deleteInput)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete objects")
 		}
+		metrics.ObservePurged(bucketName, len(deleted.Deleted), len(deleted.Errors))
 
 		// Check if there are more objects to delete
 		if result.IsTruncated == nil || !*result.IsTruncated {
@@ -282,6 +342,60 @@ func (c *BackblazeClient) DeleteAllObjectsInBucket(ctx context.Context, bucketNa
 	return nil
 }
 
+// LockedObject returns the key of an object version in a bucket that is
+// protected by an unexpired retention period or a legal hold, or an empty
+// string if there is none. Such versions can't be deleted until the
+// protection is lifted. The check is bounded: it stops at the first locked
+// version rather than counting them all.
+func (c *BackblazeClient) LockedObject(ctx context.Context, bucketName string) (string, error) {
+	listInput := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucketName),
+	}
+
+	for {
+		result, err := c.S3Client.ListObjectVersions(ctx, listInput)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list object versions")
+		}
+
+		for _, v := range result.Versions {
+			if c.isObjectVersionLocked(ctx, bucketName, v.Key, v.VersionId) {
+				return aws.ToString(v.Key), nil
+			}
+		}
+
+		if result.IsTruncated == nil || !*result.IsTruncated {
+			break
+		}
+		listInput.KeyMarker = result.NextKeyMarker
+		listInput.VersionIdMarker = result.NextVersionIdMarker
+	}
+
+	return "", nil
+}
+
+// isObjectVersionLocked returns true if the object version has an unexpired
+// retention period or a legal hold. The legal hold is only read when the
+// version has no retention settings. Versions whose lock settings can't be
+// read are assumed to be unlocked; B2 will still refuse to delete them.
+func (c *BackblazeClient) isObjectVersionLocked(ctx context.Context, bucketName string, key, versionID *string) bool {
+	retention, err := c.S3Client.GetObjectRetention(ctx, &s3.GetObjectRetentionInput{
+		Bucket:    aws.String(bucketName),
+		Key:       key,
+		VersionId: versionID,
+	})
+	if err == nil && retention.Retention != nil && retention.Retention.RetainUntilDate != nil {
+		return retention.Retention.RetainUntilDate.After(time.Now())
+	}
+
+	hold, err := c.S3Client.GetObjectLegalHold(ctx, &s3.GetObjectLegalHoldInput{
+		Bucket:    aws.String(bucketName),
+		Key:       key,
+		VersionId: versionID,
+	})
+	return err == nil && hold.LegalHold != nil && hold.LegalHold.Status == types.ObjectLockLegalHoldStatusOn
+}
+
 // isNotFoundError checks if an error is a "not found" error
 func isNotFoundError(err error) bool {
 	// This is a simplified check - in production, you'd want more robust error checking
@@ -289,6 +403,33 @@ func isNotFoundError(err error) bool {
 		err.Error() == "NoSuchBucket")
 }
 
+// IsBucketNameTaken returns true if err indicates that a bucket could not be
+// created because the name is already in use by another account. Bucket names
+// are globally unique across all B2 accounts.
+func IsBucketNameTaken(err error) bool {
+	var exists *types.BucketAlreadyExists
+	if errors.As(err, &exists) {
+		return true
+	}
+	var b2err *B2Error
+	return errors.As(err, &b2err) && b2err.Code == B2ErrorCodeDuplicateBucketName
+}
+
+// ErrorCode returns the code of the B2 Native API or S3 API error that err
+// wraps, such as duplicate_bucket_name or NoSuchBucket, or an empty string if
+// it wraps neither.
+func ErrorCode(err error) string {
+	var b2err *B2Error
+	if errors.As(err, &b2err) {
+		return b2err.Code
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
 // GetExternalName extracts the external name from a managed resource
 func GetExternalName(obj resource.Managed) string {
 	return obj.GetAnnotations()[ExternalNameAnnotation]
@@ -317,12 +458,27 @@ type B2AuthorizeAccountRequest struct {
 	ApplicationKey   string `json:"applicationKey"`
 }
 
-// B2AuthorizeAccountResponse represents the response from authorize account
+// B2AuthorizeAccountResponse represents the response from authorize account.
+// The v3 API reports URLs and capabilities under apiInfo.storageApi, and
+// earlier versions at the top level and under allowed.
 type B2AuthorizeAccountResponse struct {
 	AccountID          string `json:"accountId"`
 	AuthorizationToken string `json:"authorizationToken"`
 	APIURL             string `json:"apiUrl"`
 	DownloadURL        string `json:"downloadUrl"`
+	APIInfo            struct {
+		StorageAPI B2StorageAPIInfo `json:"storageApi"`
+	} `json:"apiInfo"`
+	Allowed struct {
+		Capabilities []string `json:"capabilities"`
+	} `json:"allowed"`
+}
+
+// B2StorageAPIInfo describes the storage API an authorized key may use.
+type B2StorageAPIInfo struct {
+	APIURL       string   `json:"apiUrl"`
+	DownloadURL  string   `json:"downloadUrl"`
+	Capabilities []string `json:"capabilities"`
 }
 
 // B2CreateKeyRequest represents the request to create an application key
@@ -331,7 +487,7 @@ type B2CreateKeyRequest struct {
 	Capabilities           []string `json:"capabilities"`
 	KeyName                string   `json:"keyName"`
 	ValidDurationInSeconds *int     `json:"validDurationInSeconds,omitempty"`
-	BucketID               string   `json:"bucketId,omitempty"`
+	BucketIDs              []string `json:"bucketIds,omitempty"`
 	NamePrefix             string   `json:"namePrefix,omitempty"`
 }
 
@@ -343,7 +499,7 @@ type B2CreateKeyResponse struct {
 	Capabilities        []string `json:"capabilities"`
 	AccountID           string   `json:"accountId"`
 	ExpirationTimestamp *int64   `json:"expirationTimestamp,omitempty"`
-	BucketID            string   `json:"bucketId,omitempty"`
+	BucketIDs           []string `json:"bucketIds,omitempty"`
 	NamePrefix          string   `json:"namePrefix,omitempty"`
 }
 
@@ -361,16 +517,10 @@ type B2ListKeysRequest struct {
 
 // B2ListKeysResponse represents the response from list keys
 type B2ListKeysResponse struct {
-	Keys []struct {
-		ApplicationKeyID    string   `json:"applicationKeyId"`
-		KeyName             string   `json:"keyName"`
-		Capabilities        []string `json:"capabilities"`
-		AccountID           string   `json:"accountId"`
-		ExpirationTimestamp *int64   `json:"expirationTimestamp,omitempty"`
-		BucketID            string   `json:"bucketId,omitempty"`
-		NamePrefix          string   `json:"namePrefix,omitempty"`
-	} `json:"keys"`
-	NextApplicationKeyID string `json:"nextApplicationKeyId,omitempty"`
+	// Keys are listed without their ApplicationKey, which B2 only returns
+	// when a key is created.
+	Keys                 []B2CreateKeyResponse `json:"keys"`
+	NextApplicationKeyID string                `json:"nextApplicationKeyId,omitempty"`
 }
 
 // B2 API Methods
@@ -381,7 +531,13 @@ func (c *BackblazeClient) authorizeAccount(ctx context.Context) error {
 	if c.AuthToken != "" && time.Now().Before(c.tokenExpiration) {
 		return nil
 	}
+	err := c.refreshAuthorization(ctx)
+	metrics.ObserveAuthRefresh(err)
+	return err
+}
 
+// refreshAuthorization calls b2_authorize_account to get a new token.
+func (c *BackblazeClient) refreshAuthorization(ctx context.Context) error {
 	req := B2AuthorizeAccountRequest{
 		ApplicationKeyID: c.ApplicationKeyID,
 		ApplicationKey:   c.ApplicationKey,
@@ -421,14 +577,30 @@ func (c *BackblazeClient) authorizeAccount(ctx context.Context) error {
 	c.APIURL = authResp.APIURL
 	c.DownloadURL = authResp.DownloadURL
 	c.AccountID = authResp.AccountID
+	c.Capabilities = authResp.Allowed.Capabilities
+	if storage := authResp.APIInfo.StorageAPI; storage.APIURL != "" {
+		c.APIURL = storage.APIURL
+		c.DownloadURL = storage.DownloadURL
+		c.Capabilities = storage.Capabilities
+	}
 	// B2 tokens typically last 24 hours, but we'll refresh after 12 hours to be safe
 	c.tokenExpiration = time.Now().Add(12 * time.Hour)
 
 	return nil
 }
 
-// CreateApplicationKey creates a new application key in Backblaze B2
-func (c *BackblazeClient) CreateApplicationKey(ctx context.Context, keyName string, capabilities []string, bucketID, namePrefix string, validDurationInSeconds *int) (*B2CreateKeyResponse, error) {
+// AuthorizedCapabilities returns the capabilities of the key the client is
+// authorized with. A key can only create keys with capabilities it holds.
+func (c *BackblazeClient) AuthorizedCapabilities(ctx context.Context) ([]string, error) {
+	if err := c.authorizeAccount(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to authorize account")
+	}
+	return c.Capabilities, nil
+}
+
+// CreateApplicationKey creates a new application key in Backblaze B2. A key
+// with bucketIDs can only access those buckets.
+func (c *BackblazeClient) CreateApplicationKey(ctx context.Context, keyName string, capabilities []string, bucketIDs []string, namePrefix string, validDurationInSeconds *int) (*B2CreateKeyResponse, error) {
 	if err := c.authorizeAccount(ctx); err != nil {
 		return nil, errors.Wrap(err, "failed to authorize account")
 	}
@@ -438,7 +610,7 @@ func (c *BackblazeClient) CreateApplicationKey(ctx context.Context, keyName stri
 		KeyName:                keyName,
 		Capabilities:           capabilities,
 		ValidDurationInSeconds: validDurationInSeconds,
-		BucketID:               bucketID,
+		BucketIDs:              bucketIDs,
 		NamePrefix:             namePrefix,
 	}
 
@@ -517,71 +689,118 @@ func (c *BackblazeClient) DeleteApplicationKey(ctx context.Context, applicationK
 
 // GetApplicationKey retrieves an application key by ID from Backblaze B2
 func (c *BackblazeClient) GetApplicationKey(ctx context.Context, applicationKeyID string) (*B2CreateKeyResponse, error) {
+	var found *B2CreateKeyResponse
+	err := c.listApplicationKeys(ctx, func(key *B2CreateKeyResponse) bool {
+		if key.ApplicationKeyID != applicationKeyID {
+			return true
+		}
+		found = key
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrApplicationKeyNotFound
+	}
+	return found, nil
+}
+
+// GetApplicationKeyByName retrieves the application key with the supplied
+// name from Backblaze B2. Key names need not be unique, so it is an error for
+// more than one key to have the name.
+func (c *BackblazeClient) GetApplicationKeyByName(ctx context.Context, keyName string) (*B2CreateKeyResponse, error) {
+	found, err := c.GetApplicationKeysByName(ctx, keyName)
+	if err != nil {
+		return nil, err
+	}
+	switch len(found) {
+	case 0:
+		return nil, ErrApplicationKeyNotFound
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, errors.Errorf("%d application keys are named %q", len(found), keyName)
+	}
+}
+
+// GetApplicationKeysByName retrieves all the application keys with the
+// supplied name from Backblaze B2.
+func (c *BackblazeClient) GetApplicationKeysByName(ctx context.Context, keyName string) ([]B2CreateKeyResponse, error) {
+	var found []B2CreateKeyResponse
+	err := c.listApplicationKeys(ctx, func(key *B2CreateKeyResponse) bool {
+		if key.KeyName == keyName {
+			found = append(found, *key)
+		}
+		return true
+	})
+	return found, err
+}
+
+// listApplicationKeys calls fn with each application key of the account, a
+// page at a time, until fn returns false or there are no more keys.
+func (c *BackblazeClient) listApplicationKeys(ctx context.Context, fn func(key *B2CreateKeyResponse) bool) error {
 	if err := c.authorizeAccount(ctx); err != nil {
-		return nil, errors.Wrap(err, "failed to authorize account")
+		return errors.Wrap(err, "failed to authorize account")
 	}
 
 	req := B2ListKeysRequest{
 		AccountID:   c.AccountID,
-		MaxKeyCount: 100, // We'll search through keys
+		MaxKeyCount: 100,
 	}
 
 	for {
-		reqBody, err := json.Marshal(req)
+		listResp, err := c.listKeysPage(ctx, req)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to marshal list keys request")
+			return err
 		}
 
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", B2ListKeysURL, bytes.NewBuffer(reqBody))
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to create HTTP request")
+		for i := range listResp.Keys {
+			if !fn(&listResp.Keys[i]) {
+				return nil
+			}
 		}
 
-		httpReq.Header.Set("Authorization", c.AuthToken)
-		httpReq.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.HTTPClient.Do(httpReq)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to execute HTTP request")
+		if listResp.NextApplicationKeyID == "" {
+			return nil
 		}
-		defer func() {
-			_ = resp.Body.Close()
-		}()
+		req.StartApplicationKeyID = listResp.NextApplicationKeyID
+	}
+}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, errors.Errorf("list keys failed with status %d: %s", resp.StatusCode, string(body))
-		}
+// listKeysPage returns a single page of application keys.
+func (c *BackblazeClient) listKeysPage(ctx context.Context, req B2ListKeysRequest) (*B2ListKeysResponse, error) {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal list keys request")
+	}
 
-		var listResp B2ListKeysResponse
-		if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-			return nil, errors.Wrap(err, "failed to decode list keys response")
-		}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", B2ListKeysURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create HTTP request")
+	}
 
-		// Search for the key in the current batch
-		for _, key := range listResp.Keys {
-			if key.ApplicationKeyID == applicationKeyID {
-				return &B2CreateKeyResponse{
-					ApplicationKeyID:    key.ApplicationKeyID,
-					ApplicationKey:      "", // Not returned in list operations for security
-					KeyName:             key.KeyName,
-					Capabilities:        key.Capabilities,
-					AccountID:           key.AccountID,
-					ExpirationTimestamp: key.ExpirationTimestamp,
-					BucketID:            key.BucketID,
-					NamePrefix:          key.NamePrefix,
-				}, nil
-			}
-		}
+	httpReq.Header.Set("Authorization", c.AuthToken)
+	httpReq.Header.Set("Content-Type", "application/json")
 
-		// If there are more keys to check, continue
-		if listResp.NextApplicationKeyID == "" {
-			break
-		}
-		req.StartApplicationKeyID = listResp.NextApplicationKeyID
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute HTTP request")
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 
-	return nil, errors.New("application key not found")
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("list keys failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var listResp B2ListKeysResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode list keys response")
+	}
+	return &listResp, nil
 }
 
 // S3 Bucket Policy Methods
@@ -594,14 +813,11 @@ func (c *BackblazeClient) GetBucketPolicy(ctx context.Context, bucketName string
 
 	result, err := c.S3Client.GetBucketPolicy(ctx, input)
 	if err != nil {
-		if isNotFoundError(err) || err.Error() == "NoSuchBucketPolicy" {
-			return "", errors.New("bucket policy not found")
-		}
 		return "", errors.Wrap(err, "failed to get bucket policy")
 	}
 
 	if result.Policy == nil {
-		return "", errors.New("bucket policy not found")
+		return "", &smithy.GenericAPIError{Code: S3ErrorCodeNoSuchBucketPolicy, Message: "The bucket policy does not exist"}
 	}
 
 	return *result.Policy, nil
@@ -630,9 +846,6 @@ func (c *BackblazeClient) DeleteBucketPolicy(ctx context.Context, bucketName str
 
 	_, err := c.S3Client.DeleteBucketPolicy(ctx, input)
 	if err != nil {
-		if isNotFoundError(err) || err.Error() == "NoSuchBucketPolicy" {
-			return errors.New("bucket policy not found")
-		}
 		return errors.Wrap(err, "failed to delete bucket policy")
 	}
 
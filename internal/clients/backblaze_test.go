@@ -17,7 +17,20 @@ limitations under the License.
 package clients
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
 )
 
 func TestNewBackblazeClient(t *testing.T) {
@@ -272,3 +285,254 @@ func TestGetProviderConfig(t *testing.T) {
 	// In a real test, we'd mock the Kubernetes client and secret
 	t.Skip("Integration test - requires Kubernetes client mocking")
 }
+
+// rewriteTransport sends every request to the supplied server, whatever the
+// host of its URL.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a client that is already authorized, and that sends
+// its B2 API requests to the supplied handler.
+func newTestClient(t *testing.T, h http.HandlerFunc) *BackblazeClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	return &BackblazeClient{
+		HTTPClient:      &http.Client{Transport: rewriteTransport{target: target}},
+		AuthToken:       "test-token",
+		AccountID:       "test-account",
+		tokenExpiration: time.Now().Add(time.Hour),
+	}
+}
+
+// newTestKeysClient returns a client that is already authorized, and that
+// sends its application key requests to a server listing the supplied pages
+// of keys.
+func newTestKeysClient(t *testing.T, pages [][]B2CreateKeyResponse) *BackblazeClient {
+	t.Helper()
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req B2ListKeysRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("cannot decode request: %v", err)
+		}
+		// Pages are identified by their index.
+		page, _ := strconv.Atoi(req.StartApplicationKeyID)
+		resp := B2ListKeysResponse{Keys: pages[page]}
+		if page+1 < len(pages) {
+			resp.NextApplicationKeyID = strconv.Itoa(page + 1)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func TestGetApplicationKey(t *testing.T) {
+	pages := [][]B2CreateKeyResponse{
+		{{ApplicationKeyID: "key-1", KeyName: "one"}},
+		{{ApplicationKeyID: "key-2", KeyName: "two", BucketIDs: []string{"bucket-a", "bucket-b"}}},
+	}
+	c := newTestKeysClient(t, pages)
+
+	got, err := c.GetApplicationKey(context.Background(), "key-2")
+	if err != nil {
+		t.Fatalf("GetApplicationKey() error = %v", err)
+	}
+	if got.KeyName != "two" || strings.Join(got.BucketIDs, ",") != "bucket-a,bucket-b" {
+		t.Errorf("GetApplicationKey() = %+v", got)
+	}
+
+	if _, err := c.GetApplicationKey(context.Background(), "key-3"); !errors.Is(err, ErrApplicationKeyNotFound) {
+		t.Errorf("GetApplicationKey() of a missing key: want %v, got %v", ErrApplicationKeyNotFound, err)
+	}
+}
+
+func TestGetApplicationKeyByName(t *testing.T) {
+	pages := [][]B2CreateKeyResponse{
+		{{ApplicationKeyID: "key-1", KeyName: "one"}, {ApplicationKeyID: "key-2", KeyName: "dup"}},
+		{{ApplicationKeyID: "key-3", KeyName: "two"}, {ApplicationKeyID: "key-4", KeyName: "dup"}},
+	}
+	c := newTestKeysClient(t, pages)
+
+	tests := map[string]struct {
+		keyName      string
+		wantID       string
+		wantErr      bool
+		wantNotFound bool
+	}{
+		"Found":     {keyName: "two", wantID: "key-3"},
+		"NotFound":  {keyName: "three", wantErr: true, wantNotFound: true},
+		"Ambiguous": {keyName: "dup", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := c.GetApplicationKeyByName(context.Background(), tc.keyName)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GetApplicationKeyByName() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if errors.Is(err, ErrApplicationKeyNotFound) != tc.wantNotFound {
+				t.Errorf("GetApplicationKeyByName() error = %v, want not found %v", err, tc.wantNotFound)
+			}
+			if err == nil && got.ApplicationKeyID != tc.wantID {
+				t.Errorf("GetApplicationKeyByName() = %s, want %s", got.ApplicationKeyID, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestCreateApplicationKeyRequest(t *testing.T) {
+	var path string
+	var body map[string]any
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("cannot decode request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(B2CreateKeyResponse{ApplicationKeyID: "key-1", BucketIDs: []string{"bucket-a", "bucket-b"}})
+	})
+
+	got, err := c.CreateApplicationKey(context.Background(), "my-key", []string{"listBuckets"}, []string{"bucket-a", "bucket-b"}, "", nil)
+	if err != nil {
+		t.Fatalf("CreateApplicationKey() error = %v", err)
+	}
+
+	// Only v4 of the API restricts a key to the buckets in bucketIds. Earlier
+	// versions ignore the field and create an unrestricted key.
+	if path != "/b2api/v4/b2_create_key" {
+		t.Errorf("CreateApplicationKey() path = %s, want /b2api/v4/b2_create_key", path)
+	}
+	if _, ok := body["bucketId"]; ok {
+		t.Errorf("CreateApplicationKey() request has a bucketId field: %v", body)
+	}
+	ids, _ := body["bucketIds"].([]any)
+	if len(ids) != 2 || ids[0] != "bucket-a" || ids[1] != "bucket-b" {
+		t.Errorf("CreateApplicationKey() request bucketIds = %v, want [bucket-a bucket-b]", body["bucketIds"])
+	}
+	if strings.Join(got.BucketIDs, ",") != "bucket-a,bucket-b" {
+		t.Errorf("CreateApplicationKey() = %+v", got)
+	}
+}
+
+func TestListApplicationKeysVersion(t *testing.T) {
+	var path string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"keys":[{"applicationKeyId":"key-1","keyName":"one","bucketIds":["bucket-a","bucket-b"]}]}`))
+	})
+
+	got, err := c.GetApplicationKey(context.Background(), "key-1")
+	if err != nil {
+		t.Fatalf("GetApplicationKey() error = %v", err)
+	}
+	if path != "/b2api/v4/b2_list_keys" {
+		t.Errorf("GetApplicationKey() path = %s, want /b2api/v4/b2_list_keys", path)
+	}
+	if strings.Join(got.BucketIDs, ",") != "bucket-a,bucket-b" {
+		t.Errorf("GetApplicationKey() bucketIds = %v, want [bucket-a bucket-b]", got.BucketIDs)
+	}
+}
+
+// newTestS3Client returns a client whose S3 requests are handled by the
+// supplied handler.
+func newTestS3Client(t *testing.T, h http.HandlerFunc) *BackblazeClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &BackblazeClient{S3Client: s3.New(s3.Options{
+		Region:       DefaultRegion,
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("id", "key", ""),
+	})}
+}
+
+func TestLockedObject(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	cases := map[string]struct {
+		// retention and holds map object keys to their retain until date
+		// and legal hold status. Keys without either have none.
+		retention map[string]string
+		holds     map[string]string
+		want      string
+		// wantRequests are the lock settings read, in order.
+		wantRequests []string
+	}{
+		"NoneLocked": {
+			retention:    map[string]string{"a": past},
+			wantRequests: []string{"retention a", "retention b", "legal-hold b", "retention c", "legal-hold c"},
+		},
+		"StopsAtFirstRetention": {
+			retention:    map[string]string{"a": future, "b": future},
+			want:         "a",
+			wantRequests: []string{"retention a"},
+		},
+		"StopsAtFirstLegalHold": {
+			holds:        map[string]string{"b": "ON", "c": "ON"},
+			want:         "b",
+			wantRequests: []string{"retention a", "legal-hold a", "retention b", "legal-hold b"},
+		},
+		"LegalHoldOnlyWithoutRetention": {
+			retention:    map[string]string{"a": past},
+			holds:        map[string]string{"a": "ON", "c": "ON"},
+			want:         "c",
+			wantRequests: []string{"retention a", "retention b", "legal-hold b", "retention c", "legal-hold c"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			c := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+				key := strings.TrimPrefix(r.URL.Path, "/my-bucket/")
+				w.Header().Set("Content-Type", "application/xml")
+				switch q := r.URL.Query(); {
+				case q.Has("versions"):
+					_, _ = w.Write([]byte(`<ListVersionsResult><IsTruncated>false</IsTruncated>` +
+						`<Version><Key>a</Key><VersionId>1</VersionId></Version>` +
+						`<Version><Key>b</Key><VersionId>2</VersionId></Version>` +
+						`<Version><Key>c</Key><VersionId>3</VersionId></Version>` +
+						`</ListVersionsResult>`))
+				case q.Has("retention"):
+					requests = append(requests, "retention "+key)
+					until, ok := tc.retention[key]
+					if !ok {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`<Error><Code>NoSuchObjectLockConfiguration</Code></Error>`))
+						return
+					}
+					_, _ = w.Write([]byte(`<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>` + until + `</RetainUntilDate></Retention>`))
+				case q.Has("legal-hold"):
+					requests = append(requests, "legal-hold "+key)
+					status, ok := tc.holds[key]
+					if !ok {
+						status = "OFF"
+					}
+					_, _ = w.Write([]byte(`<LegalHold><Status>` + status + `</Status></LegalHold>`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+			})
+
+			got, err := c.LockedObject(context.Background(), "my-bucket")
+			if err != nil {
+				t.Fatalf("LockedObject(...): %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("LockedObject(...): want %q, got %q", tc.want, got)
+			}
+			if strings.Join(requests, ",") != strings.Join(tc.wantRequests, ",") {
+				t.Errorf("lock settings read: want %v, got %v", tc.wantRequests, requests)
+			}
+		})
+	}
+}
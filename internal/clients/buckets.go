@@ -0,0 +1,322 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// B2 Native API bucket endpoints, relative to the apiUrl returned by
+	// b2_authorize_account.
+	B2ListBucketsPath  = "/b2api/v3/b2_list_buckets"
+	B2UpdateBucketPath = "/b2api/v3/b2_update_bucket"
+
+	// B2ErrorCodeConflict is returned by b2_update_bucket when ifRevisionIs
+	// does not match the current bucket revision.
+	B2ErrorCodeConflict = "conflict"
+	// B2ErrorCodeBadBucketID is returned when the supplied bucketId does not
+	// identify a bucket of the account, for example because it was deleted.
+	B2ErrorCodeBadBucketID = "bad_bucket_id"
+	// B2ErrorCodeDuplicateBucketName is returned when a bucket name is
+	// already in use, by this or another account.
+	B2ErrorCodeDuplicateBucketName = "duplicate_bucket_name"
+)
+
+// B2LifecycleRule is a lifecycle rule as represented by the B2 Native API.
+type B2LifecycleRule struct {
+	FileNamePrefix            string `json:"fileNamePrefix"`
+	DaysFromUploadingToHiding *int   `json:"daysFromUploadingToHiding"`
+	DaysFromHidingToDeleting  *int   `json:"daysFromHidingToDeleting"`
+}
+
+// B2CORSRule is a CORS rule as represented by the B2 Native API.
+type B2CORSRule struct {
+	CorsRuleName      string   `json:"corsRuleName"`
+	AllowedOrigins    []string `json:"allowedOrigins"`
+	AllowedOperations []string `json:"allowedOperations"`
+	AllowedHeaders    []string `json:"allowedHeaders,omitempty"`
+	ExposeHeaders     []string `json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds     int      `json:"maxAgeSeconds"`
+}
+
+// B2ServerSideEncryption is a server-side encryption configuration. A nil
+// Mode means encryption is disabled.
+type B2ServerSideEncryption struct {
+	Mode      *string `json:"mode"`
+	Algorithm string  `json:"algorithm,omitempty"`
+}
+
+// B2EncryptionSetting is a server-side encryption configuration as returned
+// by the B2 Native API. Value is only set if the key used to read it has the
+// readBucketEncryption capability.
+type B2EncryptionSetting struct {
+	IsClientAuthorizedToRead bool                    `json:"isClientAuthorizedToRead"`
+	Value                    *B2ServerSideEncryption `json:"value,omitempty"`
+}
+
+// B2RetentionPeriod is the duration of a file retention.
+type B2RetentionPeriod struct {
+	Duration int    `json:"duration"`
+	Unit     string `json:"unit"`
+}
+
+// B2DefaultRetention is the default retention applied to new files in a
+// bucket with file lock enabled. A nil Mode means no default retention.
+type B2DefaultRetention struct {
+	Mode   *string            `json:"mode"`
+	Period *B2RetentionPeriod `json:"period,omitempty"`
+}
+
+// B2FileLockValue is the file lock (Object Lock) configuration of a bucket.
+type B2FileLockValue struct {
+	IsFileLockEnabled bool                `json:"isFileLockEnabled"`
+	DefaultRetention  *B2DefaultRetention `json:"defaultRetention,omitempty"`
+}
+
+// B2FileLockConfiguration is the file lock configuration as returned by the
+// B2 Native API. Value is only set if the key used to read it has the
+// readBucketRetentions capability.
+type B2FileLockConfiguration struct {
+	IsClientAuthorizedToRead bool             `json:"isClientAuthorizedToRead"`
+	Value                    *B2FileLockValue `json:"value,omitempty"`
+}
+
+// B2ReplicationRule is a Cloud Replication rule as represented by the B2
+// Native API.
+type B2ReplicationRule struct {
+	ReplicationRuleName  string `json:"replicationRuleName"`
+	DestinationBucketID  string `json:"destinationBucketId"`
+	FileNamePrefix       string `json:"fileNamePrefix"`
+	IncludeExistingFiles bool   `json:"includeExistingFiles"`
+	IsEnabled            bool   `json:"isEnabled"`
+	Priority             int    `json:"priority"`
+}
+
+// B2ReplicationSource configures a bucket as a replication source.
+type B2ReplicationSource struct {
+	SourceApplicationKeyID string              `json:"sourceApplicationKeyId"`
+	ReplicationRules       []B2ReplicationRule `json:"replicationRules"`
+}
+
+// B2ReplicationDestination configures a bucket as a replication destination.
+type B2ReplicationDestination struct {
+	SourceToDestinationKeyMapping map[string]string `json:"sourceToDestinationKeyMapping"`
+}
+
+// B2ReplicationConfiguration is the replication configuration of a bucket.
+type B2ReplicationConfiguration struct {
+	AsReplicationSource      *B2ReplicationSource      `json:"asReplicationSource,omitempty"`
+	AsReplicationDestination *B2ReplicationDestination `json:"asReplicationDestination,omitempty"`
+}
+
+// B2ReplicationSetting is the replication configuration as returned by the B2
+// Native API. Value is only set if the key used to read it has the
+// readBucketReplications capability.
+type B2ReplicationSetting struct {
+	IsClientAuthorizedToRead bool                        `json:"isClientAuthorizedToRead"`
+	Value                    *B2ReplicationConfiguration `json:"value,omitempty"`
+}
+
+// B2Bucket represents a bucket as returned by the B2 Native API
+type B2Bucket struct {
+	AccountID                   string                   `json:"accountId"`
+	BucketID                    string                   `json:"bucketId"`
+	BucketName                  string                   `json:"bucketName"`
+	BucketType                  string                   `json:"bucketType"`
+	BucketInfo                  map[string]string        `json:"bucketInfo,omitempty"`
+	CORSRules                   []B2CORSRule             `json:"corsRules,omitempty"`
+	LifecycleRules              []B2LifecycleRule        `json:"lifecycleRules,omitempty"`
+	DefaultServerSideEncryption *B2EncryptionSetting     `json:"defaultServerSideEncryption,omitempty"`
+	FileLockConfiguration       *B2FileLockConfiguration `json:"fileLockConfiguration,omitempty"`
+	ReplicationConfiguration    *B2ReplicationSetting    `json:"replicationConfiguration,omitempty"`
+	Revision                    int                      `json:"revision"`
+}
+
+// B2ListBucketsRequest represents the request to list buckets
+type B2ListBucketsRequest struct {
+	AccountID  string `json:"accountId"`
+	BucketID   string `json:"bucketId,omitempty"`
+	BucketName string `json:"bucketName,omitempty"`
+}
+
+// B2ListBucketsResponse represents the response from list buckets
+type B2ListBucketsResponse struct {
+	Buckets []B2Bucket `json:"buckets"`
+}
+
+// B2UpdateBucketRequest represents the request to update a bucket. CORSRules
+// and LifecycleRules always replace the existing rules, so an empty slice
+// removes them. BucketInfo replaces the existing bucket info unless it is nil,
+// so an empty map removes it. IfRevisionIs makes the update fail with a
+// conflict if the bucket has been changed since it was observed.
+type B2UpdateBucketRequest struct {
+	AccountID                   string                      `json:"accountId"`
+	BucketID                    string                      `json:"bucketId"`
+	BucketType                  string                      `json:"bucketType,omitempty"`
+	BucketInfo                  map[string]string           `json:"bucketInfo,omitzero"`
+	CORSRules                   []B2CORSRule                `json:"corsRules"`
+	LifecycleRules              []B2LifecycleRule           `json:"lifecycleRules"`
+	DefaultServerSideEncryption *B2ServerSideEncryption     `json:"defaultServerSideEncryption,omitempty"`
+	DefaultRetention            *B2DefaultRetention         `json:"defaultRetention,omitempty"`
+	ReplicationConfiguration    *B2ReplicationConfiguration `json:"replicationConfiguration,omitempty"`
+	IfRevisionIs                int                         `json:"ifRevisionIs,omitempty"`
+}
+
+// B2Error is an error response from the B2 Native API.
+type B2Error struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements error.
+func (e *B2Error) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.Status, e.Message)
+}
+
+// IsRevisionConflict returns true if err indicates that an update was rejected
+// because the bucket revision did not match ifRevisionIs.
+func IsRevisionConflict(err error) bool {
+	var b2err *B2Error
+	if !errors.As(err, &b2err) {
+		return false
+	}
+	return b2err.Status == http.StatusConflict || b2err.Code == B2ErrorCodeConflict
+}
+
+// IsBucketNotFound returns true if err indicates that the bucket an
+// operation was made on does not exist.
+func IsBucketNotFound(err error) bool {
+	var b2err *B2Error
+	return errors.As(err, &b2err) && b2err.Code == B2ErrorCodeBadBucketID
+}
+
+// GetBucket returns the named bucket as reported by the B2 Native API, or nil
+// if no bucket with that name exists in the account.
+func (c *BackblazeClient) GetBucket(ctx context.Context, bucketName string) (*B2Bucket, error) {
+	if err := c.authorizeAccount(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to authorize account")
+	}
+
+	req := B2ListBucketsRequest{
+		AccountID:  c.AccountID,
+		BucketName: bucketName,
+	}
+
+	var listResp B2ListBucketsResponse
+	if err := c.callB2(ctx, B2ListBucketsPath, req, &listResp); err != nil {
+		return nil, errors.Wrap(err, "failed to list buckets")
+	}
+
+	for i := range listResp.Buckets {
+		if listResp.Buckets[i].BucketName == bucketName {
+			return &listResp.Buckets[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetBucketByID returns the bucket with the supplied ID as reported by the B2
+// Native API, or nil if no such bucket exists in the account.
+func (c *BackblazeClient) GetBucketByID(ctx context.Context, bucketID string) (*B2Bucket, error) {
+	if err := c.authorizeAccount(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to authorize account")
+	}
+
+	req := B2ListBucketsRequest{
+		AccountID: c.AccountID,
+		BucketID:  bucketID,
+	}
+
+	var listResp B2ListBucketsResponse
+	if err := c.callB2(ctx, B2ListBucketsPath, req, &listResp); err != nil {
+		return nil, errors.Wrap(err, "failed to list buckets")
+	}
+
+	for i := range listResp.Buckets {
+		if listResp.Buckets[i].BucketID == bucketID {
+			return &listResp.Buckets[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// UpdateBucket updates a bucket using the B2 Native API and returns the
+// updated bucket.
+func (c *BackblazeClient) UpdateBucket(ctx context.Context, req *B2UpdateBucketRequest) (*B2Bucket, error) {
+	if err := c.authorizeAccount(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to authorize account")
+	}
+
+	if req.AccountID == "" {
+		req.AccountID = c.AccountID
+	}
+
+	var updated B2Bucket
+	if err := c.callB2(ctx, B2UpdateBucketPath, req, &updated); err != nil {
+		return nil, errors.Wrap(err, "failed to update bucket")
+	}
+
+	return &updated, nil
+}
+
+// callB2 posts req to the supplied B2 Native API path and decodes the response
+// into resp. Error responses are returned as a *B2Error.
+func (c *BackblazeClient) callB2(ctx context.Context, path string, req, resp interface{}) error {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.APIURL+path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "failed to create HTTP request")
+	}
+
+	httpReq.Header.Set("Authorization", c.AuthToken)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute HTTP request")
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		b2err := &B2Error{}
+		if err := json.Unmarshal(body, b2err); err != nil || b2err.Code == "" {
+			return errors.Errorf("request failed with status %d: %s", httpResp.StatusCode, string(body))
+		}
+		b2err.Status = httpResp.StatusCode
+		return b2err
+	}
+
+	return errors.Wrap(json.NewDecoder(httpResp.Body).Decode(resp), "failed to decode response")
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/pkg/errors"
+)
+
+// newTestB2Client returns a client that is already authorized against the
+// supplied B2 Native API server.
+func newTestB2Client(t *testing.T, handler http.HandlerFunc) *BackblazeClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &BackblazeClient{
+		HTTPClient:      srv.Client(),
+		AuthToken:       "test-token",
+		APIURL:          srv.URL,
+		AccountID:       "test-account",
+		tokenExpiration: time.Now().Add(time.Hour),
+	}
+}
+
+func TestGetBucket(t *testing.T) {
+	tests := map[string]struct {
+		buckets []B2Bucket
+		want    *B2Bucket
+	}{
+		"Found": {
+			buckets: []B2Bucket{{BucketName: "test-bucket", BucketID: "id", BucketType: "allPrivate", Revision: 3}},
+			want:    &B2Bucket{BucketName: "test-bucket", BucketID: "id", BucketType: "allPrivate", Revision: 3},
+		},
+		"NotFound": {
+			buckets: []B2Bucket{},
+			want:    nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestB2Client(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != B2ListBucketsPath {
+					t.Errorf("path: want %s, got %s", B2ListBucketsPath, r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "test-token" {
+					t.Errorf("Authorization: want test-token, got %q", got)
+				}
+				var req B2ListBucketsRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("cannot decode request: %v", err)
+				}
+				if req.AccountID != "test-account" || req.BucketName != "test-bucket" {
+					t.Errorf("unexpected request: %+v", req)
+				}
+				_ = json.NewEncoder(w).Encode(B2ListBucketsResponse{Buckets: tc.buckets})
+			})
+
+			got, err := c.GetBucket(context.Background(), "test-bucket")
+			if err != nil {
+				t.Fatalf("GetBucket() error = %v", err)
+			}
+			if (got == nil) != (tc.want == nil) {
+				t.Fatalf("GetBucket() = %+v, want %+v", got, tc.want)
+			}
+			if got != nil && (got.BucketID != tc.want.BucketID || got.Revision != tc.want.Revision) {
+				t.Errorf("GetBucket() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetBucketByID(t *testing.T) {
+	c := newTestB2Client(t, func(w http.ResponseWriter, r *http.Request) {
+		var req B2ListBucketsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("cannot decode request: %v", err)
+		}
+		if req.BucketID != "bucket-id" || req.BucketName != "" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		_ = json.NewEncoder(w).Encode(B2ListBucketsResponse{Buckets: []B2Bucket{{BucketName: "test-bucket", BucketID: "bucket-id"}}})
+	})
+
+	got, err := c.GetBucketByID(context.Background(), "bucket-id")
+	if err != nil {
+		t.Fatalf("GetBucketByID() error = %v", err)
+	}
+	if got == nil || got.BucketName != "test-bucket" {
+		t.Errorf("GetBucketByID() = %+v, want test-bucket", got)
+	}
+}
+
+func TestUpdateBucket(t *testing.T) {
+	tests := map[string]struct {
+		status       int
+		body         interface{}
+		wantErr      bool
+		wantConflict bool
+	}{
+		"Success": {
+			status: http.StatusOK,
+			body:   B2Bucket{BucketID: "id", BucketType: "allPublic", Revision: 4},
+		},
+		"RevisionConflict": {
+			status:       http.StatusConflict,
+			body:         B2Error{Status: http.StatusConflict, Code: B2ErrorCodeConflict, Message: "conflict"},
+			wantErr:      true,
+			wantConflict: true,
+		},
+		"BadRequest": {
+			status:  http.StatusBadRequest,
+			body:    B2Error{Status: http.StatusBadRequest, Code: "bad_request", Message: "Invalid bucketId"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestB2Client(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != B2UpdateBucketPath {
+					t.Errorf("path: want %s, got %s", B2UpdateBucketPath, r.URL.Path)
+				}
+				var req map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("cannot decode request: %v", err)
+				}
+				if req["accountId"] != "test-account" || req["ifRevisionIs"] != float64(3) {
+					t.Errorf("unexpected request: %+v", req)
+				}
+				// Rules must always be sent so that they can be removed.
+				if _, ok := req["corsRules"].([]interface{}); !ok {
+					t.Errorf("corsRules: want empty array, got %v", req["corsRules"])
+				}
+				w.WriteHeader(tc.status)
+				_ = json.NewEncoder(w).Encode(tc.body)
+			})
+
+			got, err := c.UpdateBucket(context.Background(), &B2UpdateBucketRequest{
+				BucketID:       "id",
+				BucketType:     "allPublic",
+				CORSRules:      []B2CORSRule{},
+				LifecycleRules: []B2LifecycleRule{},
+				IfRevisionIs:   3,
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("UpdateBucket() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if IsRevisionConflict(err) != tc.wantConflict {
+				t.Errorf("IsRevisionConflict(%v) = %v, want %v", err, !tc.wantConflict, tc.wantConflict)
+			}
+			if !tc.wantErr && got.Revision != 4 {
+				t.Errorf("UpdateBucket() revision = %d, want 4", got.Revision)
+			}
+		})
+	}
+}
+
+func TestUpdateBucketInfo(t *testing.T) {
+	tests := map[string]struct {
+		info     map[string]string
+		wantSent bool
+		want     map[string]interface{}
+	}{
+		"Unmanaged": {},
+		"Cleared":   {info: map[string]string{}, wantSent: true, want: map[string]interface{}{}},
+		"Set":       {info: map[string]string{"cost-center": "team-a"}, wantSent: true, want: map[string]interface{}{"cost-center": "team-a"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestB2Client(t, func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("cannot decode request: %v", err)
+				}
+				got, sent := req["bucketInfo"]
+				if sent != tc.wantSent {
+					t.Errorf("bucketInfo sent: want %t, got %t (%v)", tc.wantSent, sent, got)
+				}
+				if sent && !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("bucketInfo: want %v, got %v", tc.want, got)
+				}
+				_ = json.NewEncoder(w).Encode(B2Bucket{BucketID: "id"})
+			})
+
+			if _, err := c.UpdateBucket(context.Background(), &B2UpdateBucketRequest{BucketID: "id", BucketInfo: tc.info}); err != nil {
+				t.Fatalf("UpdateBucket() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestIsBucketNameTaken(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil":                 {err: nil, want: false},
+		"S3AlreadyExists":     {err: errors.Wrap(&types.BucketAlreadyExists{}, "failed to create bucket"), want: true},
+		"S3AlreadyOwnedByYou": {err: &types.BucketAlreadyOwnedByYou{}, want: false},
+		"B2DuplicateName":     {err: &B2Error{Status: 400, Code: B2ErrorCodeDuplicateBucketName}, want: true},
+		"OtherB2Error":        {err: &B2Error{Status: 400, Code: "bad_request"}, want: false},
+		"UnrelatedError":      {err: errors.New("boom"), want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsBucketNameTaken(tc.err); got != tc.want {
+				t.Errorf("IsBucketNameTaken(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsBucketNotFound(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil":            {err: nil, want: false},
+		"BadBucketID":    {err: errors.Wrap(&B2Error{Status: 400, Code: B2ErrorCodeBadBucketID}, "failed to get bucket notification rules"), want: true},
+		"OtherB2Error":   {err: &B2Error{Status: 400, Code: "bad_request"}, want: false},
+		"UnrelatedError": {err: errors.New("boom"), want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsBucketNotFound(tc.err); got != tc.want {
+				t.Errorf("IsBucketNotFound(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"Nil":            {err: nil, want: ""},
+		"B2Error":        {err: errors.Wrap(&B2Error{Status: 400, Code: B2ErrorCodeDuplicateBucketName}, "cannot create bucket"), want: B2ErrorCodeDuplicateBucketName},
+		"S3Error":        {err: errors.Wrap(&types.BucketAlreadyExists{}, "failed to create bucket"), want: "BucketAlreadyExists"},
+		"UnrelatedError": {err: errors.New("boom"), want: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ErrorCode(tc.err); got != tc.want {
+				t.Errorf("ErrorCode(%v) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
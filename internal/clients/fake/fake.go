@@ -0,0 +1,687 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-memory Backblaze B2 backend for tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	"github.com/rossigee/provider-backblaze/internal/clients"
+)
+
+// DefaultAccountID is the account ID reported by a Backend.
+const DefaultAccountID = "fake-account-id"
+
+// errNoSuchBucketPolicy is the S3 API error returned for a bucket without a
+// policy.
+var errNoSuchBucketPolicy = &smithy.GenericAPIError{Code: clients.S3ErrorCodeNoSuchBucketPolicy, Message: "The bucket policy does not exist"}
+
+// Bucket is a bucket held by a Backend.
+type Bucket struct {
+	Name           string
+	ID             string
+	Type           string
+	Region         string
+	Objects        int
+	Info           map[string]string
+	Encryption     *clients.B2ServerSideEncryption
+	CORSRules      []clients.B2CORSRule
+	LifecycleRules []clients.B2LifecycleRule
+	Revision       int
+
+	// FileLockEnabled, Retention and LockedObjects model file lock. Locked
+	// objects can't be deleted, even when the bucket is emptied.
+	FileLockEnabled bool
+	Retention       *clients.B2DefaultRetention
+	LockedObjects   int
+
+	Replication *clients.B2ReplicationConfiguration
+
+	NotificationRules []clients.B2NotificationRule
+}
+
+// Call records a single operation invoked on a Backend.
+type Call struct {
+	Op   string
+	Args []string
+}
+
+// Backend is an in-memory Backblaze B2 account. It is safe for concurrent use
+// and records every operation so tests can assert on external calls.
+type Backend struct {
+	mu       sync.Mutex
+	buckets  map[string]*Bucket
+	taken    map[string]bool
+	keys     map[string]*clients.B2CreateKeyResponse
+	policies map[string]string
+	calls    []Call
+	errs     map[string]error
+	nextKey  int
+	nextID   int
+
+	// capabilities of the provider's key. nil means they aren't reported.
+	capabilities []string
+}
+
+// NewBackend returns an empty Backend.
+func NewBackend() *Backend {
+	return &Backend{
+		buckets:  map[string]*Bucket{},
+		taken:    map[string]bool{},
+		keys:     map[string]*clients.B2CreateKeyResponse{},
+		policies: map[string]string{},
+		errs:     map[string]error{},
+	}
+}
+
+// NewClientFn returns a clients.NewClientFn that always returns this Backend,
+// regardless of the supplied configuration.
+func (b *Backend) NewClientFn() clients.NewClientFn {
+	return func(_ clients.Config) (clients.Client, error) {
+		return b, nil
+	}
+}
+
+// SetError makes every subsequent call to op fail with err. Passing a nil
+// error clears a previously injected failure.
+func (b *Backend) SetError(op string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.errs, op)
+		return
+	}
+	b.errs[op] = err
+}
+
+// Calls returns the operations invoked so far, in order.
+func (b *Backend) Calls() []Call {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Call(nil), b.calls...)
+}
+
+// CallCount returns how many times op has been invoked.
+func (b *Backend) CallCount(op string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, c := range b.calls {
+		if c.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// Bucket returns a copy of the named bucket, if it exists.
+func (b *Backend) Bucket(name string) (Bucket, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk, ok := b.buckets[name]
+	if !ok {
+		return Bucket{}, false
+	}
+	return *bk, true
+}
+
+// PutBucket seeds the backend with a bucket, as if it was created out of band.
+func (b *Backend) PutBucket(bk Bucket) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bk.ID == "" {
+		bk.ID = b.newBucketID()
+	}
+	if bk.Revision == 0 {
+		bk.Revision = 1
+	}
+	b.buckets[bk.Name] = &bk
+}
+
+// TakeBucketName marks a bucket name as in use by another account. Buckets
+// with that name can't be created, and aren't visible in this account.
+func (b *Backend) TakeBucketName(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.taken[name] = true
+}
+
+// ModifyBucket applies fn to the named bucket and bumps its revision, as if
+// it was changed out of band, e.g. in the B2 web console. It returns false if
+// the bucket does not exist.
+func (b *Backend) ModifyBucket(name string, fn func(bk *Bucket)) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk, ok := b.buckets[name]
+	if !ok {
+		return false
+	}
+	fn(bk)
+	bk.Revision++
+	return true
+}
+
+// newBucketID returns a unique bucket ID. Callers must hold b.mu.
+func (b *Backend) newBucketID() string {
+	b.nextID++
+	return fmt.Sprintf("fakebucket%014d", b.nextID)
+}
+
+// b2Bucket returns the B2 Native API representation of bk.
+func b2Bucket(bk *Bucket) *clients.B2Bucket {
+	return &clients.B2Bucket{
+		AccountID:      DefaultAccountID,
+		BucketID:       bk.ID,
+		BucketName:     bk.Name,
+		BucketType:     bk.Type,
+		BucketInfo:     copyInfo(bk.Info),
+		CORSRules:      append([]clients.B2CORSRule(nil), bk.CORSRules...),
+		LifecycleRules: append([]clients.B2LifecycleRule(nil), bk.LifecycleRules...),
+		Revision:       bk.Revision,
+
+		DefaultServerSideEncryption: &clients.B2EncryptionSetting{
+			IsClientAuthorizedToRead: true,
+			Value:                    copyEncryption(bk.Encryption),
+		},
+		FileLockConfiguration: &clients.B2FileLockConfiguration{
+			IsClientAuthorizedToRead: true,
+			Value: &clients.B2FileLockValue{
+				IsFileLockEnabled: bk.FileLockEnabled,
+				DefaultRetention:  copyRetention(bk.Retention),
+			},
+		},
+		ReplicationConfiguration: &clients.B2ReplicationSetting{
+			IsClientAuthorizedToRead: true,
+			Value:                    copyReplication(bk.Replication),
+		},
+	}
+}
+
+func copyReplication(in *clients.B2ReplicationConfiguration) *clients.B2ReplicationConfiguration {
+	out := &clients.B2ReplicationConfiguration{}
+	if in == nil {
+		return out
+	}
+	if src := in.AsReplicationSource; src != nil {
+		out.AsReplicationSource = &clients.B2ReplicationSource{
+			SourceApplicationKeyID: src.SourceApplicationKeyID,
+			ReplicationRules:       append([]clients.B2ReplicationRule(nil), src.ReplicationRules...),
+		}
+	}
+	if dst := in.AsReplicationDestination; dst != nil {
+		out.AsReplicationDestination = &clients.B2ReplicationDestination{
+			SourceToDestinationKeyMapping: copyInfo(dst.SourceToDestinationKeyMapping),
+		}
+	}
+	return out
+}
+
+func copyRetention(in *clients.B2DefaultRetention) *clients.B2DefaultRetention {
+	if in == nil || in.Mode == nil {
+		return &clients.B2DefaultRetention{}
+	}
+	out := &clients.B2DefaultRetention{Mode: ptr.To(*in.Mode)}
+	if in.Period != nil {
+		out.Period = &clients.B2RetentionPeriod{Duration: in.Period.Duration, Unit: in.Period.Unit}
+	}
+	return out
+}
+
+func copyEncryption(in *clients.B2ServerSideEncryption) *clients.B2ServerSideEncryption {
+	if in == nil || in.Mode == nil {
+		return &clients.B2ServerSideEncryption{}
+	}
+	return &clients.B2ServerSideEncryption{Mode: ptr.To(*in.Mode), Algorithm: in.Algorithm}
+}
+
+// Key returns a copy of the application key with the supplied ID, if it exists.
+func (b *Backend) Key(id string) (clients.B2CreateKeyResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	k, ok := b.keys[id]
+	if !ok {
+		return clients.B2CreateKeyResponse{}, false
+	}
+	return *k, true
+}
+
+// Policy returns the policy attached to the named bucket, if any.
+func (b *Backend) Policy(bucketName string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.policies[bucketName]
+	return p, ok
+}
+
+// record logs a call and returns any injected error for it. Callers must
+// hold b.mu.
+func (b *Backend) record(op string, args ...string) error {
+	b.calls = append(b.calls, Call{Op: op, Args: args})
+	return b.errs[op]
+}
+
+// CreateBucket implements clients.Client.
+func (b *Backend) CreateBucket(_ context.Context, bucketName, bucketType, region string, fileLockEnabled bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("CreateBucket", bucketName, bucketType, region); err != nil {
+		return err
+	}
+	if b.taken[bucketName] {
+		return errors.Wrap(&s3types.BucketAlreadyExists{Message: ptr.To("The requested bucket name is not available")}, "failed to create bucket")
+	}
+	if _, ok := b.buckets[bucketName]; ok {
+		return errors.Wrap(&s3types.BucketAlreadyOwnedByYou{Message: ptr.To("Your previous request to create the named bucket succeeded")}, "failed to create bucket")
+	}
+	if region == "" {
+		region = clients.DefaultRegion
+	}
+	b.buckets[bucketName] = &Bucket{Name: bucketName, ID: b.newBucketID(), Type: bucketType, Region: region, FileLockEnabled: fileLockEnabled, Revision: 1}
+	return nil
+}
+
+// DeleteBucket implements clients.Client.
+func (b *Backend) DeleteBucket(_ context.Context, bucketName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("DeleteBucket", bucketName); err != nil {
+		return err
+	}
+	bk, ok := b.buckets[bucketName]
+	if !ok {
+		return errors.New("NoSuchBucket")
+	}
+	if bk.Objects > 0 {
+		return errors.Errorf("bucket %s is not empty", bucketName)
+	}
+	delete(b.buckets, bucketName)
+	delete(b.policies, bucketName)
+	return nil
+}
+
+// BucketExists implements clients.Client.
+func (b *Backend) BucketExists(_ context.Context, bucketName string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("BucketExists", bucketName); err != nil {
+		return false, err
+	}
+	_, ok := b.buckets[bucketName]
+	return ok, nil
+}
+
+// GetBucketLocation implements clients.Client.
+func (b *Backend) GetBucketLocation(_ context.Context, bucketName string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetBucketLocation", bucketName); err != nil {
+		return "", err
+	}
+	bk, ok := b.buckets[bucketName]
+	if !ok {
+		return "", errors.New("NoSuchBucket")
+	}
+	return bk.Region, nil
+}
+
+// DeleteAllObjectsInBucket implements clients.Client.
+func (b *Backend) DeleteAllObjectsInBucket(_ context.Context, bucketName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("DeleteAllObjectsInBucket", bucketName); err != nil {
+		return err
+	}
+	bk, ok := b.buckets[bucketName]
+	if !ok {
+		return errors.New("NoSuchBucket")
+	}
+	bk.Objects = bk.LockedObjects
+	return nil
+}
+
+// LockedObject implements clients.Client.
+func (b *Backend) LockedObject(_ context.Context, bucketName string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("LockedObject", bucketName); err != nil {
+		return "", err
+	}
+	bk, ok := b.buckets[bucketName]
+	if !ok {
+		return "", errors.New("NoSuchBucket")
+	}
+	if bk.LockedObjects == 0 {
+		return "", nil
+	}
+	return "locked-0", nil
+}
+
+// GetBucket implements clients.Client.
+func (b *Backend) GetBucket(_ context.Context, bucketName string) (*clients.B2Bucket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetBucket", bucketName); err != nil {
+		return nil, err
+	}
+	bk, ok := b.buckets[bucketName]
+	if !ok {
+		return nil, nil
+	}
+	return b2Bucket(bk), nil
+}
+
+// GetBucketByID implements clients.Client.
+func (b *Backend) GetBucketByID(_ context.Context, bucketID string) (*clients.B2Bucket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetBucketByID", bucketID); err != nil {
+		return nil, err
+	}
+	bk := b.bucketByID(bucketID)
+	if bk == nil {
+		return nil, nil
+	}
+	return b2Bucket(bk), nil
+}
+
+// UpdateBucket implements clients.Client.
+func (b *Backend) UpdateBucket(_ context.Context, req *clients.B2UpdateBucketRequest) (*clients.B2Bucket, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk := b.bucketByID(req.BucketID)
+	name := ""
+	if bk != nil {
+		name = bk.Name
+	}
+	if err := b.record("UpdateBucket", name, req.BucketID); err != nil {
+		return nil, err
+	}
+	if bk == nil {
+		return nil, &clients.B2Error{Status: 400, Code: "bad_request", Message: "Invalid bucketId: " + req.BucketID}
+	}
+	if req.IfRevisionIs != 0 && req.IfRevisionIs != bk.Revision {
+		return nil, &clients.B2Error{Status: 409, Code: clients.B2ErrorCodeConflict, Message: "ifRevisionIs does not match"}
+	}
+	if req.DefaultRetention != nil && !bk.FileLockEnabled {
+		return nil, &clients.B2Error{Status: 400, Code: "bad_request", Message: "file lock is not enabled on this bucket"}
+	}
+	if req.BucketType != "" {
+		bk.Type = req.BucketType
+	}
+	if req.DefaultServerSideEncryption != nil {
+		bk.Encryption = copyEncryption(req.DefaultServerSideEncryption)
+	}
+	if req.ReplicationConfiguration != nil {
+		bk.Replication = copyReplication(req.ReplicationConfiguration)
+	}
+	if req.DefaultRetention != nil {
+		bk.Retention = copyRetention(req.DefaultRetention)
+	}
+	if req.BucketInfo != nil {
+		bk.Info = copyInfo(req.BucketInfo)
+	}
+	bk.CORSRules = append([]clients.B2CORSRule(nil), req.CORSRules...)
+	bk.LifecycleRules = append([]clients.B2LifecycleRule(nil), req.LifecycleRules...)
+	bk.Revision++
+	return b2Bucket(bk), nil
+}
+
+// bucketByID returns the bucket with the supplied ID. Callers must hold b.mu.
+func (b *Backend) bucketByID(id string) *Bucket {
+	for _, bk := range b.buckets {
+		if bk.ID == id {
+			return bk
+		}
+	}
+	return nil
+}
+
+// GetBucketNotificationRules implements clients.Client.
+func (b *Backend) GetBucketNotificationRules(_ context.Context, bucketID string) ([]clients.B2NotificationRule, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetBucketNotificationRules", bucketID); err != nil {
+		return nil, err
+	}
+	bk := b.bucketByID(bucketID)
+	if bk == nil {
+		return nil, &clients.B2Error{Status: 400, Code: clients.B2ErrorCodeBadBucketID, Message: "Invalid bucketId: " + bucketID}
+	}
+	return copyNotificationRules(bk.NotificationRules), nil
+}
+
+// SetBucketNotificationRules implements clients.Client. Setting a rule clears
+// its suspension, as it does in B2, and changes the bucket revision.
+func (b *Backend) SetBucketNotificationRules(_ context.Context, bucketID string, rules []clients.B2NotificationRule, ifRevisionIs int) ([]clients.B2NotificationRule, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("SetBucketNotificationRules", bucketID); err != nil {
+		return nil, err
+	}
+	bk := b.bucketByID(bucketID)
+	if bk == nil {
+		return nil, &clients.B2Error{Status: 400, Code: clients.B2ErrorCodeBadBucketID, Message: "Invalid bucketId: " + bucketID}
+	}
+	if ifRevisionIs != 0 && ifRevisionIs != bk.Revision {
+		return nil, &clients.B2Error{Status: 409, Code: clients.B2ErrorCodeConflict, Message: "ifRevisionIs does not match"}
+	}
+	bk.NotificationRules = copyNotificationRules(rules)
+	for i := range bk.NotificationRules {
+		bk.NotificationRules[i].IsSuspended = false
+		bk.NotificationRules[i].SuspensionReason = ""
+	}
+	bk.Revision++
+	return copyNotificationRules(bk.NotificationRules), nil
+}
+
+func copyNotificationRules(in []clients.B2NotificationRule) []clients.B2NotificationRule {
+	out := make([]clients.B2NotificationRule, len(in))
+	for i, r := range in {
+		r.EventTypes = append([]string(nil), r.EventTypes...)
+		r.TargetConfiguration.CustomHeaders = append([]clients.B2NotificationHeader(nil), r.TargetConfiguration.CustomHeaders...)
+		out[i] = r
+	}
+	return out
+}
+
+func copyInfo(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+// CreateApplicationKey implements clients.Client.
+func (b *Backend) CreateApplicationKey(_ context.Context, keyName string, capabilities []string, bucketIDs []string, namePrefix string, validDurationInSeconds *int) (*clients.B2CreateKeyResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("CreateApplicationKey", keyName, strings.Join(bucketIDs, ","), namePrefix); err != nil {
+		return nil, err
+	}
+	for _, id := range bucketIDs {
+		if b.bucketByID(id) == nil {
+			return nil, &clients.B2Error{Status: 400, Code: "bad_request", Message: "Invalid bucketId: " + id}
+		}
+	}
+	b.nextKey++
+	k := &clients.B2CreateKeyResponse{
+		ApplicationKeyID: fmt.Sprintf("K005%012d", b.nextKey),
+		ApplicationKey:   fmt.Sprintf("K005secret%022d", b.nextKey),
+		KeyName:          keyName,
+		Capabilities:     append([]string(nil), capabilities...),
+		AccountID:        DefaultAccountID,
+		BucketIDs:        append([]string(nil), bucketIDs...),
+		NamePrefix:       namePrefix,
+	}
+	if validDurationInSeconds != nil {
+		exp := time.Now().Add(time.Duration(*validDurationInSeconds) * time.Second).UnixMilli()
+		k.ExpirationTimestamp = &exp
+	}
+	b.keys[k.ApplicationKeyID] = k
+	resp := *k
+	return &resp, nil
+}
+
+// DeleteApplicationKey implements clients.Client.
+func (b *Backend) DeleteApplicationKey(_ context.Context, applicationKeyID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("DeleteApplicationKey", applicationKeyID); err != nil {
+		return err
+	}
+	if _, ok := b.keys[applicationKeyID]; !ok {
+		return clients.ErrApplicationKeyNotFound
+	}
+	delete(b.keys, applicationKeyID)
+	return nil
+}
+
+// GetApplicationKey implements clients.Client.
+func (b *Backend) GetApplicationKey(_ context.Context, applicationKeyID string) (*clients.B2CreateKeyResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetApplicationKey", applicationKeyID); err != nil {
+		return nil, err
+	}
+	k, ok := b.keys[applicationKeyID]
+	if !ok {
+		return nil, clients.ErrApplicationKeyNotFound
+	}
+	resp := *k
+	resp.ApplicationKey = ""
+	return &resp, nil
+}
+
+// GetApplicationKeyByName implements clients.Client.
+func (b *Backend) GetApplicationKeyByName(_ context.Context, keyName string) (*clients.B2CreateKeyResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetApplicationKeyByName", keyName); err != nil {
+		return nil, err
+	}
+	var found []*clients.B2CreateKeyResponse
+	for _, k := range b.keys {
+		if k.KeyName == keyName {
+			found = append(found, k)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, clients.ErrApplicationKeyNotFound
+	case 1:
+		resp := *found[0]
+		resp.ApplicationKey = ""
+		return &resp, nil
+	default:
+		return nil, errors.Errorf("%d application keys are named %q", len(found), keyName)
+	}
+}
+
+// GetApplicationKeysByName implements clients.Client.
+func (b *Backend) GetApplicationKeysByName(_ context.Context, keyName string) ([]clients.B2CreateKeyResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetApplicationKeysByName", keyName); err != nil {
+		return nil, err
+	}
+	var found []clients.B2CreateKeyResponse
+	for _, k := range b.keys {
+		if k.KeyName == keyName {
+			resp := *k
+			resp.ApplicationKey = ""
+			found = append(found, resp)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].ApplicationKeyID < found[j].ApplicationKeyID })
+	return found, nil
+}
+
+// SetAuthorizedCapabilities sets the capabilities reported for the key the
+// provider is authorized with.
+func (b *Backend) SetAuthorizedCapabilities(caps []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.capabilities = append([]string(nil), caps...)
+}
+
+// AuthorizedCapabilities implements clients.Client.
+func (b *Backend) AuthorizedCapabilities(_ context.Context) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("AuthorizedCapabilities"); err != nil {
+		return nil, err
+	}
+	return append([]string(nil), b.capabilities...), nil
+}
+
+// GetBucketPolicy implements clients.Client.
+func (b *Backend) GetBucketPolicy(_ context.Context, bucketName string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("GetBucketPolicy", bucketName); err != nil {
+		return "", err
+	}
+	p, ok := b.policies[bucketName]
+	if !ok {
+		return "", errNoSuchBucketPolicy
+	}
+	return p, nil
+}
+
+// PutBucketPolicy implements clients.Client.
+func (b *Backend) PutBucketPolicy(_ context.Context, bucketName, policy string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("PutBucketPolicy", bucketName); err != nil {
+		return err
+	}
+	if _, ok := b.buckets[bucketName]; !ok {
+		return errors.New("NoSuchBucket")
+	}
+	b.policies[bucketName] = policy
+	return nil
+}
+
+// DeleteBucketPolicy implements clients.Client.
+func (b *Backend) DeleteBucketPolicy(_ context.Context, bucketName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.record("DeleteBucketPolicy", bucketName); err != nil {
+		return err
+	}
+	if _, ok := b.policies[bucketName]; !ok {
+		return errNoSuchBucketPolicy
+	}
+	delete(b.policies, bucketName)
+	return nil
+}
+
+var _ clients.Client = &Backend{}
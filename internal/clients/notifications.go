@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// B2 Native API event notification endpoints, relative to the apiUrl
+	// returned by b2_authorize_account.
+	B2GetBucketNotificationRulesPath = "/b2api/v3/b2_get_bucket_notification_rules"
+	B2SetBucketNotificationRulesPath = "/b2api/v3/b2_set_bucket_notification_rules"
+
+	// B2NotificationTargetWebhook is the only supported notification target.
+	B2NotificationTargetWebhook = "webhook"
+)
+
+// B2NotificationHeader is a custom HTTP header sent with every notification.
+type B2NotificationHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// B2NotificationTarget is where notifications are sent.
+type B2NotificationTarget struct {
+	TargetType              string                 `json:"targetType"`
+	URL                     string                 `json:"url"`
+	CustomHeaders           []B2NotificationHeader `json:"customHeaders,omitempty"`
+	HmacSha256SigningSecret string                 `json:"hmacSha256SigningSecret,omitempty"`
+}
+
+// B2NotificationRule is an event notification rule as represented by the B2
+// Native API. IsSuspended and SuspensionReason are set by B2 and are ignored
+// when rules are set.
+type B2NotificationRule struct {
+	Name                string               `json:"name"`
+	EventTypes          []string             `json:"eventTypes"`
+	IsEnabled           bool                 `json:"isEnabled"`
+	ObjectNamePrefix    string               `json:"objectNamePrefix"`
+	TargetConfiguration B2NotificationTarget `json:"targetConfiguration"`
+	IsSuspended         bool                 `json:"isSuspended,omitempty"`
+	SuspensionReason    string               `json:"suspensionReason,omitempty"`
+}
+
+// B2BucketNotificationRules is the request to set, and the response listing,
+// the notification rules of a bucket. If IfRevisionIs is set the rules are
+// only set if the bucket is still at that revision, so that rules added
+// since the bucket was observed aren't lost.
+type B2BucketNotificationRules struct {
+	BucketID               string               `json:"bucketId"`
+	EventNotificationRules []B2NotificationRule `json:"eventNotificationRules"`
+	IfRevisionIs           int                  `json:"ifRevisionIs,omitempty"`
+}
+
+// GetBucketNotificationRules returns the event notification rules of the
+// supplied bucket.
+func (c *BackblazeClient) GetBucketNotificationRules(ctx context.Context, bucketID string) ([]B2NotificationRule, error) {
+	if err := c.authorizeAccount(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to authorize account")
+	}
+
+	req := struct {
+		BucketID string `json:"bucketId"`
+	}{BucketID: bucketID}
+
+	var resp B2BucketNotificationRules
+	if err := c.callB2(ctx, B2GetBucketNotificationRulesPath, req, &resp); err != nil {
+		return nil, errors.Wrap(err, "failed to get bucket notification rules")
+	}
+
+	return resp.EventNotificationRules, nil
+}
+
+// SetBucketNotificationRules replaces all event notification rules of the
+// supplied bucket, and returns the rules as stored by B2. If ifRevisionIs is
+// not zero the rules are only replaced if the bucket is at that revision.
+func (c *BackblazeClient) SetBucketNotificationRules(ctx context.Context, bucketID string, rules []B2NotificationRule, ifRevisionIs int) ([]B2NotificationRule, error) {
+	if err := c.authorizeAccount(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to authorize account")
+	}
+
+	req := B2BucketNotificationRules{
+		BucketID:               bucketID,
+		EventNotificationRules: make([]B2NotificationRule, len(rules)),
+		IfRevisionIs:           ifRevisionIs,
+	}
+	for i, r := range rules {
+		r.IsSuspended = false
+		r.SuspensionReason = ""
+		req.EventNotificationRules[i] = r
+	}
+
+	var resp B2BucketNotificationRules
+	if err := c.callB2(ctx, B2SetBucketNotificationRulesPath, req, &resp); err != nil {
+		return nil, errors.Wrap(err, "failed to set bucket notification rules")
+	}
+
+	return resp.EventNotificationRules, nil
+}
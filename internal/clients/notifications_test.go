@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGetBucketNotificationRules(t *testing.T) {
+	c := newTestB2Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != B2GetBucketNotificationRulesPath {
+			t.Errorf("path: want %s, got %s", B2GetBucketNotificationRulesPath, r.URL.Path)
+		}
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("cannot decode request: %v", err)
+		}
+		if req["bucketId"] != "bucket-id" {
+			t.Errorf("bucketId: want bucket-id, got %v", req["bucketId"])
+		}
+		_ = json.NewEncoder(w).Encode(B2BucketNotificationRules{
+			BucketID: "bucket-id",
+			EventNotificationRules: []B2NotificationRule{{
+				Name:             "uploads",
+				EventTypes:       []string{"b2:ObjectCreated:*"},
+				IsSuspended:      true,
+				SuspensionReason: "webhook failed",
+			}},
+		})
+	})
+
+	got, err := c.GetBucketNotificationRules(context.Background(), "bucket-id")
+	if err != nil {
+		t.Fatalf("GetBucketNotificationRules() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "uploads" || !got[0].IsSuspended {
+		t.Errorf("GetBucketNotificationRules() = %+v", got)
+	}
+}
+
+func TestSetBucketNotificationRules(t *testing.T) {
+	c := newTestB2Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != B2SetBucketNotificationRulesPath {
+			t.Errorf("path: want %s, got %s", B2SetBucketNotificationRulesPath, r.URL.Path)
+		}
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("cannot decode request: %v", err)
+		}
+		rules, ok := req["eventNotificationRules"].([]interface{})
+		if !ok {
+			t.Fatalf("eventNotificationRules: want an array, got %v", req["eventNotificationRules"])
+		}
+		if req["ifRevisionIs"] != float64(3) {
+			t.Errorf("ifRevisionIs: want 3, got %v", req["ifRevisionIs"])
+		}
+		// Rules must always be sent, so that the last one can be removed.
+		for _, rule := range rules {
+			if _, ok := rule.(map[string]interface{})["isSuspended"]; ok {
+				t.Errorf("isSuspended is set by B2 and must not be sent: %v", rule)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(B2BucketNotificationRules{BucketID: "bucket-id", EventNotificationRules: []B2NotificationRule{}})
+	})
+
+	_, err := c.SetBucketNotificationRules(context.Background(), "bucket-id", []B2NotificationRule{{
+		Name:        "uploads",
+		EventTypes:  []string{"b2:ObjectCreated:*"},
+		IsSuspended: true,
+	}}, 3)
+	if err != nil {
+		t.Fatalf("SetBucketNotificationRules() error = %v", err)
+	}
+
+	if _, err := c.SetBucketNotificationRules(context.Background(), "bucket-id", nil, 3); err != nil {
+		t.Fatalf("SetBucketNotificationRules() with no rules error = %v", err)
+	}
+}
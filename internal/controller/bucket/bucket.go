@@ -18,20 +18,31 @@ package bucket
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
 	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
 	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/features"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+	"github.com/rossigee/provider-backblaze/internal/scope"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,25 +52,71 @@ import (
 )
 
 const (
-	errNotBucket     = "managed resource is not a Bucket custom resource"
-	errTrackPCUsage  = "cannot track ProviderConfig usage"
-	errGetPC         = "cannot get ProviderConfig"
-	errGetCreds      = "cannot get credentials"
-	errNewClient     = "cannot create new Service"
-	errCreateBucket  = "cannot create bucket"
-	errDeleteBucket  = "cannot delete bucket"
-	errObserveBucket = "cannot observe bucket"
+	errNotBucket        = "managed resource is not a Bucket custom resource"
+	errTrackPCUsage     = "cannot track ProviderConfig usage"
+	errGetPC            = "cannot get ProviderConfig"
+	errGetCreds         = "cannot get credentials"
+	errNewClient        = "cannot create new Service"
+	errCreateBucket     = "cannot create bucket"
+	errDeleteBucket     = "cannot delete bucket"
+	errObserveBucket    = "cannot observe bucket"
+	errUpdateBucket     = "cannot update bucket"
+	errGetLocation      = "cannot get bucket location"
+	errFmtNotFound      = "bucket %q does not exist and managementPolicies do not allow it to be created"
+	errFmtNotManaged    = "bucket %q already exists in this account but is not managed by this resource: set the crossplane.io/external-name annotation to %q to import it"
+	errFmtNameTaken     = "bucket name %q is already in use by another Backblaze account"
+	errRegionChanged    = "bucket is located in region %q but spec.forProvider.region is %q: buckets cannot be moved between regions"
+	errFileLockChanged  = "file lock is %s on the bucket but spec.forProvider.fileLockEnabled is %t: file lock can only be set when a bucket is created"
+	errListLocked       = "cannot check for locked objects"
+	errFmtObjectsLocked = "bucket %q still contains objects under retention or legal hold, such as %q: it can't be emptied until they expire or are released"
+	errResolveRefs      = "cannot resolve references"
+	errFmtGetRef        = "cannot get referenced %s %q"
+	errFmtRefNotReady   = "referenced %s %q does not have an ID yet"
+	errEmptyBucket      = "cannot delete objects in bucket"
+	errAddFinalizer     = "cannot add finalizer"
+	errRemFinalizer     = "cannot remove finalizer"
+	errFmtTooMuchInfo   = "bucket would have %d bucketInfo entries but B2 allows at most %d: remove entries from spec.forProvider.bucketInfo, the ProviderConfig's defaultBucketInfo or the bucket"
+
+	// Bucket info entries added by the ProviderConfig's defaultBucketInfo.
+	bucketInfoKeyCluster   = "crossplane-cluster"
+	bucketInfoKeyNamespace = "crossplane-namespace"
+	bucketInfoKeyName      = "crossplane-name"
+
+	// maxBucketInfo is the number of bucket info entries B2 allows.
+	maxBucketInfo = 10
+
+	// finalizerName blocks deletion of a Bucket until the external bucket
+	// has been deleted or orphaned.
+	finalizerName = "finalizer.managedresource.crossplane.io"
 )
 
-// SetupBucket adds a controller that reconciles Bucket managed resources.
+// SetupBucket adds controllers that reconcile cluster scoped and namespaced
+// Bucket managed resources.
 func SetupBucket(mgr ctrl.Manager, o controller.Options) error {
-	r := &BucketReconciler{
-		Client: mgr.GetClient(),
+	for _, s := range []scope.Scope{scope.Cluster, scope.Namespaced} {
+		r := &BucketReconciler{
+			Client:      mgr.GetClient(),
+			NewClientFn: clients.NewClient,
+			Scope:       s,
+		}
+		if err := r.SetupWithManager(mgr, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWithManager registers the reconciler with the supplied manager.
+func (r *BucketReconciler) SetupWithManager(mgr ctrl.Manager, o controller.Options) error {
+	r.ManagementPoliciesEnabled = o.Features.Enabled(features.EnableAlphaManagementPolicies)
+	name := r.Scope.ControllerName("bucket")
+	if r.Recorder == nil {
+		r.Recorder = recorder.New(mgr.GetEventRecorder(name))
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
-		Named("bucket-controller").
-		For(&backblazev1.Bucket{}).
+		Named(name).
+		For(r.Scope.NewBucket()).
 		Watches(&apisv1beta1.ProviderConfig{}, handler.Funcs{}).
 		Complete(r)
 }
@@ -67,6 +124,20 @@ func SetupBucket(mgr ctrl.Manager, o controller.Options) error {
 // BucketReconciler reconciles a Bucket object
 type BucketReconciler struct {
 	Client client.Client
+
+	// NewClientFn creates the Backblaze client used to manage buckets.
+	NewClientFn clients.NewClientFn
+
+	// ManagementPoliciesEnabled enables support for the
+	// spec.managementPolicies of cluster scoped Buckets.
+	ManagementPoliciesEnabled bool
+
+	// Recorder records events about the external bucket, such as its
+	// creation, updates and deletion.
+	Recorder event.Recorder
+
+	// Scope of the Buckets reconciled.
+	Scope scope.Scope
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -75,7 +146,7 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 	logger := log.FromContext(ctx).WithValues("bucket", req.NamespacedName)
 
 	// Fetch the Bucket instance
-	bucket := &backblazev1.Bucket{}
+	bucket := r.Scope.NewBucket()
 	err := r.Client.Get(ctx, req.NamespacedName, bucket)
 	if err != nil {
 		if client.IgnoreNotFound(err) == nil {
@@ -87,10 +158,35 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 		return reconcile.Result{}, err
 	}
 
-	logger.Info("Reconciling bucket", "bucketName", bucket.Spec.ForProvider.BucketName)
+	logger.Info("Reconciling bucket", "bucketName", bucket.GetBucketName())
+
+	policy := scope.ManagementPolicies(r.ManagementPoliciesEnabled, bucket)
+	if err := policy.Validate(); err != nil {
+		logger.Error(err, "Invalid management policies")
+		r.setCondition(bucket, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{}, r.Client.Status().Update(ctx, bucket)
+	}
+
+	if meta.WasDeleted(bucket) {
+		return r.handleDeletion(ctx, bucket, policy)
+	}
+
+	if policy.IsPaused() {
+		logger.Info("Reconciliation is paused via the management policies")
+		r.setCondition(bucket, xpv1.TypeSynced, "False", "ReconcilePaused", "Reconciliation is paused via the management policies")
+		return reconcile.Result{}, r.Client.Status().Update(ctx, bucket)
+	}
+
+	if !meta.FinalizerExists(bucket, finalizerName) {
+		meta.AddFinalizer(bucket, finalizerName)
+		if err := r.Client.Update(ctx, bucket); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
 
 	// Get provider config and create client
-	service, err := r.getBackblazeClient(ctx, bucket)
+	service, pc, err := r.getBackblazeClient(ctx, bucket)
 	if err != nil {
 		logger.Error(err, "Failed to create Backblaze client")
 		r.setCondition(bucket, xpv1.TypeReady, "False", "ClientError", err.Error())
@@ -103,37 +199,142 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 		return reconcile.Result{RequeueAfter: requeueAfter}, r.Client.Status().Update(ctx, bucket)
 	}
 
-	// Check if bucket exists
-	bucketName := bucket.GetBucketName()
-	exists, err := service.BucketExists(ctx, bucketName)
+	if err := r.resolveReferences(ctx, bucket); err != nil {
+		logger.Info("Cannot resolve references, retrying in 10 seconds", "error", err)
+		r.setCondition(bucket, xpv1.TypeSynced, "False", "ReconcileError", errors.Wrap(err, errResolveRefs).Error())
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, r.Client.Status().Update(ctx, bucket)
+	}
+
+	bucketName := externalName(bucket)
+	info := desiredBucketInfo(bucket, pc.Spec.DefaultBucketInfo)
+	managedInfo := bucket.GetAtProvider().ManagedBucketInfo
+	observed, obs, err := r.observe(ctx, bucket, service, info, managedInfo)
 	if err != nil {
-		logger.Error(err, "Failed to check bucket existence")
+		logger.Error(err, "Failed to observe bucket")
+		r.Recorder.Event(bucket, recorder.Warning(recorder.ReasonCannotObserve, err))
 		r.setCondition(bucket, xpv1.TypeReady, "False", "CheckError", err.Error())
 		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
 	}
 
-	if !exists {
+	// Earlier releases didn't record the external name of the buckets they
+	// managed, so record it now rather than reporting them as NotManaged.
+	if obs.ResourceExists && meta.GetExternalName(bucket) == "" && managedByEarlierRelease(bucket) {
+		logger.Info("Adopting bucket created before external names were recorded", "bucketName", bucketName)
+		meta.SetExternalName(bucket, bucketName)
+		if err := r.Client.Update(ctx, bucket); err != nil {
+			logger.Error(err, "Failed to record external name")
+			return reconcile.Result{}, err
+		}
+	}
+
+	switch {
+	case !obs.ResourceExists && !policy.ShouldCreate():
+		logger.Info("Bucket does not exist and will not be created", "bucketName", bucketName)
+		r.setCondition(bucket, xpv1.TypeReady, "False", "NotFound", errors.Errorf(errFmtNotFound, bucketName).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+
+	case obs.ResourceExists && meta.GetExternalName(bucket) == "" && !policy.ShouldOnlyObserve():
+		// Only buckets created by this resource, or imported by setting the
+		// external name, may be changed or deleted.
+		logger.Info("Bucket exists but is not managed by this resource", "bucketName", bucketName)
+		r.setCondition(bucket, xpv1.TypeReady, "False", "NotManaged", errors.Errorf(errFmtNotManaged, bucketName, bucketName).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+
+	case !obs.ResourceExists:
+		// Record the external name before creating the bucket, so that a
+		// bucket created by an interrupted reconcile is still recognised
+		// as ours.
+		if meta.GetExternalName(bucket) == "" {
+			meta.SetExternalName(bucket, bucketName)
+			if err := r.Client.Update(ctx, bucket); err != nil {
+				logger.Error(err, "Failed to record external name")
+				return reconcile.Result{}, err
+			}
+		}
+
 		// Create bucket
 		logger.Info("Creating bucket", "bucketName", bucketName)
-		bucketType := bucket.Spec.ForProvider.BucketType
-		if bucketType == "" {
-			bucketType = "allPrivate"
+		params := bucket.GetForProvider()
+		err = service.CreateBucket(ctx, bucketName, bucketType(*params), params.Region, params.FileLockEnabled)
+		if clients.IsBucketNameTaken(err) {
+			logger.Info("Bucket name is in use by another account", "bucketName", bucketName)
+			r.Recorder.Event(bucket, recorder.Warning(recorder.ReasonCannotCreate, errors.Wrap(err, errCreateBucket)))
+			r.setCondition(bucket, xpv1.TypeReady, "False", "NameTaken", errors.Errorf(errFmtNameTaken, bucketName).Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
 		}
-
-		err = service.CreateBucket(ctx, bucketName, bucketType, bucket.Spec.ForProvider.Region)
 		if err != nil {
 			logger.Error(err, "Failed to create bucket")
+			r.Recorder.Event(bucket, recorder.Warning(recorder.ReasonCannotCreate, errors.Wrap(err, errCreateBucket)))
 			r.setCondition(bucket, xpv1.TypeReady, "False", "CreateError", err.Error())
 			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
 		}
+		r.Recorder.Event(bucket, event.Normal(recorder.ReasonCreated, fmt.Sprintf("Created bucket %q", bucketName)))
 
-		// Set external name
-		meta.SetExternalName(bucket, bucketName)
+		// Lifecycle and CORS rules can't be set by the S3 CreateBucket call,
+		// so observe the new bucket and apply them as an update.
+		observed, obs, err = r.observe(ctx, bucket, service, info, managedInfo)
+		if err != nil || !obs.ResourceExists {
+			logger.Info("Created bucket is not yet visible, retrying in 10 seconds", "error", err)
+			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+
+	// The region and file lock can only be chosen at creation, so a mismatch
+	// can't be corrected by an update. Observed buckets may be in any region.
+	location, err := service.GetBucketLocation(ctx, bucketName)
+	if err != nil {
+		logger.Error(err, "Failed to get bucket location")
+		r.Recorder.Event(bucket, recorder.Warning(recorder.ReasonCannotObserve, errors.Wrap(err, errGetLocation)))
+		r.setCondition(bucket, xpv1.TypeReady, "False", "CheckError", errors.Wrap(err, errGetLocation).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+	}
+	*bucket.GetAtProvider() = generateObservation(observed, location)
+	bucket.GetAtProvider().ManagedBucketInfo = managedInfo
+	err = checkRegion(*bucket.GetForProvider(), location, pc.Spec.BackblazeRegion)
+	if err == nil {
+		err = checkFileLock(*bucket.GetForProvider(), observed)
+	}
+	if err != nil && !policy.ShouldOnlyObserve() {
+		logger.Info("Bucket does not match immutable parameters", "reason", err.Error())
+		r.setCondition(bucket, xpv1.TypeReady, "True", "Available", "Bucket is ready")
+		r.setCondition(bucket, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+	}
+
+	merged := bucketInfo(info, managedInfo, observed)
+	if len(merged) > maxBucketInfo && policy.ShouldUpdate() {
+		err := errors.Errorf(errFmtTooMuchInfo, len(merged), maxBucketInfo)
+		logger.Info("Bucket info has too many entries", "reason", err.Error())
+		r.setCondition(bucket, xpv1.TypeReady, "True", "Available", "Bucket is ready")
+		r.setCondition(bucket, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+	}
+
+	if !obs.ResourceUpToDate && policy.ShouldUpdate() {
+		logger.Info("Bucket is not up to date, updating", "bucketName", bucketName, "revision", observed.Revision)
+		updated, err := service.UpdateBucket(ctx, generateUpdateBucketRequest(*bucket.GetForProvider(), merged, observed))
+		if err != nil {
+			logger.Error(err, "Failed to update bucket")
+			r.Recorder.Event(bucket, recorder.Warning(recorder.ReasonCannotUpdate, errors.Wrap(err, errUpdateBucket)))
+			r.setCondition(bucket, xpv1.TypeSynced, "False", "ReconcileError", errors.Wrap(err, errUpdateBucket).Error())
+			// A revision conflict means the bucket changed after it was
+			// observed. Observe it again promptly rather than backing off.
+			requeueAfter := time.Minute
+			if clients.IsRevisionConflict(err) {
+				requeueAfter = 5 * time.Second
+			}
+			return reconcile.Result{RequeueAfter: requeueAfter}, r.Client.Status().Update(ctx, bucket)
+		}
+		*bucket.GetAtProvider() = generateObservation(updated, location)
+		r.Recorder.Event(bucket, event.Normal(recorder.ReasonUpdated, fmt.Sprintf("Updated bucket %q", bucketName)))
+	}
+	if policy.ShouldUpdate() {
+		bucket.GetAtProvider().ManagedBucketInfo = bucketInfoKeys(info)
 	}
 
 	// Update status
-	bucket.Status.AtProvider.BucketName = bucketName
 	r.setCondition(bucket, xpv1.TypeReady, "True", "Available", "Bucket is ready")
+	r.setCondition(bucket, xpv1.TypeSynced, "True", "ReconcileSuccess", "Successfully reconciled")
 
 	// Update the resource
 	if err := r.Client.Status().Update(ctx, bucket); err != nil {
@@ -145,36 +346,193 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
-func (r *BucketReconciler) getBackblazeClient(ctx context.Context, bucket *backblazev1.Bucket) (*clients.BackblazeClient, error) {
-	// Determine ProviderConfig name - use "default" if not specified
-	providerConfigName := "default"
-	if bucket.GetProviderConfigReference() != nil {
-		providerConfigName = bucket.GetProviderConfigReference().Name
+// observe returns the external bucket, and whether it exists and matches the
+// desired state.
+func (r *BucketReconciler) observe(ctx context.Context, bucket scope.Bucket, service clients.Client, info map[string]string, managedInfo []string) (*clients.B2Bucket, managed.ExternalObservation, error) {
+	observed, err := service.GetBucket(ctx, externalName(bucket))
+	if err != nil {
+		return nil, managed.ExternalObservation{}, errors.Wrap(err, errObserveBucket)
+	}
+	if observed == nil {
+		return nil, managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	return observed, managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(*bucket.GetForProvider(), bucketInfo(info, managedInfo, observed), observed),
+	}, nil
+}
+
+// resolveReferences sets the IDs of the Users and Buckets referenced by the
+// replication configuration, and persists them if they changed. References
+// are resolved in the scope, and namespace, of the Bucket.
+func (r *BucketReconciler) resolveReferences(ctx context.Context, bucket scope.Bucket) error {
+	rc := bucket.GetForProvider().ReplicationConfiguration
+	if rc == nil || rc.AsSource == nil {
+		return nil
+	}
+
+	changed := false
+	src := rc.AsSource
+	if ref := src.SourceApplicationKeyRef; ref != nil {
+		user := r.Scope.NewUser()
+		if err := r.Client.Get(ctx, scope.ObjectKey(bucket, ref.Name), user); err != nil {
+			return errors.Wrapf(err, errFmtGetRef, backblazev1.UserKind, ref.Name)
+		}
+		id := user.GetAtProvider().ApplicationKeyID
+		if id == "" {
+			return errors.Errorf(errFmtRefNotReady, backblazev1.UserKind, ref.Name)
+		}
+		if ptr.Deref(src.SourceApplicationKeyID, "") != id {
+			src.SourceApplicationKeyID = ptr.To(id)
+			changed = true
+		}
 	}
 
-	pc := &apisv1beta1.ProviderConfig{}
-	// ProviderConfigs are namespaced resources - look in the same namespace as the provider
-	key := client.ObjectKey{Name: providerConfigName, Namespace: "crossplane-system"}
-	if err := r.Client.Get(ctx, key, pc); err != nil {
+	for i := range src.Rules {
+		ref := src.Rules[i].DestinationBucketRef
+		if ref == nil {
+			continue
+		}
+		dest := r.Scope.NewBucket()
+		if err := r.Client.Get(ctx, scope.ObjectKey(bucket, ref.Name), dest); err != nil {
+			return errors.Wrapf(err, errFmtGetRef, backblazev1.BucketKind, ref.Name)
+		}
+		id := dest.GetAtProvider().BucketID
+		if id == "" {
+			return errors.Errorf(errFmtRefNotReady, backblazev1.BucketKind, ref.Name)
+		}
+		if ptr.Deref(src.Rules[i].DestinationBucketID, "") != id {
+			src.Rules[i].DestinationBucketID = ptr.To(id)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.Client.Update(ctx, bucket)
+}
+
+func (r *BucketReconciler) handleDeletion(ctx context.Context, bucket scope.Bucket, policy managed.ManagementPoliciesChecker) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !meta.FinalizerExists(bucket, finalizerName) {
+		return reconcile.Result{}, nil
+	}
+
+	// A bucket that this resource neither created nor imported is never
+	// deleted.
+	if policy.ShouldDelete() && (meta.GetExternalName(bucket) != "" || managedByEarlierRelease(bucket)) {
+		service, _, err := r.getBackblazeClient(ctx, bucket)
+		if err != nil {
+			logger.Error(err, "Failed to create Backblaze client")
+			r.setCondition(bucket, xpv1.TypeReady, "False", "ClientError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+		}
+
+		if err := r.deleteBucket(ctx, bucket, service); err != nil {
+			logger.Error(err, "Failed to delete bucket")
+			r.Recorder.Event(bucket, recorder.Warning(recorder.ReasonCannotDelete, err))
+			reason := "DeleteError"
+			var locked *objectsLockedError
+			if errors.As(err, &locked) {
+				reason = "ObjectsLocked"
+			}
+			r.setCondition(bucket, xpv1.TypeReady, "False", reason, err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bucket)
+		}
+	}
+
+	meta.RemoveFinalizer(bucket, finalizerName)
+	if err := r.Client.Update(ctx, bucket); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errRemFinalizer)
+	}
+
+	logger.Info("Bucket deletion handled")
+	return reconcile.Result{}, nil
+}
+
+// managedByEarlierRelease returns true if the supplied Bucket was reconciled
+// by a release that didn't record external names. Those releases only set
+// status.atProvider.bucketName, while later ones also set its bucketId.
+func managedByEarlierRelease(bucket scope.Bucket) bool {
+	o := bucket.GetAtProvider()
+	return o.BucketName != "" && o.BucketName == bucket.GetBucketName() && o.BucketID == ""
+}
+
+// objectsLockedError is returned when a bucket can't be emptied because some
+// of its objects are protected by file lock.
+type objectsLockedError struct {
+	bucketName string
+	key        string
+}
+
+func (e *objectsLockedError) Error() string {
+	return fmt.Sprintf(errFmtObjectsLocked, e.bucketName, e.key)
+}
+
+// deleteBucket deletes the external bucket, emptying it first if the
+// BucketDeletionPolicy allows it. A bucket with file lock enabled is only
+// emptied once none of its objects are locked, so that a deletion never
+// leaves it partially purged. A bucket that no longer exists in the account
+// is not an error.
+func (r *BucketReconciler) deleteBucket(ctx context.Context, bucket scope.Bucket, service clients.Client) error {
+	bucketName := externalName(bucket)
+
+	observed, err := service.GetBucket(ctx, bucketName)
+	if err != nil {
+		return errors.Wrap(err, errObserveBucket)
+	}
+	if observed == nil {
+		return nil
+	}
+
+	if bucket.GetForProvider().BucketDeletionPolicy == backblazev1.DeleteAll {
+		if mayHaveLockedObjects(observed) {
+			key, err := service.LockedObject(ctx, bucketName)
+			if err != nil {
+				return errors.Wrap(err, errListLocked)
+			}
+			if key != "" {
+				return &objectsLockedError{bucketName: bucketName, key: key}
+			}
+		}
+		if err := service.DeleteAllObjectsInBucket(ctx, bucketName); err != nil {
+			return errors.Wrap(err, errEmptyBucket)
+		}
+	}
+
+	if err := service.DeleteBucket(ctx, bucketName); err != nil {
+		return errors.Wrap(err, errDeleteBucket)
+	}
+	r.Recorder.Event(bucket, event.Normal(recorder.ReasonDeleted, fmt.Sprintf("Deleted bucket %q", bucketName)))
+	return nil
+}
+
+func (r *BucketReconciler) getBackblazeClient(ctx context.Context, bucket scope.Bucket) (clients.Client, *apisv1beta1.ProviderConfig, error) {
+	pc, err := scope.GetProviderConfig(ctx, r.Client, bucket)
+	if err != nil {
 		// Check if this is a "not found" error that could be due to cache sync timing
 		if client.IgnoreNotFound(err) == nil {
 			// ProviderConfig not found - this could be a cache sync issue
 			// Return a retriable error to allow reconciliation to retry
-			return nil, errors.Wrap(err, errGetPC)
+			return nil, nil, errors.Wrap(err, errGetPC)
 		}
 		// Other errors (permission, etc.) - return immediately
-		return nil, errors.Wrap(err, errGetPC)
+		return nil, nil, errors.Wrap(err, errGetPC)
 	}
 
 	cfg, err := clients.GetProviderConfig(ctx, r.Client, pc)
 	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+		return nil, nil, errors.Wrap(err, errGetCreds)
 	}
 
-	return clients.NewBackblazeClient(*cfg)
+	service, err := r.NewClientFn(*cfg)
+	return service, pc, err
 }
 
-func (r *BucketReconciler) setCondition(bucket *backblazev1.Bucket, conditionType xpv1.ConditionType, status, reason, message string) {
+func (r *BucketReconciler) setCondition(bucket scope.Bucket, conditionType xpv1.ConditionType, status, reason, message string) {
 	bucket.SetConditions(xpv1.Condition{
 		Type:               conditionType,
 		Status:             corev1.ConditionStatus(status),
@@ -183,3 +541,379 @@ func (r *BucketReconciler) setCondition(bucket *backblazev1.Bucket, conditionTyp
 		Message:            message,
 	})
 }
+
+// externalName returns the name of the external bucket. The external-name
+// annotation takes precedence over spec.forProvider.bucketName so that it
+// identifies imported buckets.
+func externalName(bucket scope.Bucket) string {
+	if en := meta.GetExternalName(bucket); en != "" {
+		return en
+	}
+	return bucket.GetBucketName()
+}
+
+// generateObservation returns the observable state of the supplied bucket.
+func generateObservation(observed *clients.B2Bucket, location string) backblazev1.BucketObservation {
+	return backblazev1.BucketObservation{
+		BucketName: observed.BucketName,
+		BucketID:   observed.BucketID,
+		AccountID:  observed.AccountID,
+		Region:     location,
+		BucketType: observed.BucketType,
+		BucketInfo: observed.BucketInfo,
+		Revision:   observed.Revision,
+
+		DefaultServerSideEncryption: generateEncryptionObservation(observed.DefaultServerSideEncryption),
+		FileLockEnabled:             fileLockEnabled(observed),
+		DefaultRetention:            generateRetentionObservation(observed.FileLockConfiguration),
+		Replication:                 generateReplicationObservation(observed.ReplicationConfiguration),
+	}
+}
+
+// checkRegion returns an error if the bucket's actual location differs from
+// the desired region. An unknown location or unset region is not checked.
+// Region used to default to us-west-001, so Buckets created before then have
+// it set even though they are in the ProviderConfig's region. That region is
+// therefore only enforced if the ProviderConfig is in it too.
+func checkRegion(p backblazev1.BucketParameters, location, pcRegion string) error {
+	if location == "" || p.Region == "" || location == p.Region {
+		return nil
+	}
+	if p.Region == clients.DefaultRegion && pcRegion != clients.DefaultRegion {
+		return nil
+	}
+	return errors.Errorf(errRegionChanged, location, p.Region)
+}
+
+// checkFileLock returns an error if file lock is enabled on the bucket but not
+// desired, or vice versa. It is not checked if the provider's key is not
+// authorized to read it.
+func checkFileLock(p backblazev1.BucketParameters, observed *clients.B2Bucket) error {
+	flc := observed.FileLockConfiguration
+	if flc == nil || !flc.IsClientAuthorizedToRead || flc.Value == nil {
+		return nil
+	}
+	if flc.Value.IsFileLockEnabled == p.FileLockEnabled {
+		return nil
+	}
+	state := "disabled"
+	if flc.Value.IsFileLockEnabled {
+		state = "enabled"
+	}
+	return errors.Errorf(errFileLockChanged, state, p.FileLockEnabled)
+}
+
+// fileLockEnabled returns true if file lock is known to be enabled on the
+// bucket.
+func fileLockEnabled(observed *clients.B2Bucket) bool {
+	flc := observed.FileLockConfiguration
+	return flc != nil && flc.Value != nil && flc.Value.IsFileLockEnabled
+}
+
+// mayHaveLockedObjects returns true unless file lock is known to be disabled
+// on the bucket.
+func mayHaveLockedObjects(observed *clients.B2Bucket) bool {
+	flc := observed.FileLockConfiguration
+	if flc == nil || !flc.IsClientAuthorizedToRead || flc.Value == nil {
+		return true
+	}
+	return flc.Value.IsFileLockEnabled
+}
+
+// isUpToDate returns true if the observed bucket matches the desired
+// parameters and bucket info. Nil bucket info is not managed.
+func isUpToDate(p backblazev1.BucketParameters, info map[string]string, observed *clients.B2Bucket) bool {
+	if observed.BucketType != bucketType(p) {
+		return false
+	}
+	if info != nil && !cmp.Equal(info, observed.BucketInfo, cmpopts.EquateEmpty()) {
+		return false
+	}
+	if !isEncryptionUpToDate(p.DefaultServerSideEncryption, observed.DefaultServerSideEncryption) {
+		return false
+	}
+	if !isRetentionUpToDate(p.DefaultRetention, observed.FileLockConfiguration) {
+		return false
+	}
+	if !isReplicationUpToDate(p.ReplicationConfiguration, observed.ReplicationConfiguration) {
+		return false
+	}
+	if !cmp.Equal(lifecycleRules(p), observed.LifecycleRules, cmpopts.EquateEmpty()) {
+		return false
+	}
+	return cmp.Equal(corsRules(p), observed.CORSRules, cmpopts.EquateEmpty())
+}
+
+// generateUpdateBucketRequest returns a request that makes the observed bucket
+// match the desired parameters and bucket info. The request only succeeds if
+// the bucket has not been changed since it was observed.
+func generateUpdateBucketRequest(p backblazev1.BucketParameters, info map[string]string, observed *clients.B2Bucket) *clients.B2UpdateBucketRequest {
+	return &clients.B2UpdateBucketRequest{
+		AccountID:      observed.AccountID,
+		BucketID:       observed.BucketID,
+		BucketType:     bucketType(p),
+		BucketInfo:     info,
+		CORSRules:      corsRules(p),
+		LifecycleRules: lifecycleRules(p),
+		IfRevisionIs:   observed.Revision,
+
+		DefaultServerSideEncryption: serverSideEncryption(p.DefaultServerSideEncryption),
+		DefaultRetention:            defaultRetention(p.DefaultRetention),
+		ReplicationConfiguration:    replicationConfiguration(p.ReplicationConfiguration),
+	}
+}
+
+// isEncryptionUpToDate returns true if the observed default encryption
+// matches the desired configuration. Unmanaged encryption, or encryption the
+// provider's key is not authorized to read, is considered up to date.
+func isEncryptionUpToDate(desired *backblazev1.ServerSideEncryption, observed *clients.B2EncryptionSetting) bool {
+	if desired == nil || observed == nil || !observed.IsClientAuthorizedToRead {
+		return true
+	}
+	current := observed.Value
+	if current == nil {
+		current = &clients.B2ServerSideEncryption{}
+	}
+	return cmp.Equal(serverSideEncryption(desired), current, cmpopts.EquateEmpty())
+}
+
+// serverSideEncryption returns the B2 representation of the supplied default
+// encryption, or nil if it is not managed.
+func serverSideEncryption(sse *backblazev1.ServerSideEncryption) *clients.B2ServerSideEncryption {
+	if sse == nil {
+		return nil
+	}
+	if sse.Mode == backblazev1.SSEModeNone {
+		return &clients.B2ServerSideEncryption{}
+	}
+	algorithm := sse.Algorithm
+	if algorithm == "" {
+		algorithm = "AES256"
+	}
+	return &clients.B2ServerSideEncryption{Mode: ptr.To(sse.Mode), Algorithm: algorithm}
+}
+
+// generateEncryptionObservation returns the observed default encryption, or
+// nil if it could not be read.
+func generateEncryptionObservation(observed *clients.B2EncryptionSetting) *backblazev1.ServerSideEncryption {
+	if observed == nil || !observed.IsClientAuthorizedToRead {
+		return nil
+	}
+	if observed.Value == nil || observed.Value.Mode == nil {
+		return &backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeNone}
+	}
+	return &backblazev1.ServerSideEncryption{Mode: *observed.Value.Mode, Algorithm: observed.Value.Algorithm}
+}
+
+// isRetentionUpToDate returns true if the observed default retention matches
+// the desired retention. Unmanaged retention, or retention the provider's key
+// is not authorized to read, is considered up to date.
+func isRetentionUpToDate(desired *backblazev1.DefaultRetention, observed *clients.B2FileLockConfiguration) bool {
+	if desired == nil || observed == nil || !observed.IsClientAuthorizedToRead {
+		return true
+	}
+	current := &clients.B2DefaultRetention{}
+	if observed.Value != nil && observed.Value.DefaultRetention != nil {
+		current = observed.Value.DefaultRetention
+	}
+	return cmp.Equal(defaultRetention(desired), current, cmpopts.EquateEmpty())
+}
+
+// defaultRetention returns the B2 representation of the supplied default
+// retention, or nil if it is not managed.
+func defaultRetention(r *backblazev1.DefaultRetention) *clients.B2DefaultRetention {
+	if r == nil {
+		return nil
+	}
+	return &clients.B2DefaultRetention{
+		Mode:   ptr.To(r.Mode),
+		Period: &clients.B2RetentionPeriod{Duration: r.Period.Duration, Unit: r.Period.Unit},
+	}
+}
+
+// generateRetentionObservation returns the observed default retention, or nil
+// if there is none or it could not be read.
+func generateRetentionObservation(observed *clients.B2FileLockConfiguration) *backblazev1.DefaultRetention {
+	if observed == nil || !observed.IsClientAuthorizedToRead || observed.Value == nil {
+		return nil
+	}
+	r := observed.Value.DefaultRetention
+	if r == nil || r.Mode == nil || r.Period == nil {
+		return nil
+	}
+	return &backblazev1.DefaultRetention{
+		Mode:   *r.Mode,
+		Period: backblazev1.RetentionPeriod{Duration: r.Period.Duration, Unit: r.Period.Unit},
+	}
+}
+
+// isReplicationUpToDate returns true if the observed replication configuration
+// matches the desired configuration. Unmanaged replication, or replication the
+// provider's key is not authorized to read, is considered up to date.
+func isReplicationUpToDate(desired *backblazev1.ReplicationConfiguration, observed *clients.B2ReplicationSetting) bool {
+	if desired == nil || observed == nil || !observed.IsClientAuthorizedToRead {
+		return true
+	}
+	current := observed.Value
+	if current == nil {
+		current = &clients.B2ReplicationConfiguration{}
+	}
+	byName := cmpopts.SortSlices(func(a, b clients.B2ReplicationRule) bool {
+		return a.ReplicationRuleName < b.ReplicationRuleName
+	})
+	return cmp.Equal(replicationConfiguration(desired), current, cmpopts.EquateEmpty(), byName)
+}
+
+// replicationConfiguration returns the B2 representation of the supplied
+// replication configuration, or nil if it is not managed. References must
+// already be resolved.
+func replicationConfiguration(rc *backblazev1.ReplicationConfiguration) *clients.B2ReplicationConfiguration {
+	if rc == nil {
+		return nil
+	}
+	out := &clients.B2ReplicationConfiguration{}
+	if src := rc.AsSource; src != nil {
+		rules := make([]clients.B2ReplicationRule, len(src.Rules))
+		for i, r := range src.Rules {
+			priority := r.Priority
+			if priority == 0 {
+				priority = 1
+			}
+			rules[i] = clients.B2ReplicationRule{
+				ReplicationRuleName:  r.Name,
+				DestinationBucketID:  ptr.Deref(r.DestinationBucketID, ""),
+				FileNamePrefix:       r.FileNamePrefix,
+				IncludeExistingFiles: r.IncludeExistingFiles,
+				IsEnabled:            ptr.Deref(r.Enabled, true),
+				Priority:             priority,
+			}
+		}
+		out.AsReplicationSource = &clients.B2ReplicationSource{
+			SourceApplicationKeyID: ptr.Deref(src.SourceApplicationKeyID, ""),
+			ReplicationRules:       rules,
+		}
+	}
+	if dst := rc.AsDestination; dst != nil {
+		out.AsReplicationDestination = &clients.B2ReplicationDestination{
+			SourceToDestinationKeyMapping: dst.SourceToDestinationKeyMapping,
+		}
+	}
+	return out
+}
+
+// generateReplicationObservation returns the observed replication
+// configuration, or nil if there is none or it could not be read.
+func generateReplicationObservation(observed *clients.B2ReplicationSetting) *backblazev1.ReplicationObservation {
+	if observed == nil || !observed.IsClientAuthorizedToRead || observed.Value == nil {
+		return nil
+	}
+	src, dst := observed.Value.AsReplicationSource, observed.Value.AsReplicationDestination
+	if src == nil && dst == nil {
+		return nil
+	}
+	obs := &backblazev1.ReplicationObservation{}
+	if src != nil {
+		obs.SourceApplicationKeyID = src.SourceApplicationKeyID
+		for _, r := range src.ReplicationRules {
+			state := backblazev1.ReplicationRuleDisabled
+			if r.IsEnabled {
+				state = backblazev1.ReplicationRuleEnabled
+			}
+			obs.Rules = append(obs.Rules, backblazev1.ReplicationRuleObservation{
+				Name:                r.ReplicationRuleName,
+				DestinationBucketID: r.DestinationBucketID,
+				FileNamePrefix:      r.FileNamePrefix,
+				Priority:            r.Priority,
+				State:               state,
+			})
+		}
+	}
+	if dst != nil {
+		obs.SourceToDestinationKeyMapping = dst.SourceToDestinationKeyMapping
+	}
+	return obs
+}
+
+// desiredBucketInfo returns the bucket info for the supplied bucket: the
+// ProviderConfig defaults, overridden by the bucket's own entries.
+func desiredBucketInfo(bucket scope.Bucket, defaults *apisv1beta1.DefaultBucketInfo) map[string]string {
+	info := map[string]string{}
+	if defaults != nil {
+		for k, v := range defaults.Tags {
+			info[k] = v
+		}
+		if defaults.ClusterName != "" {
+			info[bucketInfoKeyCluster] = defaults.ClusterName
+		}
+		if defaults.IncludeResourceName {
+			info[bucketInfoKeyName] = bucket.GetName()
+			if bucket.GetNamespace() != "" {
+				info[bucketInfoKeyNamespace] = bucket.GetNamespace()
+			}
+		}
+	}
+	for k, v := range bucket.GetForProvider().BucketInfo {
+		info[k] = v
+	}
+	return info
+}
+
+// bucketInfo returns the bucket info the observed bucket should have: the
+// observed entries, less those the provider set that are no longer desired,
+// with the desired entries merged in. Entries set by others, for example in
+// the B2 web console, are kept. It returns nil if bucket info is not managed.
+func bucketInfo(desired map[string]string, managedKeys []string, observed *clients.B2Bucket) map[string]string {
+	if len(desired) == 0 && len(managedKeys) == 0 {
+		return nil
+	}
+	info := make(map[string]string, len(observed.BucketInfo)+len(desired))
+	for k, v := range observed.BucketInfo {
+		if _, ok := desired[k]; ok || !slices.Contains(managedKeys, k) {
+			info[k] = v
+		}
+	}
+	maps.Copy(info, desired)
+	return info
+}
+
+// bucketInfoKeys returns the sorted keys of the supplied bucket info.
+func bucketInfoKeys(info map[string]string) []string {
+	if len(info) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(info))
+}
+
+func bucketType(p backblazev1.BucketParameters) string {
+	if p.BucketType == "" {
+		return "allPrivate"
+	}
+	return p.BucketType
+}
+
+func lifecycleRules(p backblazev1.BucketParameters) []clients.B2LifecycleRule {
+	rules := make([]clients.B2LifecycleRule, len(p.LifecycleRules))
+	for i, r := range p.LifecycleRules {
+		rules[i] = clients.B2LifecycleRule{
+			FileNamePrefix:            r.FileNamePrefix,
+			DaysFromUploadingToHiding: r.DaysFromUploadingToHiding,
+			DaysFromHidingToDeleting:  r.DaysFromHidingToDeleting,
+		}
+	}
+	return rules
+}
+
+func corsRules(p backblazev1.BucketParameters) []clients.B2CORSRule {
+	rules := make([]clients.B2CORSRule, len(p.CorsRules))
+	for i, r := range p.CorsRules {
+		rules[i] = clients.B2CORSRule{
+			CorsRuleName:      r.CorsRuleName,
+			AllowedOrigins:    r.AllowedOrigins,
+			AllowedOperations: r.AllowedMethods,
+			AllowedHeaders:    r.AllowedHeaders,
+			ExposeHeaders:     r.ExposeHeaders,
+			MaxAgeSeconds:     ptr.Deref(r.MaxAgeSeconds, 0),
+		}
+	}
+	return rules
+}
@@ -20,11 +20,15 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 // BackblazeClientInterface defines the interface for Backblaze client operations
@@ -567,3 +571,545 @@ func TestObserveWithWrongType(t *testing.T) {
 		t.Errorf("Expected error %q, got %q", errNotBucket, err.Error())
 	}
 }
+
+func TestIsUpToDate(t *testing.T) {
+	days := func(d int) *int { return &d }
+
+	tests := map[string]struct {
+		params   backblazev1.BucketParameters
+		info     map[string]string
+		observed *clients.B2Bucket
+		want     bool
+	}{
+		"UpToDate": {
+			params: backblazev1.BucketParameters{
+				BucketType:     "allPrivate",
+				LifecycleRules: []backblazev1.LifecycleRule{{FileNamePrefix: "logs/", DaysFromHidingToDeleting: days(7)}},
+				CorsRules: []backblazev1.CORSRule{{
+					CorsRuleName:   "downloads",
+					AllowedOrigins: []string{"https://example.com"},
+					AllowedMethods: []string{"s3_get"},
+					MaxAgeSeconds:  days(3600),
+				}},
+			},
+			observed: &clients.B2Bucket{
+				BucketType:     "allPrivate",
+				LifecycleRules: []clients.B2LifecycleRule{{FileNamePrefix: "logs/", DaysFromHidingToDeleting: days(7)}},
+				CORSRules: []clients.B2CORSRule{{
+					CorsRuleName:      "downloads",
+					AllowedOrigins:    []string{"https://example.com"},
+					AllowedOperations: []string{"s3_get"},
+					MaxAgeSeconds:     3600,
+				}},
+			},
+			want: true,
+		},
+		"DefaultBucketType": {
+			params:   backblazev1.BucketParameters{},
+			observed: &clients.B2Bucket{BucketType: "allPrivate"},
+			want:     true,
+		},
+		"BucketTypeChanged": {
+			params:   backblazev1.BucketParameters{BucketType: "allPrivate"},
+			observed: &clients.B2Bucket{BucketType: "allPublic"},
+			want:     false,
+		},
+		"LifecycleRuleChanged": {
+			params: backblazev1.BucketParameters{
+				LifecycleRules: []backblazev1.LifecycleRule{{FileNamePrefix: "logs/", DaysFromHidingToDeleting: days(7)}},
+			},
+			observed: &clients.B2Bucket{
+				BucketType:     "allPrivate",
+				LifecycleRules: []clients.B2LifecycleRule{{FileNamePrefix: "logs/", DaysFromHidingToDeleting: days(30)}},
+			},
+			want: false,
+		},
+		"BucketInfoChanged": {
+			info: map[string]string{"cost-center": "1234"},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				BucketInfo: map[string]string{"cost-center": "5678"},
+			},
+			want: false,
+		},
+		"BucketInfoNotManaged": {
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				BucketInfo: map[string]string{"cost-center": "5678"},
+			},
+			want: true,
+		},
+		"BucketInfoCleared": {
+			info: map[string]string{},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				BucketInfo: map[string]string{"cost-center": "5678"},
+			},
+			want: false,
+		},
+		"EncryptionDisabled": {
+			params: backblazev1.BucketParameters{
+				DefaultServerSideEncryption: &backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeB2},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				DefaultServerSideEncryption: &clients.B2EncryptionSetting{
+					IsClientAuthorizedToRead: true,
+					Value:                    &clients.B2ServerSideEncryption{},
+				},
+			},
+			want: false,
+		},
+		"EncryptionEnabled": {
+			params: backblazev1.BucketParameters{
+				DefaultServerSideEncryption: &backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeB2, Algorithm: "AES256"},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				DefaultServerSideEncryption: &clients.B2EncryptionSetting{
+					IsClientAuthorizedToRead: true,
+					Value:                    &clients.B2ServerSideEncryption{Mode: ptr.To("SSE-B2"), Algorithm: "AES256"},
+				},
+			},
+			want: true,
+		},
+		"EncryptionNone": {
+			params: backblazev1.BucketParameters{
+				DefaultServerSideEncryption: &backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeNone},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				DefaultServerSideEncryption: &clients.B2EncryptionSetting{
+					IsClientAuthorizedToRead: true,
+				},
+			},
+			want: true,
+		},
+		"EncryptionNotReadable": {
+			params: backblazev1.BucketParameters{
+				DefaultServerSideEncryption: &backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeB2},
+			},
+			observed: &clients.B2Bucket{
+				BucketType:                  "allPrivate",
+				DefaultServerSideEncryption: &clients.B2EncryptionSetting{IsClientAuthorizedToRead: false},
+			},
+			want: true,
+		},
+		"RetentionChanged": {
+			params: backblazev1.BucketParameters{
+				FileLockEnabled: true,
+				DefaultRetention: &backblazev1.DefaultRetention{
+					Mode:   backblazev1.RetentionModeCompliance,
+					Period: backblazev1.RetentionPeriod{Duration: 30, Unit: "days"},
+				},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				FileLockConfiguration: &clients.B2FileLockConfiguration{
+					IsClientAuthorizedToRead: true,
+					Value: &clients.B2FileLockValue{
+						IsFileLockEnabled: true,
+						DefaultRetention: &clients.B2DefaultRetention{
+							Mode:   ptr.To("governance"),
+							Period: &clients.B2RetentionPeriod{Duration: 30, Unit: "days"},
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		"RetentionMissing": {
+			params: backblazev1.BucketParameters{
+				FileLockEnabled: true,
+				DefaultRetention: &backblazev1.DefaultRetention{
+					Mode:   backblazev1.RetentionModeGovernance,
+					Period: backblazev1.RetentionPeriod{Duration: 1, Unit: "years"},
+				},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				FileLockConfiguration: &clients.B2FileLockConfiguration{
+					IsClientAuthorizedToRead: true,
+					Value:                    &clients.B2FileLockValue{IsFileLockEnabled: true, DefaultRetention: &clients.B2DefaultRetention{}},
+				},
+			},
+			want: false,
+		},
+		"RetentionMatches": {
+			params: backblazev1.BucketParameters{
+				FileLockEnabled: true,
+				DefaultRetention: &backblazev1.DefaultRetention{
+					Mode:   backblazev1.RetentionModeGovernance,
+					Period: backblazev1.RetentionPeriod{Duration: 1, Unit: "years"},
+				},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				FileLockConfiguration: &clients.B2FileLockConfiguration{
+					IsClientAuthorizedToRead: true,
+					Value: &clients.B2FileLockValue{
+						IsFileLockEnabled: true,
+						DefaultRetention: &clients.B2DefaultRetention{
+							Mode:   ptr.To("governance"),
+							Period: &clients.B2RetentionPeriod{Duration: 1, Unit: "years"},
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		"ReplicationRulesReordered": {
+			params: backblazev1.BucketParameters{
+				ReplicationConfiguration: &backblazev1.ReplicationConfiguration{
+					AsSource: &backblazev1.ReplicationSource{
+						SourceApplicationKeyID: ptr.To("key"),
+						Rules: []backblazev1.ReplicationRule{
+							{Name: "a", DestinationBucketID: ptr.To("dest-a")},
+							{Name: "b", DestinationBucketID: ptr.To("dest-b"), Priority: 2},
+						},
+					},
+				},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				ReplicationConfiguration: &clients.B2ReplicationSetting{
+					IsClientAuthorizedToRead: true,
+					Value: &clients.B2ReplicationConfiguration{
+						AsReplicationSource: &clients.B2ReplicationSource{
+							SourceApplicationKeyID: "key",
+							ReplicationRules: []clients.B2ReplicationRule{
+								{ReplicationRuleName: "b", DestinationBucketID: "dest-b", IsEnabled: true, Priority: 2},
+								{ReplicationRuleName: "a", DestinationBucketID: "dest-a", IsEnabled: true, Priority: 1},
+							},
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		"ReplicationRuleDisabled": {
+			params: backblazev1.BucketParameters{
+				ReplicationConfiguration: &backblazev1.ReplicationConfiguration{
+					AsSource: &backblazev1.ReplicationSource{
+						SourceApplicationKeyID: ptr.To("key"),
+						Rules:                  []backblazev1.ReplicationRule{{Name: "a", DestinationBucketID: ptr.To("dest-a")}},
+					},
+				},
+			},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				ReplicationConfiguration: &clients.B2ReplicationSetting{
+					IsClientAuthorizedToRead: true,
+					Value: &clients.B2ReplicationConfiguration{
+						AsReplicationSource: &clients.B2ReplicationSource{
+							SourceApplicationKeyID: "key",
+							ReplicationRules:       []clients.B2ReplicationRule{{ReplicationRuleName: "a", DestinationBucketID: "dest-a", Priority: 1}},
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		"ReplicationNotConfigured": {
+			params: backblazev1.BucketParameters{
+				ReplicationConfiguration: &backblazev1.ReplicationConfiguration{
+					AsDestination: &backblazev1.ReplicationDestination{
+						SourceToDestinationKeyMapping: map[string]string{"source-key": "dest-key"},
+					},
+				},
+			},
+			observed: &clients.B2Bucket{
+				BucketType:               "allPrivate",
+				ReplicationConfiguration: &clients.B2ReplicationSetting{IsClientAuthorizedToRead: true},
+			},
+			want: false,
+		},
+		"UnwantedCORSRule": {
+			params: backblazev1.BucketParameters{},
+			observed: &clients.B2Bucket{
+				BucketType: "allPrivate",
+				CORSRules:  []clients.B2CORSRule{{CorsRuleName: "added-in-console"}},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isUpToDate(tc.params, tc.info, tc.observed); got != tc.want {
+				t.Errorf("isUpToDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateUpdateBucketRequest(t *testing.T) {
+	observed := &clients.B2Bucket{
+		AccountID:  "account",
+		BucketID:   "bucket-id",
+		BucketType: "allPublic",
+		CORSRules:  []clients.B2CORSRule{{CorsRuleName: "added-in-console"}},
+		Revision:   7,
+	}
+
+	got := generateUpdateBucketRequest(backblazev1.BucketParameters{}, nil, observed)
+
+	if got.BucketID != "bucket-id" || got.AccountID != "account" {
+		t.Errorf("request targets %s/%s, want account/bucket-id", got.AccountID, got.BucketID)
+	}
+	if got.IfRevisionIs != 7 {
+		t.Errorf("IfRevisionIs = %d, want 7", got.IfRevisionIs)
+	}
+	if got.BucketType != "allPrivate" {
+		t.Errorf("BucketType = %q, want allPrivate", got.BucketType)
+	}
+	if got.CORSRules == nil || len(got.CORSRules) != 0 {
+		t.Errorf("CORSRules = %v, want an empty list that removes existing rules", got.CORSRules)
+	}
+}
+
+func TestCheckRegion(t *testing.T) {
+	tests := map[string]struct {
+		region   string
+		location string
+		pcRegion string
+		wantErr  bool
+	}{
+		"Matches":         {region: "us-west-001", location: "us-west-001", pcRegion: "us-west-001"},
+		"UnknownLocation": {region: "us-west-001", location: "", pcRegion: "us-west-001"},
+		"NoRegion":        {region: "", location: "eu-central-003", pcRegion: "eu-central-003"},
+		"Moved":           {region: "us-west-001", location: "eu-central-003", pcRegion: "us-west-001", wantErr: true},
+		"MovedFromOther":  {region: "us-east-005", location: "eu-central-003", pcRegion: "eu-central-003", wantErr: true},
+		"FormerDefault":   {region: "us-west-001", location: "eu-central-003", pcRegion: "eu-central-003"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkRegion(backblazev1.BucketParameters{Region: tc.region}, tc.location, tc.pcRegion)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkRegion() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckFileLock(t *testing.T) {
+	enabled := func(b bool) *clients.B2FileLockConfiguration {
+		return &clients.B2FileLockConfiguration{IsClientAuthorizedToRead: true, Value: &clients.B2FileLockValue{IsFileLockEnabled: b}}
+	}
+
+	tests := map[string]struct {
+		desired  bool
+		observed *clients.B2FileLockConfiguration
+		wantErr  bool
+	}{
+		"BothEnabled":  {desired: true, observed: enabled(true)},
+		"BothDisabled": {desired: false, observed: enabled(false)},
+		"NotReadable":  {desired: true, observed: &clients.B2FileLockConfiguration{IsClientAuthorizedToRead: false}},
+		"NotReported":  {desired: true},
+		"NotEnabled":   {desired: true, observed: enabled(false), wantErr: true},
+		"NotDesired":   {desired: false, observed: enabled(true), wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkFileLock(backblazev1.BucketParameters{FileLockEnabled: tc.desired}, &clients.B2Bucket{FileLockConfiguration: tc.observed})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkFileLock() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateReplicationObservation(t *testing.T) {
+	got := generateReplicationObservation(&clients.B2ReplicationSetting{
+		IsClientAuthorizedToRead: true,
+		Value: &clients.B2ReplicationConfiguration{
+			AsReplicationSource: &clients.B2ReplicationSource{
+				SourceApplicationKeyID: "key",
+				ReplicationRules: []clients.B2ReplicationRule{
+					{ReplicationRuleName: "on", DestinationBucketID: "dest", IsEnabled: true, Priority: 1},
+					{ReplicationRuleName: "off", DestinationBucketID: "dest", Priority: 2},
+				},
+			},
+		},
+	})
+	want := &backblazev1.ReplicationObservation{
+		SourceApplicationKeyID: "key",
+		Rules: []backblazev1.ReplicationRuleObservation{
+			{Name: "on", DestinationBucketID: "dest", Priority: 1, State: backblazev1.ReplicationRuleEnabled},
+			{Name: "off", DestinationBucketID: "dest", Priority: 2, State: backblazev1.ReplicationRuleDisabled},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("generateReplicationObservation(): -want, +got:\n%s", diff)
+	}
+
+	if got := generateReplicationObservation(&clients.B2ReplicationSetting{IsClientAuthorizedToRead: true, Value: &clients.B2ReplicationConfiguration{}}); got != nil {
+		t.Errorf("generateReplicationObservation() without replication = %+v, want nil", got)
+	}
+}
+
+func TestExternalName(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"SpecBucketName": {
+			want: "spec-bucket",
+		},
+		"Imported": {
+			annotations: map[string]string{"crossplane.io/external-name": "existing-bucket"},
+			want:        "existing-bucket",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &backblazev1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: tc.annotations},
+				Spec: backblazev1.BucketSpec{
+					ForProvider: backblazev1.BucketParameters{BucketName: "spec-bucket"},
+				},
+			}
+			if got := externalName(cr); got != tc.want {
+				t.Errorf("externalName() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestManagedByEarlierRelease(t *testing.T) {
+	tests := map[string]struct {
+		observed backblazev1.BucketObservation
+		want     bool
+	}{
+		"NeverObserved": {},
+		"EarlierRelease": {
+			observed: backblazev1.BucketObservation{BucketName: "spec-bucket"},
+			want:     true,
+		},
+		"ObservedByThisRelease": {
+			observed: backblazev1.BucketObservation{BucketName: "spec-bucket", BucketID: "4a48fe8875c6214145260818"},
+		},
+		"OtherBucket": {
+			observed: backblazev1.BucketObservation{BucketName: "other-bucket"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &backblazev1.Bucket{
+				Spec: backblazev1.BucketSpec{
+					ForProvider: backblazev1.BucketParameters{BucketName: "spec-bucket"},
+				},
+				Status: backblazev1.BucketStatus{AtProvider: tc.observed},
+			}
+			if got := managedByEarlierRelease(cr); got != tc.want {
+				t.Errorf("managedByEarlierRelease() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBucketInfo(t *testing.T) {
+	observed := &clients.B2Bucket{BucketInfo: map[string]string{"cost-center": "team-a", "added-in-console": "yes"}}
+
+	tests := map[string]struct {
+		desired     map[string]string
+		managedKeys []string
+		want        map[string]string
+	}{
+		"Desired": {
+			desired:     map[string]string{"cost-center": "team-b"},
+			managedKeys: []string{"cost-center"},
+			want:        map[string]string{"cost-center": "team-b", "added-in-console": "yes"},
+		},
+		"FirstDesired": {
+			desired: map[string]string{"owner": "platform"},
+			want:    map[string]string{"cost-center": "team-a", "added-in-console": "yes", "owner": "platform"},
+		},
+		"DesiredRemoved": {
+			desired:     map[string]string{"owner": "platform"},
+			managedKeys: []string{"cost-center", "owner"},
+			want:        map[string]string{"added-in-console": "yes", "owner": "platform"},
+		},
+		"NeverManaged": {
+			desired: map[string]string{},
+		},
+		"Cleared": {
+			desired:     map[string]string{},
+			managedKeys: []string{"cost-center"},
+			want:        map[string]string{"added-in-console": "yes"},
+		},
+		"AllCleared": {
+			managedKeys: []string{"cost-center", "added-in-console"},
+			want:        map[string]string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := bucketInfo(tc.desired, tc.managedKeys, observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("bucketInfo(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDesiredBucketInfo(t *testing.T) {
+	tests := map[string]struct {
+		namespace string
+		info      map[string]string
+		defaults  *apisv1beta1.DefaultBucketInfo
+		want      map[string]string
+	}{
+		"NoDefaults": {
+			info: map[string]string{"Cache-Control": "max-age=3600"},
+			want: map[string]string{"Cache-Control": "max-age=3600"},
+		},
+		"DefaultsMerged": {
+			info: map[string]string{"cost-center": "team-a"},
+			defaults: &apisv1beta1.DefaultBucketInfo{
+				Tags:                map[string]string{"cost-center": "shared", "env": "prod"},
+				ClusterName:         "prod-eu",
+				IncludeResourceName: true,
+			},
+			want: map[string]string{
+				"cost-center":        "team-a",
+				"env":                "prod",
+				"crossplane-cluster": "prod-eu",
+				"crossplane-name":    "test",
+			},
+		},
+		"Namespaced": {
+			namespace: "team-a",
+			defaults:  &apisv1beta1.DefaultBucketInfo{IncludeResourceName: true},
+			want: map[string]string{
+				"crossplane-namespace": "team-a",
+				"crossplane-name":      "test",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &backblazev1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: tc.namespace},
+				Spec: backblazev1.BucketSpec{
+					ForProvider: backblazev1.BucketParameters{BucketInfo: tc.info},
+				},
+			}
+			got := desiredBucketInfo(cr, tc.defaults)
+			if len(got) != len(tc.want) {
+				t.Fatalf("desiredBucketInfo() = %v, want %v", got, tc.want)
+			}
+			for k, v := range tc.want {
+				if got[k] != v {
+					t.Errorf("desiredBucketInfo()[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
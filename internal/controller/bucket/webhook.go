@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/scope"
+	"github.com/rossigee/provider-backblaze/internal/validation"
+)
+
+// reservedBucketPrefix is the prefix B2 reserves for its own bucket names.
+const reservedBucketPrefix = "b2-"
+
+// bucketNamePattern matches the characters a B2 bucket name may contain.
+var bucketNamePattern = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// SetupBucketWebhook adds webhooks that validate cluster scoped and
+// namespaced Buckets when they are created or updated.
+func SetupBucketWebhook(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &backblazev1.Bucket{}).
+		WithValidator(&Validator[*backblazev1.Bucket]{}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr, &bucketv1beta1.Bucket{}).
+		WithValidator(&Validator[*bucketv1beta1.Bucket]{}).
+		Complete()
+}
+
+// A Validator validates Buckets of either scope.
+type Validator[T scope.Bucket] struct{}
+
+// ValidateCreate validates a new Bucket.
+func (v *Validator[T]) ValidateCreate(_ context.Context, b T) (admission.Warnings, error) {
+	return nil, invalid(b, validateParameters(b.GetForProvider()))
+}
+
+// ValidateUpdate validates an updated Bucket, including that the fields B2
+// can't change haven't been. Only problems the update introduces are
+// reported.
+func (v *Validator[T]) ValidateUpdate(_ context.Context, old, b T) (admission.Warnings, error) {
+	if validation.SkipUpdate(old, b) {
+		return nil, nil
+	}
+	p, was := b.GetForProvider(), old.GetForProvider()
+	errs := validation.NewErrors(validateParameters(was), validateParameters(p))
+	params := field.NewPath("spec", "forProvider")
+	if p.BucketName != was.BucketName {
+		errs = append(errs, field.Forbidden(params.Child("bucketName"), "bucketName is immutable"))
+	}
+	if was.Region != "" && p.Region != was.Region {
+		errs = append(errs, field.Forbidden(params.Child("region"), "region is immutable"))
+	}
+	if was.FileLockEnabled && !p.FileLockEnabled {
+		errs = append(errs, field.Forbidden(params.Child("fileLockEnabled"), "file lock can't be disabled once enabled"))
+	}
+	return nil, invalid(b, errs)
+}
+
+// ValidateDelete allows all Buckets to be deleted.
+func (v *Validator[T]) ValidateDelete(_ context.Context, _ T) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateParameters validates the supplied Bucket parameters.
+func validateParameters(p *backblazev1.BucketParameters) field.ErrorList {
+	var errs field.ErrorList
+	params := field.NewPath("spec", "forProvider")
+
+	errs = append(errs, validateBucketName(params.Child("bucketName"), p.BucketName)...)
+
+	if p.DefaultRetention != nil && !p.FileLockEnabled {
+		errs = append(errs, field.Invalid(params.Child("defaultRetention"), "", "defaultRetention requires fileLockEnabled"))
+	}
+
+	for i, r := range p.LifecycleRules {
+		rule := params.Child("lifecycleRules").Index(i)
+		if r.DaysFromUploadingToHiding != nil && *r.DaysFromUploadingToHiding < 1 {
+			errs = append(errs, field.Invalid(rule.Child("daysFromUploadingToHiding"), *r.DaysFromUploadingToHiding, "must be at least 1"))
+		}
+		if r.DaysFromHidingToDeleting != nil && *r.DaysFromHidingToDeleting < 1 {
+			errs = append(errs, field.Invalid(rule.Child("daysFromHidingToDeleting"), *r.DaysFromHidingToDeleting, "must be at least 1"))
+		}
+	}
+
+	names := map[string]bool{}
+	for i, r := range p.CorsRules {
+		if names[r.CorsRuleName] {
+			errs = append(errs, field.Duplicate(params.Child("corsRules").Index(i).Child("corsRuleName"), r.CorsRuleName))
+		}
+		names[r.CorsRuleName] = true
+	}
+	return errs
+}
+
+// validateBucketName validates a name against B2's bucket naming rules.
+// Names are 6 to 63 letters, digits and hyphens, and may not start with the
+// prefix B2 reserves. They must also be globally unique, which only B2 can
+// check.
+func validateBucketName(path *field.Path, name string) field.ErrorList {
+	var errs field.ErrorList
+	if len(name) < 6 || len(name) > 63 {
+		errs = append(errs, field.Invalid(path, name, "must be between 6 and 63 characters long"))
+	}
+	if !bucketNamePattern.MatchString(name) {
+		errs = append(errs, field.Invalid(path, name, "must contain only letters, digits and hyphens"))
+	}
+	if strings.HasPrefix(strings.ToLower(name), reservedBucketPrefix) {
+		errs = append(errs, field.Invalid(path, name, "must not start with "+reservedBucketPrefix+", which B2 reserves"))
+	}
+	return errs
+}
+
+// invalid returns an Invalid error for the supplied Bucket, or nil if there
+// are no errors.
+func invalid(b scope.Bucket, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(b.GetObjectKind().GroupVersionKind().GroupKind(), b.GetName(), errs)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bucket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+// fieldPaths returns the paths of the fields with errors.
+func fieldPaths(errs field.ErrorList) []string {
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.Field)
+	}
+	return paths
+}
+
+func TestValidateParameters(t *testing.T) {
+	tests := map[string]struct {
+		params backblazev1.BucketParameters
+		want   []string
+	}{
+		"Valid": {
+			params: backblazev1.BucketParameters{BucketName: "my-bucket-01"},
+		},
+		"NameTooShort": {
+			params: backblazev1.BucketParameters{BucketName: "abc"},
+			want:   []string{"spec.forProvider.bucketName"},
+		},
+		"NameTooLong": {
+			params: backblazev1.BucketParameters{BucketName: "a123456789012345678901234567890123456789012345678901234567890123"},
+			want:   []string{"spec.forProvider.bucketName"},
+		},
+		"NameInvalidCharacters": {
+			params: backblazev1.BucketParameters{BucketName: "my_bucket.name"},
+			want:   []string{"spec.forProvider.bucketName"},
+		},
+		"NameReservedPrefix": {
+			params: backblazev1.BucketParameters{BucketName: "B2-my-bucket"},
+			want:   []string{"spec.forProvider.bucketName"},
+		},
+		"DefaultRetentionWithoutFileLock": {
+			params: backblazev1.BucketParameters{
+				BucketName:       "my-bucket",
+				DefaultRetention: &backblazev1.DefaultRetention{Mode: "governance"},
+			},
+			want: []string{"spec.forProvider.defaultRetention"},
+		},
+		"LifecycleDaysZero": {
+			params: backblazev1.BucketParameters{
+				BucketName: "my-bucket",
+				LifecycleRules: []backblazev1.LifecycleRule{
+					{DaysFromUploadingToHiding: ptr.To(1)},
+					{DaysFromUploadingToHiding: ptr.To(0), DaysFromHidingToDeleting: ptr.To(0)},
+				},
+			},
+			want: []string{
+				"spec.forProvider.lifecycleRules[1].daysFromUploadingToHiding",
+				"spec.forProvider.lifecycleRules[1].daysFromHidingToDeleting",
+			},
+		},
+		"DuplicateCorsRuleName": {
+			params: backblazev1.BucketParameters{
+				BucketName: "my-bucket",
+				CorsRules:  []backblazev1.CORSRule{{CorsRuleName: "a"}, {CorsRuleName: "b"}, {CorsRuleName: "a"}},
+			},
+			want: []string{"spec.forProvider.corsRules[2].corsRuleName"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := fieldPaths(validateParameters(&tc.params))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("validateParameters(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := map[string]struct {
+		old     backblazev1.BucketParameters
+		params  backblazev1.BucketParameters
+		wantErr bool
+	}{
+		"TypeChanged": {
+			old:    backblazev1.BucketParameters{BucketName: "my-bucket", BucketType: "allPrivate", Region: "us-west-001"},
+			params: backblazev1.BucketParameters{BucketName: "my-bucket", BucketType: "allPublic", Region: "us-west-001"},
+		},
+		"RegionSet": {
+			old:    backblazev1.BucketParameters{BucketName: "my-bucket"},
+			params: backblazev1.BucketParameters{BucketName: "my-bucket", Region: "us-west-001"},
+		},
+		"FileLockEnabled": {
+			old:    backblazev1.BucketParameters{BucketName: "my-bucket"},
+			params: backblazev1.BucketParameters{BucketName: "my-bucket", FileLockEnabled: true},
+		},
+		"ExistingProblem": {
+			old:    backblazev1.BucketParameters{BucketName: "b2-bucket", BucketType: "allPrivate"},
+			params: backblazev1.BucketParameters{BucketName: "b2-bucket", BucketType: "allPublic"},
+		},
+		"NewProblem": {
+			old: backblazev1.BucketParameters{BucketName: "my-bucket"},
+			params: backblazev1.BucketParameters{BucketName: "my-bucket", LifecycleRules: []backblazev1.LifecycleRule{
+				{DaysFromHidingToDeleting: ptr.To(0)},
+			}},
+			wantErr: true,
+		},
+		"NameChanged": {
+			old:     backblazev1.BucketParameters{BucketName: "my-bucket"},
+			params:  backblazev1.BucketParameters{BucketName: "my-other-bucket"},
+			wantErr: true,
+		},
+		"RegionChanged": {
+			old:     backblazev1.BucketParameters{BucketName: "my-bucket", Region: "us-west-001"},
+			params:  backblazev1.BucketParameters{BucketName: "my-bucket", Region: "eu-central-003"},
+			wantErr: true,
+		},
+		"FileLockDisabled": {
+			old:     backblazev1.BucketParameters{BucketName: "my-bucket", FileLockEnabled: true},
+			params:  backblazev1.BucketParameters{BucketName: "my-bucket"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := &backblazev1.Bucket{Spec: backblazev1.BucketSpec{ForProvider: tc.old}}
+			b := &backblazev1.Bucket{Spec: backblazev1.BucketSpec{ForProvider: tc.params}}
+			_, err := (&Validator[*backblazev1.Bucket]{}).ValidateUpdate(context.Background(), old, b)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateUpdate(...): want error %t, got %v", tc.wantErr, err)
+			}
+
+			// A Bucket that is being deleted can always be updated, for
+			// example to remove its finalizer.
+			b.SetDeletionTimestamp(&metav1.Time{})
+			if _, err := (&Validator[*backblazev1.Bucket]{}).ValidateUpdate(context.Background(), old, b); err != nil {
+				t.Errorf("ValidateUpdate(...) while deleting: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,515 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bucketnotification manages B2 event notification rules.
+package bucketnotification
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/features"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+	"github.com/rossigee/provider-backblaze/internal/scope"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errGetPC            = "cannot get ProviderConfig"
+	errGetCreds         = "cannot get credentials"
+	errResolveRefs      = "cannot resolve references"
+	errFmtGetRef        = "cannot get referenced %s %q"
+	errFmtRefNotReady   = "referenced %s %q does not have an ID yet"
+	errGetHeaders       = "cannot get custom headers Secret"
+	errGetSigningSecret = "cannot get signing Secret"
+	errFmtNoSigningKey  = "signing Secret %s/%s has no key %q"
+	errInvalidSigning   = "signing secret must be 32 alphanumeric characters"
+	errTooManyHeaders   = "at most 10 custom headers are supported"
+	errObserveRules     = "cannot get bucket notification rules"
+	errSetRules         = "cannot set bucket notification rules"
+	errFmtNotFound      = "notification rule %q does not exist and managementPolicies do not allow it to be created"
+	errFmtNotManaged    = "notification rule %q already exists on the bucket but is not managed by this resource: set the crossplane.io/external-name annotation to %q to import it"
+	errAddFinalizer     = "cannot add finalizer"
+	errRemFinalizer     = "cannot remove finalizer"
+
+	// maxCustomHeaders is the number of custom headers B2 accepts per rule.
+	maxCustomHeaders = 10
+
+	// finalizerName blocks deletion of a BucketNotification until the
+	// external rule has been deleted or orphaned.
+	finalizerName = "finalizer.managedresource.crossplane.io"
+)
+
+// signingSecretPattern matches the signing secrets B2 accepts.
+var signingSecretPattern = regexp.MustCompile(`^[a-zA-Z0-9]{32}$`)
+
+// SetupBucketNotification adds a controller that reconciles
+// BucketNotification managed resources.
+func SetupBucketNotification(mgr ctrl.Manager, o controller.Options) error {
+	r := &BucketNotificationReconciler{
+		Client:      mgr.GetClient(),
+		NewClientFn: clients.NewClient,
+	}
+
+	return r.SetupWithManager(mgr, o)
+}
+
+// SetupWithManager registers the reconciler with the supplied manager.
+func (r *BucketNotificationReconciler) SetupWithManager(mgr ctrl.Manager, o controller.Options) error {
+	r.ManagementPoliciesEnabled = o.Features.Enabled(features.EnableAlphaManagementPolicies)
+	name := "bucketnotification-controller"
+	if r.Recorder == nil {
+		r.Recorder = recorder.New(mgr.GetEventRecorder(name))
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&backblazev1.BucketNotification{}).
+		Watches(&apisv1beta1.ProviderConfig{}, handler.Funcs{}).
+		Complete(r)
+}
+
+// BucketNotificationReconciler reconciles a BucketNotification object
+type BucketNotificationReconciler struct {
+	Client client.Client
+
+	// NewClientFn creates the Backblaze client used to manage notification
+	// rules.
+	NewClientFn clients.NewClientFn
+
+	// ManagementPoliciesEnabled enables support for spec.managementPolicies.
+	ManagementPoliciesEnabled bool
+
+	// Recorder records events about the notification rule, such as its
+	// creation and deletion.
+	Recorder event.Recorder
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *BucketNotificationReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := log.FromContext(ctx).WithValues("bucketnotification", req.NamespacedName)
+
+	bn := &backblazev1.BucketNotification{}
+	if err := r.Client.Get(ctx, req.NamespacedName, bn); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			logger.Info("BucketNotification resource not found, likely deleted")
+			return reconcile.Result{}, nil
+		}
+		logger.Error(err, "Failed to get BucketNotification")
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Reconciling bucket notification", "ruleName", bn.GetRuleName())
+
+	policy := managed.NewLegacyManagementPoliciesResolver(r.ManagementPoliciesEnabled, bn.GetManagementPolicies(), bn.GetDeletionPolicy())
+	if err := policy.Validate(); err != nil {
+		logger.Error(err, "Invalid management policies")
+		r.setCondition(bn, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{}, r.Client.Status().Update(ctx, bn)
+	}
+
+	if meta.WasDeleted(bn) {
+		return r.handleDeletion(ctx, bn, policy)
+	}
+
+	if policy.IsPaused() {
+		logger.Info("Reconciliation is paused via the management policies")
+		r.setCondition(bn, xpv1.TypeSynced, "False", "ReconcilePaused", "Reconciliation is paused via the management policies")
+		return reconcile.Result{}, r.Client.Status().Update(ctx, bn)
+	}
+
+	if !meta.FinalizerExists(bn, finalizerName) {
+		meta.AddFinalizer(bn, finalizerName)
+		if err := r.Client.Update(ctx, bn); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
+	}
+
+	service, err := r.getBackblazeClient(ctx, bn)
+	if err != nil {
+		logger.Error(err, "Failed to create Backblaze client")
+		r.setCondition(bn, xpv1.TypeReady, "False", "ClientError", err.Error())
+		requeueAfter := time.Minute
+		if strings.Contains(err.Error(), "not found") {
+			requeueAfter = 10 * time.Second
+		}
+		return reconcile.Result{RequeueAfter: requeueAfter}, r.Client.Status().Update(ctx, bn)
+	}
+
+	if err := r.resolveReferences(ctx, bn); err != nil {
+		logger.Info("Cannot resolve references, retrying in 10 seconds", "error", err)
+		r.setCondition(bn, xpv1.TypeSynced, "False", "ReconcileError", errors.Wrap(err, errResolveRefs).Error())
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, r.Client.Status().Update(ctx, bn)
+	}
+
+	desired, err := r.desiredRule(ctx, bn)
+	if err != nil {
+		logger.Error(err, "Failed to build notification rule")
+		r.setCondition(bn, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bn)
+	}
+
+	bucketID := ptr.Deref(bn.Spec.ForProvider.BucketID, "")
+	ruleName := bn.GetRuleName()
+	rules, revision, err := observeRules(ctx, service, bucketID)
+	if err != nil {
+		logger.Error(err, "Failed to observe notification rules")
+		r.Recorder.Event(bn, recorder.Warning(recorder.ReasonCannotObserve, errors.Wrap(err, errObserveRules)))
+		r.setCondition(bn, xpv1.TypeReady, "False", "CheckError", errors.Wrap(err, errObserveRules).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bn)
+	}
+	observed := findRule(rules, ruleName)
+
+	switch {
+	case observed == nil && !policy.ShouldCreate():
+		logger.Info("Notification rule does not exist and will not be created", "ruleName", ruleName)
+		r.setCondition(bn, xpv1.TypeReady, "False", "NotFound", errors.Errorf(errFmtNotFound, ruleName).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bn)
+
+	case observed != nil && meta.GetExternalName(bn) == "" && !policy.ShouldOnlyObserve():
+		logger.Info("Notification rule exists but is not managed by this resource", "ruleName", ruleName)
+		r.setCondition(bn, xpv1.TypeReady, "False", "NotManaged", errors.Errorf(errFmtNotManaged, ruleName, ruleName).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bn)
+
+	case observed == nil || (!isUpToDate(desired, observed) && policy.ShouldUpdate()):
+		if meta.GetExternalName(bn) == "" {
+			meta.SetExternalName(bn, ruleName)
+			if err := r.Client.Update(ctx, bn); err != nil {
+				logger.Error(err, "Failed to record external name")
+				return reconcile.Result{}, err
+			}
+		}
+
+		logger.Info("Setting notification rule", "ruleName", ruleName, "bucketId", bucketID)
+		rules, err = service.SetBucketNotificationRules(ctx, bucketID, withRule(rules, desired), revision)
+		if err != nil {
+			logger.Error(err, "Failed to set notification rules")
+			reason, ct, er := "ReconcileError", xpv1.TypeSynced, recorder.ReasonCannotUpdate
+			if observed == nil {
+				reason, ct, er = "CreateError", xpv1.TypeReady, recorder.ReasonCannotCreate
+			}
+			r.Recorder.Event(bn, recorder.Warning(er, errors.Wrap(err, errSetRules)))
+			r.setCondition(bn, ct, "False", reason, errors.Wrap(err, errSetRules).Error())
+			return reconcile.Result{RequeueAfter: retryAfter(err)}, r.Client.Status().Update(ctx, bn)
+		}
+		if observed == nil {
+			r.Recorder.Event(bn, event.Normal(recorder.ReasonCreated, fmt.Sprintf("Created notification rule %q", ruleName)))
+		} else {
+			r.Recorder.Event(bn, event.Normal(recorder.ReasonUpdated, fmt.Sprintf("Updated notification rule %q", ruleName)))
+		}
+		observed = findRule(rules, ruleName)
+	}
+
+	if observed == nil {
+		logger.Info("Notification rule is not yet visible, retrying in 10 seconds")
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	bn.Status.AtProvider = generateObservation(bucketID, observed)
+	if observed.IsSuspended {
+		r.setCondition(bn, xpv1.TypeReady, "False", "Suspended", "Notification rule was suspended by B2: "+observed.SuspensionReason)
+	} else {
+		r.setCondition(bn, xpv1.TypeReady, "True", "Available", "Notification rule is ready")
+	}
+	r.setCondition(bn, xpv1.TypeSynced, "True", "ReconcileSuccess", "Successfully reconciled")
+
+	if err := r.Client.Status().Update(ctx, bn); err != nil {
+		logger.Error(err, "Failed to update bucket notification status")
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Successfully reconciled bucket notification")
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// resolveReferences sets the ID of the referenced Bucket, and persists it if
+// it changed.
+func (r *BucketNotificationReconciler) resolveReferences(ctx context.Context, bn *backblazev1.BucketNotification) error {
+	ref := bn.Spec.ForProvider.BucketRef
+	if ref == nil {
+		return nil
+	}
+
+	bucket := &backblazev1.Bucket{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: ref.Name}, bucket); err != nil {
+		return errors.Wrapf(err, errFmtGetRef, backblazev1.BucketKind, ref.Name)
+	}
+	id := bucket.Status.AtProvider.BucketID
+	if id == "" {
+		return errors.Errorf(errFmtRefNotReady, backblazev1.BucketKind, ref.Name)
+	}
+	if ptr.Deref(bn.Spec.ForProvider.BucketID, "") == id {
+		return nil
+	}
+	bn.Spec.ForProvider.BucketID = ptr.To(id)
+	return r.Client.Update(ctx, bn)
+}
+
+// desiredRule returns the notification rule described by the supplied
+// resource, including the custom headers and signing secret read from their
+// Secrets.
+func (r *BucketNotificationReconciler) desiredRule(ctx context.Context, bn *backblazev1.BucketNotification) (clients.B2NotificationRule, error) {
+	p := bn.Spec.ForProvider
+	rule := clients.B2NotificationRule{
+		Name:             bn.GetRuleName(),
+		EventTypes:       make([]string, len(p.EventTypes)),
+		IsEnabled:        ptr.Deref(p.Enabled, true),
+		ObjectNamePrefix: p.ObjectNamePrefix,
+		TargetConfiguration: clients.B2NotificationTarget{
+			TargetType: clients.B2NotificationTargetWebhook,
+			URL:        p.URL,
+		},
+	}
+	for i, et := range p.EventTypes {
+		rule.EventTypes[i] = string(et)
+	}
+
+	if ref := p.CustomHeadersSecretRef; ref != nil {
+		s := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return rule, errors.Wrap(err, errGetHeaders)
+		}
+		headers, err := customHeaders(s.Data)
+		if err != nil {
+			return rule, err
+		}
+		rule.TargetConfiguration.CustomHeaders = headers
+	}
+
+	if ref := p.SigningSecretRef; ref != nil {
+		s := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return rule, errors.Wrap(err, errGetSigningSecret)
+		}
+		secret, ok := s.Data[ref.Key]
+		if !ok {
+			return rule, errors.Errorf(errFmtNoSigningKey, ref.Namespace, ref.Name, ref.Key)
+		}
+		if !signingSecretPattern.Match(secret) {
+			return rule, errors.New(errInvalidSigning)
+		}
+		rule.TargetConfiguration.HmacSha256SigningSecret = string(secret)
+	}
+
+	return rule, nil
+}
+
+func (r *BucketNotificationReconciler) handleDeletion(ctx context.Context, bn *backblazev1.BucketNotification, policy managed.ManagementPoliciesChecker) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !meta.FinalizerExists(bn, finalizerName) {
+		return reconcile.Result{}, nil
+	}
+
+	// A rule that this resource neither created nor imported is never
+	// deleted.
+	bucketID := ptr.Deref(bn.Spec.ForProvider.BucketID, "")
+	if policy.ShouldDelete() && meta.GetExternalName(bn) != "" && bucketID != "" {
+		service, err := r.getBackblazeClient(ctx, bn)
+		if err != nil {
+			logger.Error(err, "Failed to create Backblaze client")
+			r.setCondition(bn, xpv1.TypeReady, "False", "ClientError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, bn)
+		}
+
+		if err := deleteRule(ctx, service, bucketID, meta.GetExternalName(bn)); err != nil {
+			logger.Error(err, "Failed to delete notification rule")
+			r.Recorder.Event(bn, recorder.Warning(recorder.ReasonCannotDelete, err))
+			r.setCondition(bn, xpv1.TypeReady, "False", "DeleteError", err.Error())
+			return reconcile.Result{RequeueAfter: retryAfter(err)}, r.Client.Status().Update(ctx, bn)
+		}
+		r.Recorder.Event(bn, event.Normal(recorder.ReasonDeleted, fmt.Sprintf("Deleted notification rule %q", meta.GetExternalName(bn))))
+	}
+
+	meta.RemoveFinalizer(bn, finalizerName)
+	if err := r.Client.Update(ctx, bn); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errRemFinalizer)
+	}
+
+	logger.Info("Bucket notification deletion handled")
+	return reconcile.Result{}, nil
+}
+
+// observeRules returns the notification rules of the supplied bucket, and the
+// revision of the bucket they were observed at. The revision is read first,
+// so that setting rules derived from them at that revision fails if they
+// changed in between.
+func observeRules(ctx context.Context, service clients.Client, bucketID string) ([]clients.B2NotificationRule, int, error) {
+	bucket, err := service.GetBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if bucket == nil {
+		return nil, 0, &clients.B2Error{Status: 400, Code: clients.B2ErrorCodeBadBucketID, Message: "Invalid bucketId: " + bucketID}
+	}
+	rules, err := service.GetBucketNotificationRules(ctx, bucketID)
+	return rules, bucket.Revision, err
+}
+
+// deleteRule removes the named rule from the bucket, leaving its other rules
+// unchanged. A rule or bucket that no longer exists is not an error.
+func deleteRule(ctx context.Context, service clients.Client, bucketID, ruleName string) error {
+	rules, revision, err := observeRules(ctx, service, bucketID)
+	if clients.IsBucketNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errObserveRules)
+	}
+	if findRule(rules, ruleName) == nil {
+		return nil
+	}
+
+	remaining := make([]clients.B2NotificationRule, 0, len(rules))
+	for _, r := range rules {
+		if r.Name != ruleName {
+			remaining = append(remaining, r)
+		}
+	}
+	_, err = service.SetBucketNotificationRules(ctx, bucketID, remaining, revision)
+	return errors.Wrap(err, errSetRules)
+}
+
+// retryAfter returns how long to wait before retrying after the supplied
+// error. A revision conflict means the bucket's rules changed after they were
+// observed, so they are observed again promptly rather than backing off.
+func retryAfter(err error) time.Duration {
+	if clients.IsRevisionConflict(err) {
+		return 5 * time.Second
+	}
+	return time.Minute
+}
+
+func (r *BucketNotificationReconciler) getBackblazeClient(ctx context.Context, bn *backblazev1.BucketNotification) (clients.Client, error) {
+	pc, err := scope.GetProviderConfig(ctx, r.Client, bn)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	cfg, err := clients.GetProviderConfig(ctx, r.Client, pc)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	return r.NewClientFn(*cfg)
+}
+
+func (r *BucketNotificationReconciler) setCondition(bn *backblazev1.BucketNotification, conditionType xpv1.ConditionType, status, reason, message string) {
+	bn.SetConditions(xpv1.Condition{
+		Type:               conditionType,
+		Status:             corev1.ConditionStatus(status),
+		LastTransitionTime: metav1.NewTime(time.Now()),
+		Reason:             xpv1.ConditionReason(reason),
+		Message:            message,
+	})
+}
+
+// customHeaders returns the entries of a Secret as notification headers,
+// sorted by name.
+func customHeaders(data map[string][]byte) ([]clients.B2NotificationHeader, error) {
+	if len(data) > maxCustomHeaders {
+		return nil, errors.New(errTooManyHeaders)
+	}
+	headers := make([]clients.B2NotificationHeader, 0, len(data))
+	for k, v := range data {
+		headers = append(headers, clients.B2NotificationHeader{Name: k, Value: string(v)})
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	return headers, nil
+}
+
+// findRule returns the named rule, or nil if there is none.
+func findRule(rules []clients.B2NotificationRule, name string) *clients.B2NotificationRule {
+	for i := range rules {
+		if rules[i].Name == name {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// withRule returns rules with the rule of the same name as desired replaced
+// by it, or desired appended if there is none.
+func withRule(rules []clients.B2NotificationRule, desired clients.B2NotificationRule) []clients.B2NotificationRule {
+	out := make([]clients.B2NotificationRule, 0, len(rules)+1)
+	replaced := false
+	for _, r := range rules {
+		if r.Name == desired.Name {
+			out = append(out, desired)
+			replaced = true
+			continue
+		}
+		out = append(out, r)
+	}
+	if !replaced {
+		out = append(out, desired)
+	}
+	return out
+}
+
+// isUpToDate returns true if the observed rule matches the desired rule. The
+// signing secret is only compared if B2 reports it, and a suspended rule is
+// considered up to date so that it is not re-enabled automatically.
+func isUpToDate(desired clients.B2NotificationRule, observed *clients.B2NotificationRule) bool {
+	current := *observed
+	current.IsSuspended = false
+	current.SuspensionReason = ""
+	if current.TargetConfiguration.HmacSha256SigningSecret == "" {
+		desired.TargetConfiguration.HmacSha256SigningSecret = ""
+	}
+
+	sortStrings := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	sortHeaders := cmpopts.SortSlices(func(a, b clients.B2NotificationHeader) bool { return a.Name < b.Name })
+	return cmp.Equal(desired, current, cmpopts.EquateEmpty(), sortStrings, sortHeaders)
+}
+
+// generateObservation returns the observable state of the supplied rule.
+func generateObservation(bucketID string, observed *clients.B2NotificationRule) backblazev1.BucketNotificationObservation {
+	return backblazev1.BucketNotificationObservation{
+		BucketID:         bucketID,
+		RuleName:         observed.Name,
+		Enabled:          observed.IsEnabled,
+		IsSuspended:      observed.IsSuspended,
+		SuspensionReason: observed.SuspensionReason,
+	}
+}
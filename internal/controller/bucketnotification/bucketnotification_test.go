@@ -0,0 +1,264 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucketnotification
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+)
+
+func newRule(name string) clients.B2NotificationRule {
+	return clients.B2NotificationRule{
+		Name:       name,
+		EventTypes: []string{"b2:ObjectCreated:*", "b2:ObjectDeleted:*"},
+		IsEnabled:  true,
+		TargetConfiguration: clients.B2NotificationTarget{
+			TargetType:    clients.B2NotificationTargetWebhook,
+			URL:           "https://example.com/hook",
+			CustomHeaders: []clients.B2NotificationHeader{{Name: "X-A", Value: "a"}, {Name: "X-B", Value: "b"}},
+		},
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	tests := map[string]struct {
+		desired  clients.B2NotificationRule
+		observed func(r *clients.B2NotificationRule)
+		want     bool
+	}{
+		"UpToDate": {
+			desired:  newRule("rule"),
+			observed: func(r *clients.B2NotificationRule) {},
+			want:     true,
+		},
+		"Reordered": {
+			desired: newRule("rule"),
+			observed: func(r *clients.B2NotificationRule) {
+				r.EventTypes = []string{"b2:ObjectDeleted:*", "b2:ObjectCreated:*"}
+				r.TargetConfiguration.CustomHeaders = []clients.B2NotificationHeader{{Name: "X-B", Value: "b"}, {Name: "X-A", Value: "a"}}
+			},
+			want: true,
+		},
+		"Suspended": {
+			desired: newRule("rule"),
+			observed: func(r *clients.B2NotificationRule) {
+				r.IsSuspended = true
+				r.SuspensionReason = "webhook returned 500"
+			},
+			want: true,
+		},
+		"URLChanged": {
+			desired: newRule("rule"),
+			observed: func(r *clients.B2NotificationRule) {
+				r.TargetConfiguration.URL = "https://example.com/other"
+			},
+			want: false,
+		},
+		"Disabled": {
+			desired: newRule("rule"),
+			observed: func(r *clients.B2NotificationRule) {
+				r.IsEnabled = false
+			},
+			want: false,
+		},
+		"HeaderChanged": {
+			desired: newRule("rule"),
+			observed: func(r *clients.B2NotificationRule) {
+				r.TargetConfiguration.CustomHeaders = []clients.B2NotificationHeader{{Name: "X-A", Value: "old"}, {Name: "X-B", Value: "b"}}
+			},
+			want: false,
+		},
+		"SigningSecretNotReported": {
+			desired: func() clients.B2NotificationRule {
+				r := newRule("rule")
+				r.TargetConfiguration.HmacSha256SigningSecret = "abcdefghijklmnopqrstuvwxyz012345"
+				return r
+			}(),
+			observed: func(r *clients.B2NotificationRule) {},
+			want:     true,
+		},
+		"SigningSecretChanged": {
+			desired: func() clients.B2NotificationRule {
+				r := newRule("rule")
+				r.TargetConfiguration.HmacSha256SigningSecret = "abcdefghijklmnopqrstuvwxyz012345"
+				return r
+			}(),
+			observed: func(r *clients.B2NotificationRule) {
+				r.TargetConfiguration.HmacSha256SigningSecret = "543210zyxwvutsrqponmlkjihgfedcba"
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			observed := newRule("rule")
+			tc.observed(&observed)
+			if got := isUpToDate(tc.desired, &observed); got != tc.want {
+				t.Errorf("isUpToDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithRule(t *testing.T) {
+	other := newRule("other")
+	existing := newRule("mine")
+	desired := newRule("mine")
+	desired.TargetConfiguration.URL = "https://example.com/new"
+
+	tests := map[string]struct {
+		rules []clients.B2NotificationRule
+		want  []clients.B2NotificationRule
+	}{
+		"Added": {
+			rules: []clients.B2NotificationRule{other},
+			want:  []clients.B2NotificationRule{other, desired},
+		},
+		"Replaced": {
+			rules: []clients.B2NotificationRule{existing, other},
+			want:  []clients.B2NotificationRule{desired, other},
+		},
+		"NoRules": {
+			want: []clients.B2NotificationRule{desired},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, withRule(tc.rules, desired)); diff != "" {
+				t.Errorf("withRule(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCustomHeaders(t *testing.T) {
+	got, err := customHeaders(map[string][]byte{"X-Token": []byte("secret"), "Authorization": []byte("Bearer x")})
+	if err != nil {
+		t.Fatalf("customHeaders() error = %v", err)
+	}
+	want := []clients.B2NotificationHeader{{Name: "Authorization", Value: "Bearer x"}, {Name: "X-Token", Value: "secret"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("customHeaders(): -want, +got:\n%s", diff)
+	}
+
+	tooMany := map[string][]byte{}
+	for i := 0; i <= maxCustomHeaders; i++ {
+		tooMany[fmt.Sprintf("X-Header-%d", i)] = []byte("v")
+	}
+	if _, err := customHeaders(tooMany); err == nil {
+		t.Errorf("customHeaders() with %d headers: want error", len(tooMany))
+	}
+}
+
+// racingBackend changes the rules of a bucket after they are observed, as if
+// another writer set them concurrently.
+type racingBackend struct {
+	*fake.Backend
+	bucketName string
+}
+
+func (b racingBackend) GetBucketNotificationRules(ctx context.Context, bucketID string) ([]clients.B2NotificationRule, error) {
+	rules, err := b.Backend.GetBucketNotificationRules(ctx, bucketID)
+	b.ModifyBucket(b.bucketName, func(bk *fake.Bucket) {
+		bk.NotificationRules = append(bk.NotificationRules, newRule("added"))
+	})
+	return rules, err
+}
+
+func TestDeleteRule(t *testing.T) {
+	tests := map[string]struct {
+		setup        func(b *fake.Backend) clients.Client
+		wantErr      bool
+		wantConflict bool
+		wantRules    []string
+	}{
+		"Deleted": {
+			setup:     func(b *fake.Backend) clients.Client { return b },
+			wantRules: []string{"other"},
+		},
+		"AlreadyDeleted": {
+			setup: func(b *fake.Backend) clients.Client {
+				b.ModifyBucket("bucket", func(bk *fake.Bucket) { bk.NotificationRules = bk.NotificationRules[1:] })
+				return b
+			},
+			wantRules: []string{"other"},
+		},
+		"BucketDeleted": {
+			setup: func(b *fake.Backend) clients.Client {
+				_ = b.DeleteBucket(context.Background(), "bucket")
+				return b
+			},
+		},
+		"BucketGone": {
+			setup: func(b *fake.Backend) clients.Client {
+				b.SetError("GetBucketNotificationRules", &clients.B2Error{Status: 400, Code: clients.B2ErrorCodeBadBucketID})
+				return b
+			},
+			wantRules: []string{"mine", "other"},
+		},
+		"OtherBadRequest": {
+			setup: func(b *fake.Backend) clients.Client {
+				b.SetError("GetBucketNotificationRules", &clients.B2Error{Status: 400, Code: "bad_request"})
+				return b
+			},
+			wantErr:   true,
+			wantRules: []string{"mine", "other"},
+		},
+		"RulesChanged": {
+			setup:        func(b *fake.Backend) clients.Client { return racingBackend{Backend: b, bucketName: "bucket"} },
+			wantErr:      true,
+			wantConflict: true,
+			wantRules:    []string{"mine", "other", "added"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := fake.NewBackend()
+			b.PutBucket(fake.Bucket{Name: "bucket", ID: "bucket-id", NotificationRules: []clients.B2NotificationRule{newRule("mine"), newRule("other")}})
+
+			err := deleteRule(context.Background(), tc.setup(b), "bucket-id", "mine")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("deleteRule(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if got := clients.IsRevisionConflict(err); got != tc.wantConflict {
+				t.Errorf("IsRevisionConflict(%v): want %t, got %t", err, tc.wantConflict, got)
+			}
+			if tc.wantConflict && retryAfter(err) != 5*time.Second {
+				t.Errorf("retryAfter(%v): want 5s, got %s", err, retryAfter(err))
+			}
+
+			bk, _ := b.Bucket("bucket")
+			var got []string
+			for _, r := range bk.NotificationRules {
+				got = append(got, r.Name)
+			}
+			if diff := cmp.Diff(tc.wantRules, got); diff != "" {
+				t.Errorf("rules: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
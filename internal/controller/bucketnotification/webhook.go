@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucketnotification
+
+import (
+	"context"
+	"net/url"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/validation"
+)
+
+// SetupBucketNotificationWebhook adds a webhook that validates
+// BucketNotifications when they are created or updated.
+func SetupBucketNotificationWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &backblazev1.BucketNotification{}).
+		WithValidator(&Validator{}).
+		Complete()
+}
+
+// A Validator validates BucketNotifications.
+type Validator struct{}
+
+// ValidateCreate validates a new BucketNotification.
+func (v *Validator) ValidateCreate(_ context.Context, n *backblazev1.BucketNotification) (admission.Warnings, error) {
+	return nil, invalid(n, validateParameters(&n.Spec.ForProvider))
+}
+
+// ValidateUpdate validates an updated BucketNotification, including that its
+// bucket and rule name haven't changed. Only problems the update introduces
+// are reported.
+func (v *Validator) ValidateUpdate(_ context.Context, old, n *backblazev1.BucketNotification) (admission.Warnings, error) {
+	if validation.SkipUpdate(old, n) {
+		return nil, nil
+	}
+	errs := validation.NewErrors(validateParameters(&old.Spec.ForProvider), validateParameters(&n.Spec.ForProvider))
+	params := field.NewPath("spec", "forProvider")
+	o, p := &old.Spec.ForProvider, &n.Spec.ForProvider
+	if o.BucketID != nil && (p.BucketID == nil || *p.BucketID != *o.BucketID) {
+		errs = append(errs, field.Forbidden(params.Child("bucketId"), "bucketId is immutable"))
+	}
+	if o.BucketRef != nil && (p.BucketRef == nil || p.BucketRef.Name != o.BucketRef.Name) {
+		errs = append(errs, field.Forbidden(params.Child("bucketRef"), "bucketRef is immutable"))
+	}
+	if n.GetRuleName() != old.GetRuleName() {
+		errs = append(errs, field.Forbidden(params.Child("ruleName"), "ruleName is immutable"))
+	}
+	return nil, invalid(n, errs)
+}
+
+// ValidateDelete allows all BucketNotifications to be deleted.
+func (v *Validator) ValidateDelete(_ context.Context, _ *backblazev1.BucketNotification) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateParameters validates the supplied BucketNotification parameters.
+func validateParameters(p *backblazev1.BucketNotificationParameters) field.ErrorList {
+	var errs field.ErrorList
+	params := field.NewPath("spec", "forProvider")
+
+	if p.BucketID == nil && p.BucketRef == nil {
+		errs = append(errs, field.Required(params.Child("bucketId"), "either bucketId or bucketRef must be set"))
+	}
+
+	if len(p.EventTypes) == 0 {
+		errs = append(errs, field.Required(params.Child("eventTypes"), "at least one event type is required"))
+	}
+	seen := map[backblazev1.NotificationEventType]bool{}
+	for i, t := range p.EventTypes {
+		if seen[t] {
+			errs = append(errs, field.Duplicate(params.Child("eventTypes").Index(i), t))
+		}
+		seen[t] = true
+	}
+
+	if u, err := url.Parse(p.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = append(errs, field.Invalid(params.Child("url"), p.URL, "must be an https:// URL"))
+	}
+	return errs
+}
+
+// invalid returns an Invalid error for the supplied BucketNotification, or
+// nil if there are no errors.
+func invalid(n *backblazev1.BucketNotification, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(backblazev1.BucketNotificationGroupKind, n.GetName(), errs)
+}
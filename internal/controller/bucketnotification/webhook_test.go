@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bucketnotification
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+func TestValidateParameters(t *testing.T) {
+	created := backblazev1.NotificationEventType("b2:ObjectCreated:*")
+	tests := map[string]struct {
+		params backblazev1.BucketNotificationParameters
+		want   []string
+	}{
+		"Valid": {
+			params: backblazev1.BucketNotificationParameters{
+				BucketID:   ptr.To("bucket-id"),
+				EventTypes: []backblazev1.NotificationEventType{created},
+				URL:        "https://example.com/hook",
+			},
+		},
+		"NoBucket": {
+			params: backblazev1.BucketNotificationParameters{
+				EventTypes: []backblazev1.NotificationEventType{created},
+				URL:        "https://example.com/hook",
+			},
+			want: []string{"spec.forProvider.bucketId"},
+		},
+		"NoEventTypes": {
+			params: backblazev1.BucketNotificationParameters{
+				BucketRef: &xpv1.Reference{Name: "my-bucket"},
+				URL:       "https://example.com/hook",
+			},
+			want: []string{"spec.forProvider.eventTypes"},
+		},
+		"DuplicateEventType": {
+			params: backblazev1.BucketNotificationParameters{
+				BucketRef:  &xpv1.Reference{Name: "my-bucket"},
+				EventTypes: []backblazev1.NotificationEventType{created, created},
+				URL:        "https://example.com/hook",
+			},
+			want: []string{"spec.forProvider.eventTypes[1]"},
+		},
+		"InsecureURL": {
+			params: backblazev1.BucketNotificationParameters{
+				BucketRef:  &xpv1.Reference{Name: "my-bucket"},
+				EventTypes: []backblazev1.NotificationEventType{created},
+				URL:        "http://example.com/hook",
+			},
+			want: []string{"spec.forProvider.url"},
+		},
+		"URLWithoutHost": {
+			params: backblazev1.BucketNotificationParameters{
+				BucketRef:  &xpv1.Reference{Name: "my-bucket"},
+				EventTypes: []backblazev1.NotificationEventType{created},
+				URL:        "https:///hook",
+			},
+			want: []string{"spec.forProvider.url"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, e := range validateParameters(&tc.params) {
+				got = append(got, e.Field)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("validateParameters(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	base := backblazev1.BucketNotificationParameters{
+		BucketID:   ptr.To("bucket-id"),
+		BucketRef:  &xpv1.Reference{Name: "my-bucket"},
+		RuleName:   "my-rule",
+		EventTypes: []backblazev1.NotificationEventType{"b2:ObjectCreated:*"},
+		URL:        "https://example.com/hook",
+	}
+	tests := map[string]struct {
+		update  func(p *backblazev1.BucketNotificationParameters)
+		wantErr bool
+	}{
+		"EventTypesChanged": {
+			update: func(p *backblazev1.BucketNotificationParameters) {
+				p.EventTypes = []backblazev1.NotificationEventType{"b2:ObjectDeleted:*"}
+			},
+		},
+		"NewProblem": {
+			update: func(p *backblazev1.BucketNotificationParameters) {
+				p.URL = "http://example.com/hook"
+			},
+			wantErr: true,
+		},
+		"BucketIDChanged": {
+			update: func(p *backblazev1.BucketNotificationParameters) {
+				p.BucketID = ptr.To("other-bucket-id")
+			},
+			wantErr: true,
+		},
+		"BucketRefChanged": {
+			update: func(p *backblazev1.BucketNotificationParameters) {
+				p.BucketRef = &xpv1.Reference{Name: "my-other-bucket"}
+			},
+			wantErr: true,
+		},
+		"RuleNameChanged": {
+			update: func(p *backblazev1.BucketNotificationParameters) {
+				p.RuleName = "my-other-rule"
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := &backblazev1.BucketNotification{Spec: backblazev1.BucketNotificationSpec{ForProvider: *base.DeepCopy()}}
+			n := old.DeepCopy()
+			tc.update(&n.Spec.ForProvider)
+			_, err := (&Validator{}).ValidateUpdate(context.Background(), old, n)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateUpdate(...): want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
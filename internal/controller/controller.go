@@ -20,6 +20,7 @@ package controller
 import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/rossigee/provider-backblaze/internal/controller/bucket"
+	"github.com/rossigee/provider-backblaze/internal/controller/bucketnotification"
 	"github.com/rossigee/provider-backblaze/internal/controller/policy"
 	"github.com/rossigee/provider-backblaze/internal/controller/user"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,5 +38,22 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	if err := policy.SetupPolicy(mgr, o); err != nil {
 		return err
 	}
+	if err := bucketnotification.SetupBucketNotification(mgr, o); err != nil {
+		return err
+	}
 	return nil
 }
+
+// SetupWebhooks sets up all admission webhooks for the Backblaze provider.
+func SetupWebhooks(mgr ctrl.Manager) error {
+	if err := bucket.SetupBucketWebhook(mgr); err != nil {
+		return err
+	}
+	if err := user.SetupUserWebhook(mgr); err != nil {
+		return err
+	}
+	if err := policy.SetupPolicyWebhook(mgr); err != nil {
+		return err
+	}
+	return bucketnotification.SetupBucketNotificationWebhook(mgr)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/bucketpolicy"
+)
+
+const (
+	// policyVersion is the version of the policy language documents use.
+	policyVersion = "2012-10-17"
+
+	// principalAll is the principal that means everyone.
+	principalAll = "*"
+
+	errFmtUnresolvedResource = "resource %d of statement %d has no bucket"
+	errTemplateNoBucket      = "publicReadPrefix and denyInsecureTransport require bucket, bucketRef, allowBucket or readOnlyBucket"
+	errComposeRawPolicy      = "cannot add template statements to raw policy"
+)
+
+// A document is an S3-compatible bucket policy document. Its fields are
+// ordered, and its lists sorted, so that it marshals to canonical JSON.
+type document struct {
+	Version   string      `json:"Version"`
+	Statement []statement `json:"Statement"`
+}
+
+// A statement of a document. Principal is either principalAll or a map of
+// principal type to principals.
+type statement struct {
+	Sid       string                         `json:"Sid,omitempty"`
+	Effect    string                         `json:"Effect"`
+	Principal interface{}                    `json:"Principal,omitempty"`
+	Action    []string                       `json:"Action"`
+	Resource  []string                       `json:"Resource"`
+	Condition map[string]map[string][]string `json:"Condition,omitempty"`
+}
+
+// bucketARN returns the ARN of the named bucket.
+func bucketARN(bucket string) string {
+	return fmt.Sprintf("arn:aws:s3:::%s", bucket)
+}
+
+// objectsARN returns the ARN of the objects in the named bucket whose names
+// start with prefix.
+func objectsARN(bucket, prefix string) string {
+	return fmt.Sprintf("arn:aws:s3:::%s/%s*", bucket, prefix)
+}
+
+// allowBucketStatements returns the statements that allow all operations on
+// the named bucket and its objects. They have no principal, so validation
+// rejects any document they are part of rather than put it.
+func allowBucketStatements(bucket string) []backblazev1.PolicyStatement {
+	return []backblazev1.PolicyStatement{{
+		Effect:  backblazev1.PolicyEffectAllow,
+		Actions: []string{"s3:*"},
+		Resources: []backblazev1.PolicyResource{
+			{Bucket: ptr.To(bucket)},
+			{Bucket: ptr.To(bucket), Prefix: ptr.To("")},
+		},
+	}}
+}
+
+// templateStatements returns the statements generated by the templates of
+// the supplied parameters, such as PublicReadPrefix, in a fixed order.
+func templateStatements(params backblazev1.PolicyParameters) ([]backblazev1.PolicyStatement, error) {
+	var stmts []backblazev1.PolicyStatement
+	bucket := params.GetTargetBucket()
+	if (params.PublicReadPrefix != nil || ptr.Deref(params.DenyInsecureTransport, false)) && bucket == "" {
+		return nil, errors.New(errTemplateNoBucket)
+	}
+
+	if params.PublicReadPrefix != nil {
+		stmts = append(stmts, backblazev1.PolicyStatement{
+			SID:        "PublicRead",
+			Effect:     backblazev1.PolicyEffectAllow,
+			Principals: []string{principalAll},
+			Actions:    []string{"s3:GetObject"},
+			Resources:  []backblazev1.PolicyResource{{Bucket: ptr.To(bucket), Prefix: params.PublicReadPrefix}},
+		})
+	}
+
+	if params.ReadOnlyBucket != nil {
+		principals := params.ReadOnlyPrincipals
+		if len(principals) == 0 {
+			principals = []string{principalAll}
+		}
+		stmts = append(stmts,
+			backblazev1.PolicyStatement{
+				SID:        "ReadOnlyBucket",
+				Effect:     backblazev1.PolicyEffectAllow,
+				Principals: principals,
+				Actions:    []string{"s3:GetBucketLocation", "s3:ListBucket"},
+				Resources:  []backblazev1.PolicyResource{{Bucket: params.ReadOnlyBucket}},
+			},
+			backblazev1.PolicyStatement{
+				SID:        "ReadOnlyObjects",
+				Effect:     backblazev1.PolicyEffectAllow,
+				Principals: principals,
+				Actions:    []string{"s3:GetObject"},
+				Resources:  []backblazev1.PolicyResource{{Bucket: params.ReadOnlyBucket, Prefix: ptr.To("")}},
+			},
+		)
+	}
+
+	if ptr.Deref(params.DenyInsecureTransport, false) {
+		stmts = append(stmts, backblazev1.PolicyStatement{
+			SID:        "DenyInsecureTransport",
+			Effect:     backblazev1.PolicyEffectDeny,
+			Principals: []string{principalAll},
+			Actions:    []string{"s3:*"},
+			Resources: []backblazev1.PolicyResource{
+				{Bucket: ptr.To(bucket)},
+				{Bucket: ptr.To(bucket), Prefix: ptr.To("")},
+			},
+			Conditions: []backblazev1.PolicyCondition{{Operator: "Bool", Key: "aws:SecureTransport", Values: []string{"false"}}},
+		})
+	}
+	return stmts, nil
+}
+
+// composeRawPolicy returns the supplied raw policy document with the
+// supplied statements appended. The raw document is returned unchanged if
+// there are no statements to append.
+func composeRawPolicy(raw string, stmts []backblazev1.PolicyStatement) (string, error) {
+	if len(stmts) == 0 {
+		return raw, nil
+	}
+	d, err := bucketpolicy.Parse(raw)
+	if err != nil {
+		return "", errors.Wrap(err, errComposeRawPolicy)
+	}
+	generated, err := generateDocument(stmts)
+	if err != nil {
+		return "", err
+	}
+	g, err := bucketpolicy.Parse(generated)
+	if err != nil {
+		return "", errors.Wrap(err, errComposeRawPolicy)
+	}
+	if d.Version == "" {
+		d.Version = policyVersion
+	}
+	d.Statement = append(d.Statement, g.Statement...)
+
+	b, err := json.MarshalIndent(d, "", "  ")
+	return string(b), errors.Wrap(err, errComposeRawPolicy)
+}
+
+// generateDocument returns the canonical JSON policy document of the
+// supplied statements. Statements keep their order, while actions,
+// principals, resources and condition values are sorted and deduplicated.
+// The buckets of all resources must have been resolved.
+func generateDocument(stmts []backblazev1.PolicyStatement) (string, error) {
+	doc := document{Version: policyVersion, Statement: make([]statement, 0, len(stmts))}
+	for i, s := range stmts {
+		out := statement{
+			Sid:    s.SID,
+			Effect: s.Effect,
+			Action: bucketpolicy.SortedSet(s.Actions),
+		}
+		if out.Effect == "" {
+			out.Effect = backblazev1.PolicyEffectAllow
+		}
+		if p := principal(s.Principals); p != nil {
+			out.Principal = p
+		}
+
+		resources := make([]string, 0, len(s.Resources))
+		for j, r := range s.Resources {
+			if ptr.Deref(r.Bucket, "") == "" {
+				return "", errors.Errorf(errFmtUnresolvedResource, j, i)
+			}
+			if r.Prefix == nil {
+				resources = append(resources, bucketARN(*r.Bucket))
+				continue
+			}
+			resources = append(resources, objectsARN(*r.Bucket, *r.Prefix))
+		}
+		out.Resource = bucketpolicy.SortedSet(resources)
+
+		for _, c := range s.Conditions {
+			if out.Condition == nil {
+				out.Condition = map[string]map[string][]string{}
+			}
+			if out.Condition[c.Operator] == nil {
+				out.Condition[c.Operator] = map[string][]string{}
+			}
+			out.Condition[c.Operator][c.Key] = bucketpolicy.SortedSet(append(out.Condition[c.Operator][c.Key], c.Values...))
+		}
+
+		doc.Statement = append(doc.Statement, out)
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	return string(b), err
+}
+
+// principal returns the Principal of a statement with the supplied
+// principals, or nil if there are none.
+func principal(principals []string) interface{} {
+	if len(principals) == 0 {
+		return nil
+	}
+	for _, p := range principals {
+		if p == principalAll {
+			return principalAll
+		}
+	}
+	return map[string][]string{"AWS": bucketpolicy.SortedSet(principals)}
+}
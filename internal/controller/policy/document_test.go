@@ -0,0 +1,331 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+func TestGenerateDocument(t *testing.T) {
+	tests := map[string]struct {
+		stmts   []backblazev1.PolicyStatement
+		want    string
+		wantErr bool
+	}{
+		"AllowBucket": {
+			stmts: allowBucketStatements("test-bucket"),
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "s3:*"
+      ],
+      "Resource": [
+        "arn:aws:s3:::test-bucket",
+        "arn:aws:s3:::test-bucket/*"
+      ]
+    }
+  ]
+}`,
+		},
+		"Canonical": {
+			stmts: []backblazev1.PolicyStatement{{
+				SID:        "PublicRead",
+				Actions:    []string{"s3:GetObject", "s3:ListBucket", "s3:GetObject"},
+				Principals: []string{"*"},
+				Resources: []backblazev1.PolicyResource{
+					{Bucket: ptr.To("b"), Prefix: ptr.To("public/")},
+					{Bucket: ptr.To("a")},
+				},
+				Conditions: []backblazev1.PolicyCondition{
+					{Operator: "StringLike", Key: "s3:prefix", Values: []string{"public/", "docs/"}},
+					{Operator: "Bool", Key: "aws:SecureTransport", Values: []string{"true"}},
+				},
+			}, {
+				Effect:     backblazev1.PolicyEffectDeny,
+				Actions:    []string{"s3:DeleteObject"},
+				Principals: []string{"user-b", "user-a"},
+				Resources:  []backblazev1.PolicyResource{{Bucket: ptr.To("b"), Prefix: ptr.To("")}},
+			}},
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "PublicRead",
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": [
+        "s3:GetObject",
+        "s3:ListBucket"
+      ],
+      "Resource": [
+        "arn:aws:s3:::a",
+        "arn:aws:s3:::b/public/*"
+      ],
+      "Condition": {
+        "Bool": {
+          "aws:SecureTransport": [
+            "true"
+          ]
+        },
+        "StringLike": {
+          "s3:prefix": [
+            "docs/",
+            "public/"
+          ]
+        }
+      }
+    },
+    {
+      "Effect": "Deny",
+      "Principal": {
+        "AWS": [
+          "user-a",
+          "user-b"
+        ]
+      },
+      "Action": [
+        "s3:DeleteObject"
+      ],
+      "Resource": [
+        "arn:aws:s3:::b/*"
+      ]
+    }
+  ]
+}`,
+		},
+		"UnresolvedBucketRef": {
+			stmts: []backblazev1.PolicyStatement{{
+				Actions:   []string{"s3:GetObject"},
+				Resources: []backblazev1.PolicyResource{{BucketRef: &xpv1.Reference{Name: "bucket"}}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := generateDocument(tc.stmts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("generateDocument() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("generateDocument(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyDocument(t *testing.T) {
+	tests := map[string]struct {
+		params  backblazev1.PolicyParameters
+		want    string
+		wantErr bool
+	}{
+		"PublicReadPrefix": {
+			params: backblazev1.PolicyParameters{
+				Bucket:           ptr.To("assets"),
+				PublicReadPrefix: ptr.To("public/"),
+			},
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "PublicRead",
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": [
+        "s3:GetObject"
+      ],
+      "Resource": [
+        "arn:aws:s3:::assets/public/*"
+      ]
+    }
+  ]
+}`,
+		},
+		"ReadOnlyBucket": {
+			params: backblazev1.PolicyParameters{
+				ReadOnlyBucket:     ptr.To("reports"),
+				ReadOnlyPrincipals: []string{"key-b", "key-a"},
+			},
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "ReadOnlyBucket",
+      "Effect": "Allow",
+      "Principal": {
+        "AWS": [
+          "key-a",
+          "key-b"
+        ]
+      },
+      "Action": [
+        "s3:GetBucketLocation",
+        "s3:ListBucket"
+      ],
+      "Resource": [
+        "arn:aws:s3:::reports"
+      ]
+    },
+    {
+      "Sid": "ReadOnlyObjects",
+      "Effect": "Allow",
+      "Principal": {
+        "AWS": [
+          "key-a",
+          "key-b"
+        ]
+      },
+      "Action": [
+        "s3:GetObject"
+      ],
+      "Resource": [
+        "arn:aws:s3:::reports/*"
+      ]
+    }
+  ]
+}`,
+		},
+		"DenyInsecureTransportWithAllowBucket": {
+			params: backblazev1.PolicyParameters{
+				AllowBucket:           ptr.To("data"),
+				DenyInsecureTransport: ptr.To(true),
+			},
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "s3:*"
+      ],
+      "Resource": [
+        "arn:aws:s3:::data",
+        "arn:aws:s3:::data/*"
+      ]
+    },
+    {
+      "Sid": "DenyInsecureTransport",
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": [
+        "s3:*"
+      ],
+      "Resource": [
+        "arn:aws:s3:::data",
+        "arn:aws:s3:::data/*"
+      ],
+      "Condition": {
+        "Bool": {
+          "aws:SecureTransport": [
+            "false"
+          ]
+        }
+      }
+    }
+  ]
+}`,
+		},
+		"RawPolicyWithTemplate": {
+			params: backblazev1.PolicyParameters{
+				Bucket:                ptr.To("data"),
+				RawPolicy:             ptr.To(`{"Statement":{"Sid":"Mine","Effect":"Allow","Principal":{"AWS":"key-a"},"Action":"s3:PutObject","Resource":"arn:aws:s3:::data/uploads/*"}}`),
+				DenyInsecureTransport: ptr.To(true),
+			},
+			want: `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "Mine",
+      "Effect": "Allow",
+      "Principal": {
+        "AWS": [
+          "key-a"
+        ]
+      },
+      "Action": [
+        "s3:PutObject"
+      ],
+      "Resource": [
+        "arn:aws:s3:::data/uploads/*"
+      ]
+    },
+    {
+      "Sid": "DenyInsecureTransport",
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": [
+        "s3:*"
+      ],
+      "Resource": [
+        "arn:aws:s3:::data",
+        "arn:aws:s3:::data/*"
+      ],
+      "Condition": {
+        "Bool": {
+          "aws:SecureTransport": [
+            "false"
+          ]
+        }
+      }
+    }
+  ]
+}`,
+		},
+		"RawPolicyUnchangedWithoutTemplates": {
+			params: backblazev1.PolicyParameters{RawPolicy: ptr.To(`{"Statement":[]}`)},
+			want:   `{"Statement":[]}`,
+		},
+		"TemplateWithoutBucket": {
+			params:  backblazev1.PolicyParameters{PublicReadPrefix: ptr.To("")},
+			wantErr: true,
+		},
+		"AllowBucketAndRawPolicy": {
+			params: backblazev1.PolicyParameters{
+				AllowBucket: ptr.To("data"),
+				RawPolicy:   ptr.To(`{"Statement":[]}`),
+			},
+			wantErr: true,
+		},
+		"Nothing": {
+			params:  backblazev1.PolicyParameters{Bucket: ptr.To("data")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := policyDocument(tc.params)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("policyDocument() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("policyDocument(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,15 +24,23 @@ import (
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/pkg/errors"
 
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
 	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/bucketpolicy"
 	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/features"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+	"github.com/rossigee/provider-backblaze/internal/scope"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,21 +56,56 @@ const (
 	errCreateBackblazeClient = "cannot create Backblaze client"
 	errCreatePolicy          = "cannot create policy"
 	errDeletePolicy          = "cannot delete policy"
+	errDeleteBucketPolicy    = "cannot delete bucket policy"
 	errGetPolicy             = "cannot get policy"
-	errInvalidPolicyParams   = "invalid policy parameters: specify either allowBucket or rawPolicy, not both"
-	errGenerateSimplePolicy  = "cannot generate simple policy document"
+	errGetBucketPolicy       = "cannot get bucket policy"
+	errPutBucketPolicy       = "cannot put bucket policy"
+	errInvalidPolicyParams   = "invalid policy parameters: specify at most one of allowBucket, rawPolicy or statements, and at least one of them or a template"
+	errGeneratePolicy        = "cannot generate policy document"
 	errInvalidRawPolicy      = "invalid raw policy: must be valid JSON"
+	errResolveRefs           = "cannot resolve references"
+	errFmtGetRef             = "cannot get referenced %s %q"
+	errFmtRefNotReady        = "referenced %s %q does not have a name yet"
+	errAddFinalizer          = "cannot add finalizer"
+	errRemFinalizer          = "cannot remove finalizer"
+	errFmtNotUpToDate        = "the policy of bucket %q differs from the desired policy, and the management policies don't allow it to be changed"
+
+	// finalizerName blocks deletion of a Policy until the policy it put on
+	// its bucket has been deleted or orphaned.
+	finalizerName = "finalizer.managedresource.crossplane.io"
 )
 
-// SetupPolicy adds a controller that reconciles Policy managed resources.
+const (
+	reasonUpdatedBucketPolicy event.Reason = "UpdatedBucketPolicy"
+	reasonKeptBucketPolicy    event.Reason = "KeptBucketPolicy"
+)
+
+// SetupPolicy adds controllers that reconcile cluster scoped and namespaced
+// Policy managed resources.
 func SetupPolicy(mgr ctrl.Manager, o controller.Options) error {
-	r := &PolicyReconciler{
-		Client: mgr.GetClient(),
+	for _, s := range []scope.Scope{scope.Cluster, scope.Namespaced} {
+		r := &PolicyReconciler{
+			Client:      mgr.GetClient(),
+			NewClientFn: clients.NewClient,
+			Scope:       s,
+		}
+		if err := r.SetupWithManager(mgr, o); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// SetupWithManager registers the reconciler with the supplied manager.
+func (r *PolicyReconciler) SetupWithManager(mgr ctrl.Manager, o controller.Options) error {
+	r.ManagementPoliciesEnabled = o.Features.Enabled(features.EnableAlphaManagementPolicies)
+	name := r.Scope.ControllerName("policy")
+	if r.Recorder == nil {
+		r.Recorder = recorder.New(mgr.GetEventRecorder(name))
+	}
 	return ctrl.NewControllerManagedBy(mgr).
-		Named("policy-controller").
-		For(&backblazev1.Policy{}).
+		Named(name).
+		For(r.Scope.NewPolicy()).
 		Watches(&apisv1beta1.ProviderConfig{}, handler.Funcs{}).
 		Complete(r)
 }
@@ -70,6 +113,19 @@ func SetupPolicy(mgr ctrl.Manager, o controller.Options) error {
 // PolicyReconciler reconciles a Policy object
 type PolicyReconciler struct {
 	Client client.Client
+
+	// NewClientFn creates the Backblaze client used to manage policies.
+	NewClientFn clients.NewClientFn
+
+	// ManagementPoliciesEnabled enables support for the
+	// spec.managementPolicies of cluster scoped Policies.
+	ManagementPoliciesEnabled bool
+
+	// Recorder records events, such as changes to bucket policies.
+	Recorder event.Recorder
+
+	// Scope of the Policies reconciled.
+	Scope scope.Scope
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -78,7 +134,7 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 	logger := log.FromContext(ctx).WithValues("policy", req.NamespacedName)
 
 	// Fetch the Policy instance
-	policy := &backblazev1.Policy{}
+	policy := r.Scope.NewPolicy()
 	err := r.Client.Get(ctx, req.NamespacedName, policy)
 	if err != nil {
 		if client.IgnoreNotFound(err) == nil {
@@ -92,9 +148,29 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 
 	logger.Info("Reconciling policy", "policyName", policy.GetPolicyName())
 
-	// Check for deletion - in this simple implementation, we let Kubernetes handle deletion
-	if !policy.GetDeletionTimestamp().IsZero() {
-		return r.handleDeletion(ctx, policy)
+	mp := scope.ManagementPolicies(r.ManagementPoliciesEnabled, policy)
+	if err := mp.Validate(); err != nil {
+		logger.Error(err, "Invalid management policies")
+		r.setCondition(policy, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{}, r.Client.Status().Update(ctx, policy)
+	}
+
+	if meta.WasDeleted(policy) {
+		return r.handleDeletion(ctx, policy, mp)
+	}
+
+	if mp.IsPaused() {
+		logger.Info("Reconciliation is paused via the management policies")
+		r.setCondition(policy, xpv1.TypeSynced, "False", "ReconcilePaused", "Reconciliation is paused via the management policies")
+		return reconcile.Result{}, r.Client.Status().Update(ctx, policy)
+	}
+
+	if !meta.FinalizerExists(policy, finalizerName) {
+		meta.AddFinalizer(policy, finalizerName)
+		if err := r.Client.Update(ctx, policy); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
 	}
 
 	// Get provider config and create client
@@ -110,14 +186,35 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 		return reconcile.Result{RequeueAfter: requeueAfter}, r.Client.Status().Update(ctx, policy)
 	}
 
-	// Check if policy already exists
-	if policy.Status.AtProvider.PolicyName == "" {
-		// Create policy
-		if err := r.createPolicy(ctx, policy, service); err != nil {
+	if err := r.resolveReferences(ctx, policy); err != nil {
+		logger.Info("Cannot resolve references, retrying in 10 seconds", "error", err)
+		r.setCondition(policy, xpv1.TypeSynced, "False", "ReconcileError", errors.Wrap(err, errResolveRefs).Error())
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, r.Client.Status().Update(ctx, policy)
+	}
+
+	upToDate, err := r.applyPolicy(ctx, policy, service, mp)
+	if err != nil {
+		var invalid *bucketpolicy.ValidationError
+		switch {
+		case errors.As(err, &invalid):
+			logger.Info("Policy document is invalid", "error", err)
+			r.setCondition(policy, xpv1.TypeReady, "False", "InvalidPolicy", err.Error())
+			r.setCondition(policy, xpv1.TypeSynced, "False", "InvalidPolicy", err.Error())
+		case policy.GetAtProvider().PolicyName == "":
 			logger.Error(err, "Failed to create policy")
 			r.setCondition(policy, xpv1.TypeReady, "False", "CreateError", err.Error())
-			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, policy)
+		default:
+			logger.Error(err, "Failed to update policy")
+			r.setCondition(policy, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
 		}
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, policy)
+	}
+	if !upToDate {
+		bucket := policy.GetForProvider().GetTargetBucket()
+		logger.Info("Bucket policy is not up to date and the management policies don't allow changing it", "bucket", bucket)
+		r.setCondition(policy, xpv1.TypeReady, "False", "NotUpToDate", fmt.Sprintf(errFmtNotUpToDate, bucket))
+		r.setCondition(policy, xpv1.TypeSynced, "True", "ReconcileSuccess", "Successfully reconciled")
+		return reconcile.Result{RequeueAfter: 5 * time.Minute}, r.Client.Status().Update(ctx, policy)
 	}
 
 	// Policy exists and is ready
@@ -128,72 +225,147 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req reconcile.Request)
 	return reconcile.Result{RequeueAfter: 5 * time.Minute}, r.Client.Status().Update(ctx, policy)
 }
 
-func (r *PolicyReconciler) handleDeletion(ctx context.Context, policy *backblazev1.Policy) (reconcile.Result, error) {
+func (r *PolicyReconciler) handleDeletion(ctx context.Context, policy scope.Policy, mp managed.ManagementPoliciesChecker) (reconcile.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// For this implementation, we'll simulate policy deletion
-	// In a real implementation, you would use the Backblaze B2 API
-	// TODO: Implement actual B2 policy deletion
+	if !meta.FinalizerExists(policy, finalizerName) {
+		return reconcile.Result{}, nil
+	}
+
+	// Only a policy this resource put on its bucket is deleted, and only if
+	// the bucket still has it.
+	bucket := policy.GetForProvider().GetTargetBucket()
+	applied := policy.GetAtProvider().PolicyDocument
+	if mp.ShouldDelete() && bucket != "" && applied != "" {
+		service, err := r.getBackblazeClient(ctx, policy)
+		if err != nil {
+			logger.Error(err, "Failed to create Backblaze client")
+			r.setCondition(policy, xpv1.TypeReady, "False", "ClientError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, policy)
+		}
+
+		current, err := service.GetBucketPolicy(ctx, bucket)
+		switch {
+		case isPolicyNotFound(err):
+			logger.Info("Bucket has no policy to delete", "bucket", bucket)
+		case err != nil:
+			err = errors.Wrap(err, errGetBucketPolicy)
+			logger.Error(err, "Failed to get bucket policy")
+			r.Recorder.Event(policy, recorder.Warning(recorder.ReasonCannotObserve, err))
+			r.setCondition(policy, xpv1.TypeReady, "False", "DeleteError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, policy)
+		case !bucketpolicy.Equal(current, applied):
+			logger.Info("Bucket policy was replaced since it was applied, leaving it in place", "bucket", bucket)
+			r.Recorder.Event(policy, event.Normal(reasonKeptBucketPolicy, fmt.Sprintf("Kept the policy of bucket %q, which was replaced since this Policy applied it", bucket)))
+		default:
+			if err := service.DeleteBucketPolicy(ctx, bucket); err != nil && !isPolicyNotFound(err) {
+				err = errors.Wrap(err, errDeleteBucketPolicy)
+				logger.Error(err, "Failed to delete bucket policy")
+				r.Recorder.Event(policy, recorder.Warning(recorder.ReasonCannotDelete, err))
+				r.setCondition(policy, xpv1.TypeReady, "False", "DeleteError", err.Error())
+				return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, policy)
+			}
+			r.Recorder.Event(policy, event.Normal(recorder.ReasonDeleted, fmt.Sprintf("Deleted the policy of bucket %q", bucket)))
+		}
+	}
+
+	meta.RemoveFinalizer(policy, finalizerName)
+	if err := r.Client.Update(ctx, policy); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errRemFinalizer)
+	}
 
 	logger.Info("Policy deletion handled")
 	return reconcile.Result{}, nil
 }
 
-func (r *PolicyReconciler) createPolicy(ctx context.Context, policy *backblazev1.Policy, service *clients.BackblazeClient) error {
-	// Validate policy parameters
-	params := policy.Spec.ForProvider
-	if (params.AllowBucket != nil && params.RawPolicy != nil) ||
-		(params.AllowBucket == nil && params.RawPolicy == nil) {
-		return errors.New(errInvalidPolicyParams)
+// applyPolicy generates and validates the policy document and, if the policy
+// has a target bucket, puts it as the bucket's policy unless the bucket
+// already has an equivalent one. It returns false if the bucket's policy
+// differs but the management policies don't allow it to be put.
+func (r *PolicyReconciler) applyPolicy(ctx context.Context, policy scope.Policy, service clients.Client, mp managed.ManagementPoliciesChecker) (bool, error) {
+	params := *policy.GetForProvider()
+	doc, err := policyDocument(params)
+	if err != nil {
+		return false, err
+	}
+	bucket := params.GetTargetBucket()
+	if err := bucketpolicy.Validate(doc, bucket); err != nil {
+		return false, err
 	}
 
-	var policyDocument string
-	var err error
-
-	if params.AllowBucket != nil {
-		// Generate simple policy for the bucket
-		policyDocument, err = r.generateSimplePolicy(*params.AllowBucket)
-		if err != nil {
-			return errors.Wrap(err, errGenerateSimplePolicy)
+	if bucket != "" {
+		current, err := service.GetBucketPolicy(ctx, bucket)
+		exists := err == nil
+		if err != nil && !isPolicyNotFound(err) {
+			err = errors.Wrap(err, errGetBucketPolicy)
+			r.Recorder.Event(policy, recorder.Warning(recorder.ReasonCannotObserve, err))
+			return false, err
 		}
-	} else {
-		// Use raw policy document
-		policyDocument = *params.RawPolicy
-		// Validate it's valid JSON
-		var temp interface{}
-		if err := json.Unmarshal([]byte(policyDocument), &temp); err != nil {
-			return errors.Wrap(err, errInvalidRawPolicy)
+		if !bucketpolicy.Equal(current, doc) {
+			if (exists && !mp.ShouldUpdate()) || (!exists && !mp.ShouldCreate()) {
+				return false, nil
+			}
+			if err := service.PutBucketPolicy(ctx, bucket, doc); err != nil {
+				err = errors.Wrap(err, errPutBucketPolicy)
+				r.Recorder.Event(policy, recorder.Warning(recorder.ReasonCannotUpdate, err))
+				return false, err
+			}
+			msg := fmt.Sprintf("Updated the policy of bucket %q:\n%s", bucket, bucketpolicy.Diff(current, doc))
+			r.Recorder.Event(policy, event.Normal(reasonUpdatedBucketPolicy, msg))
 		}
 	}
 
-	// Get policy name
-	policyName := policy.GetPolicyName()
-
-	// For this implementation, we'll simulate policy creation
-	// In a real implementation, you would use the Backblaze B2 API
-	// TODO: Implement actual B2 policy creation
-
 	// Update the resource status
-	policy.Status.AtProvider.PolicyName = policyName
-	policy.Status.AtProvider.PolicyDocument = policyDocument
-	policy.Status.AtProvider.PolicyID = fmt.Sprintf("policy-%d", policy.GetGeneration())
-	now := metav1.NewTime(time.Now())
-	policy.Status.AtProvider.CreationTime = &now
+	policy.GetAtProvider().PolicyDocument = doc
+	if policy.GetAtProvider().PolicyName == "" {
+		policy.GetAtProvider().PolicyName = policy.GetPolicyName()
+		policy.GetAtProvider().PolicyID = fmt.Sprintf("policy-%d", policy.GetGeneration())
+		now := metav1.NewTime(time.Now())
+		policy.GetAtProvider().CreationTime = &now
+	}
 
-	return nil
+	return true, nil
 }
 
-func (r *PolicyReconciler) getBackblazeClient(ctx context.Context, policy *backblazev1.Policy) (*clients.BackblazeClient, error) {
-	// Determine ProviderConfig name - use "default" if not specified
-	providerConfigName := "default"
-	if policy.GetProviderConfigReference() != nil {
-		providerConfigName = policy.GetProviderConfigReference().Name
+// policyDocument returns the policy document of the supplied parameters.
+// They may specify at most one of AllowBucket, RawPolicy or Statements, to
+// which the statements generated by any templates are added.
+func policyDocument(params backblazev1.PolicyParameters) (string, error) {
+	set := 0
+	for _, isSet := range []bool{params.AllowBucket != nil, params.RawPolicy != nil, len(params.Statements) > 0} {
+		if isSet {
+			set++
+		}
+	}
+	templates, err := templateStatements(params)
+	if err != nil {
+		return "", err
+	}
+	if set > 1 || (set == 0 && len(templates) == 0) {
+		return "", errors.New(errInvalidPolicyParams)
+	}
+
+	var doc string
+	switch {
+	case params.RawPolicy != nil:
+		// Use raw policy document, which must be valid JSON
+		var temp interface{}
+		if err := json.Unmarshal([]byte(*params.RawPolicy), &temp); err != nil {
+			return "", errors.Wrap(err, errInvalidRawPolicy)
+		}
+		doc, err = composeRawPolicy(*params.RawPolicy, templates)
+	case params.AllowBucket != nil:
+		doc, err = generateDocument(append(allowBucketStatements(*params.AllowBucket), templates...))
+	default:
+		stmts := append([]backblazev1.PolicyStatement{}, params.Statements...)
+		doc, err = generateDocument(append(stmts, templates...))
 	}
+	return doc, errors.Wrap(err, errGeneratePolicy)
+}
 
-	pc := &apisv1beta1.ProviderConfig{}
-	// ProviderConfigs are namespaced resources - look in the same namespace as the provider
-	key := client.ObjectKey{Name: providerConfigName, Namespace: "crossplane-system"}
-	if err := r.Client.Get(ctx, key, pc); err != nil {
+func (r *PolicyReconciler) getBackblazeClient(ctx context.Context, policy scope.Policy) (clients.Client, error) {
+	pc, err := scope.GetProviderConfig(ctx, r.Client, policy)
+	if err != nil {
 		// Check if this is a "not found" error that could be due to cache sync timing
 		if client.IgnoreNotFound(err) == nil {
 			// ProviderConfig not found - this could be a cache sync issue
@@ -209,34 +381,68 @@ func (r *PolicyReconciler) getBackblazeClient(ctx context.Context, policy *backb
 		return nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
-	return clients.NewBackblazeClient(*cfg)
+	return r.NewClientFn(*cfg)
 }
 
-// generateSimplePolicy creates a basic policy that allows all operations for a specific bucket
-func (r *PolicyReconciler) generateSimplePolicy(bucketName string) (string, error) {
-	policy := map[string]interface{}{
-		"Version": "2012-10-17",
-		"Statement": []map[string]interface{}{
-			{
-				"Effect": "Allow",
-				"Action": []string{"s3:*"},
-				"Resource": []string{
-					fmt.Sprintf("arn:aws:s3:::%s", bucketName),
-					fmt.Sprintf("arn:aws:s3:::%s/*", bucketName),
-				},
-			},
-		},
-	}
-
-	policyBytes, err := json.MarshalIndent(policy, "", "  ")
-	if err != nil {
-		return "", err
+// resolveReferences sets the names of the Buckets referenced by the policy
+// and by the resources of its statements, and persists them if they changed.
+// References are resolved in the scope, and namespace, of the policy.
+func (r *PolicyReconciler) resolveReferences(ctx context.Context, policy scope.Policy) error {
+	changed := false
+	if ref := policy.GetForProvider().BucketRef; ref != nil {
+		name, err := r.bucketName(ctx, policy, ref)
+		if err != nil {
+			return err
+		}
+		if ptr.Deref(policy.GetForProvider().Bucket, "") != name {
+			policy.GetForProvider().Bucket = ptr.To(name)
+			changed = true
+		}
 	}
+	for i := range policy.GetForProvider().Statements {
+		resources := policy.GetForProvider().Statements[i].Resources
+		for j := range resources {
+			ref := resources[j].BucketRef
+			if ref == nil {
+				continue
+			}
+			name, err := r.bucketName(ctx, policy, ref)
+			if err != nil {
+				return err
+			}
+			if ptr.Deref(resources[j].Bucket, "") != name {
+				resources[j].Bucket = ptr.To(name)
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.Client.Update(ctx, policy)
+}
+
+// bucketName returns the name of the Bucket referenced by the supplied
+// policy.
+func (r *PolicyReconciler) bucketName(ctx context.Context, policy scope.Policy, ref *xpv1.Reference) (string, error) {
+	bucket := r.Scope.NewBucket()
+	if err := r.Client.Get(ctx, scope.ObjectKey(policy, ref.Name), bucket); err != nil {
+		return "", errors.Wrapf(err, errFmtGetRef, backblazev1.BucketKind, ref.Name)
+	}
+	name := bucket.GetAtProvider().BucketName
+	if name == "" {
+		return "", errors.Errorf(errFmtRefNotReady, backblazev1.BucketKind, ref.Name)
+	}
+	return name, nil
+}
 
-	return string(policyBytes), nil
+// isPolicyNotFound returns true if err indicates the bucket has no policy.
+func isPolicyNotFound(err error) bool {
+	return clients.ErrorCode(err) == clients.S3ErrorCodeNoSuchBucketPolicy
 }
 
-func (r *PolicyReconciler) setCondition(policy *backblazev1.Policy, conditionType xpv1.ConditionType, status, reason, message string) {
+func (r *PolicyReconciler) setCondition(policy scope.Policy, conditionType xpv1.ConditionType, status, reason, message string) {
 	policy.SetConditions(xpv1.Condition{
 		Type:               conditionType,
 		Status:             corev1.ConditionStatus(status),
@@ -17,11 +17,27 @@ limitations under the License.
 package policy
 
 import (
+	"context"
 	"testing"
 
+	"github.com/aws/smithy-go"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/rossigee/provider-backblaze/apis"
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/bucketpolicy"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+	"github.com/rossigee/provider-backblaze/internal/scope"
 )
 
 func TestPolicyGetPolicyName(t *testing.T) {
@@ -59,24 +75,9 @@ func TestPolicySetCondition(t *testing.T) {
 	// Test passes if no panic occurs
 }
 
-func TestGenerateSimplePolicy(t *testing.T) {
-	r := &PolicyReconciler{}
-	policy, err := r.generateSimplePolicy("test-bucket")
-	if err != nil {
-		t.Errorf("generateSimplePolicy(...): expected no error, got %v", err)
-	}
-	if policy == "" {
-		t.Error("generateSimplePolicy(...): expected policy document, got empty string")
-	}
-	// Verify it contains the bucket name
-	if !contains(policy, "test-bucket") {
-		t.Error("generateSimplePolicy(...): policy should contain bucket name")
-	}
-}
-
 func TestCreatePolicyValidation(t *testing.T) {
 	allowBucket := "test-bucket"
-	rawPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::test/*"}]}`
+	rawPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::test/*"}]}`
 	invalidPolicy := `{invalid json`
 
 	cases := map[string]struct {
@@ -130,19 +131,199 @@ func TestCreatePolicyValidation(t *testing.T) {
 	}
 }
 
-// Helper function to check if string contains substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
-		(len(substr) > 0 && (s[:len(substr)] == substr ||
-			(len(s) > len(substr) && s[len(s)-len(substr):] == substr) ||
-			containsHelper(s, substr))))
+func TestIsPolicyNotFound(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil": {},
+		"NoSuchBucketPolicy": {
+			err:  errors.Wrap(&smithy.GenericAPIError{Code: clients.S3ErrorCodeNoSuchBucketPolicy}, "failed to get bucket policy"),
+			want: true,
+		},
+		"NoSuchBucket": {
+			err: errors.Wrap(&smithy.GenericAPIError{Code: "NoSuchBucket"}, "failed to get bucket policy"),
+		},
+		"SecretNotFound": {
+			err: errors.New(`secrets "backblaze-creds" not found`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isPolicyNotFound(tc.err); got != tc.want {
+				t.Errorf("isPolicyNotFound(%v): want %t, got %t", tc.err, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyPolicyManagementPolicies(t *testing.T) {
+	const bucket = "my-bucket"
+	live := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`
+	tests := map[string]struct {
+		policies     xpv1.ManagementPolicies
+		live         string
+		wantUpToDate bool
+		wantLive     bool
+	}{
+		"ObserveDiffers": {
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			live:     live,
+			wantLive: true,
+		},
+		"ObserveMissing": {
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+		},
+		"UpdateDiffers": {
+			policies:     xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionUpdate},
+			live:         live,
+			wantUpToDate: true,
+		},
+		"CreateMissing": {
+			policies:     xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionCreate},
+			wantUpToDate: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend := fake.NewBackend()
+			backend.PutBucket(fake.Bucket{Name: bucket, ID: "bucket-id"})
+			if tc.live != "" {
+				if err := backend.PutBucketPolicy(context.Background(), bucket, tc.live); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p := &backblazev1.Policy{Spec: backblazev1.PolicySpec{ForProvider: backblazev1.PolicyParameters{Bucket: ptr.To(bucket), PublicReadPrefix: ptr.To("public/")}}}
+			p.SetManagementPolicies(tc.policies)
+
+			r := &PolicyReconciler{Recorder: event.NewNopRecorder()}
+			upToDate, err := r.applyPolicy(context.Background(), p, backend, scope.ManagementPolicies(true, p))
+			if err != nil {
+				t.Fatalf("applyPolicy(...): %v", err)
+			}
+			if upToDate != tc.wantUpToDate {
+				t.Errorf("applyPolicy(...): want up to date %t, got %t", tc.wantUpToDate, upToDate)
+			}
+			got, _ := backend.Policy(bucket)
+			if tc.wantLive && got != tc.live {
+				t.Errorf("bucket policy: want %s, got %s", tc.live, got)
+			}
+			if !tc.wantUpToDate && p.Status.AtProvider.PolicyDocument != "" {
+				t.Errorf("status.atProvider.policyDocument of a policy that wasn't put: %s", p.Status.AtProvider.PolicyDocument)
+			}
+		})
+	}
+}
+
+func TestApplyPolicyWithoutPrincipal(t *testing.T) {
+	const bucket = "my-bucket"
+	tests := map[string]backblazev1.PolicyParameters{
+		"AllowBucket": {AllowBucket: ptr.To(bucket)},
+		"RawPolicy": {
+			Bucket:    ptr.To(bucket),
+			RawPolicy: ptr.To(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`),
+		},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend := fake.NewBackend()
+			backend.PutBucket(fake.Bucket{Name: bucket, ID: "bucket-id"})
+			p := &backblazev1.Policy{Spec: backblazev1.PolicySpec{ForProvider: params}}
+
+			r := &PolicyReconciler{Recorder: event.NewNopRecorder()}
+			_, err := r.applyPolicy(context.Background(), p, backend, scope.ManagementPolicies(true, p))
+			var invalid *bucketpolicy.ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("applyPolicy(...): want *ValidationError, got %v", err)
+			}
+			if n := backend.CallCount("PutBucketPolicy"); n != 0 {
+				t.Errorf("PutBucketPolicy calls: want 0, got %d", n)
+			}
+			if got, ok := backend.Policy(bucket); ok {
+				t.Errorf("bucket policy: want none, got %s", got)
+			}
+		})
+	}
 }
 
-func containsHelper(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
+func TestHandleDeletion(t *testing.T) {
+	const bucket = "my-bucket"
+	applied := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::my-bucket/*"}]}`
+	replaced := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`
+	tests := map[string]struct {
+		live       string
+		policies   xpv1.ManagementPolicies
+		wantDelete bool
+	}{
+		"Applied": {
+			live:       applied,
+			policies:   xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			wantDelete: true,
+		},
+		"Replaced": {
+			live:     replaced,
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+		},
+		"Missing": {
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+		},
+		"NotDeletable": {
+			live:     applied,
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionUpdate},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			if err := apis.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := corev1.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			pc := &apisv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Namespace: scope.ProviderNamespace, Name: "default"}}
+			pc.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+			pc.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: scope.ProviderNamespace, Name: "creds"}}
+			creds := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: scope.ProviderNamespace, Name: "creds"},
+				Data:       map[string][]byte{clients.SecretKeyApplicationKeyID: []byte("id"), clients.SecretKeyApplicationKey: []byte("key")},
+			}
+			p := &backblazev1.Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Finalizers: []string{finalizerName}},
+				Spec: backblazev1.PolicySpec{
+					DeletionPolicy: xpv1.DeletionDelete,
+					ForProvider:    backblazev1.PolicyParameters{Bucket: ptr.To(bucket), AllowBucket: ptr.To(bucket)},
+				},
+			}
+			p.SetManagementPolicies(tc.policies)
+			p.Status.AtProvider.PolicyDocument = applied
+			c := crfake.NewClientBuilder().WithScheme(s).WithObjects(pc, creds, p).WithStatusSubresource(p).Build()
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(p), p); err != nil {
+				t.Fatal(err)
+			}
+
+			backend := fake.NewBackend()
+			backend.PutBucket(fake.Bucket{Name: bucket, ID: "bucket-id"})
+			if tc.live != "" {
+				if err := backend.PutBucketPolicy(context.Background(), bucket, tc.live); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := &PolicyReconciler{Client: c, NewClientFn: backend.NewClientFn(), Recorder: event.NewNopRecorder()}
+			if _, err := r.handleDeletion(context.Background(), p, scope.ManagementPolicies(true, p)); err != nil {
+				t.Fatalf("handleDeletion(...): %v", err)
+			}
+			if got := backend.CallCount("DeleteBucketPolicy") > 0; got != tc.wantDelete {
+				t.Errorf("DeleteBucketPolicy called: want %t, got %t", tc.wantDelete, got)
+			}
+			if len(p.GetFinalizers()) != 0 {
+				t.Errorf("finalizers: want none, got %v", p.GetFinalizers())
+			}
+		})
 	}
-	return false
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	policyv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/policy/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/bucketpolicy"
+	"github.com/rossigee/provider-backblaze/internal/scope"
+	"github.com/rossigee/provider-backblaze/internal/validation"
+)
+
+// SetupPolicyWebhook adds webhooks that validate the policy documents of
+// cluster scoped and namespaced Policies when they are created or updated.
+func SetupPolicyWebhook(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &backblazev1.Policy{}).
+		WithValidator(&Validator[*backblazev1.Policy]{}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr, &policyv1beta1.Policy{}).
+		WithValidator(&Validator[*policyv1beta1.Policy]{}).
+		Complete()
+}
+
+// A Validator validates the policy documents of Policies of either scope.
+type Validator[T scope.Policy] struct{}
+
+// ValidateCreate validates the policy document of a new Policy.
+func (v *Validator[T]) ValidateCreate(_ context.Context, p T) (admission.Warnings, error) {
+	return nil, validatePolicy(p)
+}
+
+// ValidateUpdate validates the policy document of an updated Policy, and that
+// its target bucket hasn't changed once set. Only problems the update
+// introduces are reported.
+func (v *Validator[T]) ValidateUpdate(_ context.Context, old, p T) (admission.Warnings, error) {
+	if validation.SkipUpdate(old, p) {
+		return nil, nil
+	}
+	from, to := old.GetForProvider().GetTargetBucket(), p.GetForProvider().GetTargetBucket()
+	if from != "" && to != "" && from != to {
+		errs := field.ErrorList{field.Forbidden(field.NewPath("spec", "forProvider", "bucket"),
+			fmt.Sprintf("the target bucket can't be changed from %q to %q", from, to))}
+		return nil, kerrors.NewInvalid(p.GetObjectKind().GroupVersionKind().GroupKind(), p.GetName(), errs)
+	}
+	return nil, newProblems(validatePolicy(old), validatePolicy(p))
+}
+
+// ValidateDelete allows all Policies to be deleted.
+func (v *Validator[T]) ValidateDelete(_ context.Context, _ T) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validatePolicy validates the policy document of the supplied Policy.
+// References have not necessarily been resolved at admission, so a resource
+// that only references a Bucket is assumed to be in the target bucket, or
+// failing that in a bucket named after the referenced Bucket. Resources are
+// not checked against an unresolved target bucket.
+func validatePolicy(p scope.Policy) error {
+	params := *p.GetForProvider().DeepCopy()
+	target := params.GetTargetBucket()
+	if params.Bucket == nil && params.BucketRef != nil {
+		params.Bucket = ptr.To(params.BucketRef.Name)
+	}
+	for i := range params.Statements {
+		resources := params.Statements[i].Resources
+		for j := range resources {
+			if resources[j].Bucket != nil || resources[j].BucketRef == nil {
+				continue
+			}
+			resources[j].Bucket = ptr.To(resources[j].BucketRef.Name)
+			if target != "" {
+				resources[j].Bucket = ptr.To(target)
+			}
+		}
+	}
+
+	doc, err := policyDocument(params)
+	if err != nil {
+		return err
+	}
+	return bucketpolicy.Validate(doc, target)
+}
+
+// newProblems returns err without the problems old also reported, or nil if
+// it has no others.
+func newProblems(old, err error) error {
+	if err == nil || old == nil {
+		return err
+	}
+	var was, is *bucketpolicy.ValidationError
+	if !errors.As(old, &was) || !errors.As(err, &is) {
+		if old.Error() == err.Error() {
+			return nil
+		}
+		return err
+	}
+
+	var problems []string
+	for _, p := range is.Problems {
+		if !slices.Contains(was.Problems, p) {
+			problems = append(problems, p)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &bucketpolicy.ValidationError{Problems: problems}
+}
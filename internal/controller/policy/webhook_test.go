@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/bucketpolicy"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	type want struct {
+		err     bool
+		invalid bool
+	}
+	tests := map[string]struct {
+		params backblazev1.PolicyParameters
+		want   want
+	}{
+		"AllowBucketNoPrincipal": {
+			params: backblazev1.PolicyParameters{AllowBucket: ptr.To("my-bucket")},
+			want:   want{err: true, invalid: true},
+		},
+		"NothingSet": {
+			params: backblazev1.PolicyParameters{},
+			want:   want{err: true},
+		},
+		"RawPolicyNotJSON": {
+			params: backblazev1.PolicyParameters{RawPolicy: ptr.To("{")},
+			want:   want{err: true},
+		},
+		"RawPolicyOtherBucket": {
+			params: backblazev1.PolicyParameters{
+				Bucket:    ptr.To("my-bucket"),
+				RawPolicy: ptr.To(`{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::other-bucket/*"}]}`),
+			},
+			want: want{err: true, invalid: true},
+		},
+		"UnresolvedBucketRefInTarget": {
+			params: backblazev1.PolicyParameters{
+				Bucket: ptr.To("my-bucket"),
+				Statements: []backblazev1.PolicyStatement{{
+					Principals: []string{"*"},
+					Actions:    []string{"s3:GetObject"},
+					Resources:  []backblazev1.PolicyResource{{BucketRef: &xpv1.Reference{Name: "My_Bucket"}, Prefix: ptr.To("")}},
+				}},
+			},
+		},
+		"UnsupportedAction": {
+			params: backblazev1.PolicyParameters{
+				Statements: []backblazev1.PolicyStatement{{
+					Actions:   []string{"s3:PutBucketPolicy"},
+					Resources: []backblazev1.PolicyResource{{BucketRef: &xpv1.Reference{Name: "my-bucket"}}},
+				}},
+			},
+			want: want{err: true, invalid: true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &backblazev1.Policy{Spec: backblazev1.PolicySpec{ForProvider: tc.params}}
+			err := validatePolicy(p)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("validatePolicy(...): want error %t, got %v", tc.want.err, err)
+			}
+			var invalid *bucketpolicy.ValidationError
+			if got := errors.As(err, &invalid); got != tc.want.invalid {
+				t.Errorf("validatePolicy(...): want *ValidationError %t, got %v", tc.want.invalid, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := map[string]struct {
+		old     backblazev1.PolicyParameters
+		params  backblazev1.PolicyParameters
+		wantErr bool
+	}{
+		"TargetBucketResolved": {
+			old:    backblazev1.PolicyParameters{BucketRef: &xpv1.Reference{Name: "my-bucket"}, DenyInsecureTransport: ptr.To(true)},
+			params: backblazev1.PolicyParameters{Bucket: ptr.To("my-bucket"), BucketRef: &xpv1.Reference{Name: "my-bucket"}, DenyInsecureTransport: ptr.To(true)},
+		},
+		"TargetBucketSet": {
+			old:    backblazev1.PolicyParameters{RawPolicy: ptr.To(`{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`)},
+			params: backblazev1.PolicyParameters{AllowBucket: ptr.To("my-bucket")},
+		},
+		"ExistingProblem": {
+			old: backblazev1.PolicyParameters{Statements: []backblazev1.PolicyStatement{{
+				Actions:   []string{"s3:PutBucketPolicy"},
+				Resources: []backblazev1.PolicyResource{{Bucket: ptr.To("my-bucket")}},
+			}}},
+			params: backblazev1.PolicyParameters{Statements: []backblazev1.PolicyStatement{{
+				Actions:    []string{"s3:PutBucketPolicy"},
+				Principals: []string{"*"},
+				Resources:  []backblazev1.PolicyResource{{Bucket: ptr.To("my-bucket")}},
+			}}},
+		},
+		"NewProblem": {
+			old: backblazev1.PolicyParameters{AllowBucket: ptr.To("my-bucket")},
+			params: backblazev1.PolicyParameters{Statements: []backblazev1.PolicyStatement{{
+				Actions:   []string{"s3:PutBucketPolicy"},
+				Resources: []backblazev1.PolicyResource{{Bucket: ptr.To("my-bucket")}},
+			}}},
+			wantErr: true,
+		},
+		"TargetBucketChanged": {
+			old:     backblazev1.PolicyParameters{AllowBucket: ptr.To("my-bucket")},
+			params:  backblazev1.PolicyParameters{AllowBucket: ptr.To("my-other-bucket")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := &backblazev1.Policy{Spec: backblazev1.PolicySpec{ForProvider: tc.old}}
+			p := &backblazev1.Policy{Spec: backblazev1.PolicySpec{ForProvider: tc.params}}
+			_, err := (&Validator[*backblazev1.Policy]{}).ValidateUpdate(context.Background(), old, p)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateUpdate(...): want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -19,19 +19,31 @@ package user
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
 	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
 	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/features"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+	"github.com/rossigee/provider-backblaze/internal/scope"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,18 +60,92 @@ const (
 	errCreateApplicationKey  = "cannot create application key"
 	errDeleteApplicationKey  = "cannot delete application key"
 	errGetApplicationKey     = "cannot get application key"
+	errImportApplicationKey  = "cannot import application key"
+	errFmtImportedKeyMissing = "cannot import application key %q named by the crossplane.io/external-name annotation: remove the annotation to create a new key"
+	errKeyNotFound           = "application key does not exist and managementPolicies do not allow it to be created"
+	errGetConnectionSecret   = "cannot get connection secret"
 	errWriteSecret           = "cannot write application key secret"
+	errPublishConnection     = "cannot publish connection details"
+	errUnpublishConnection   = "cannot unpublish connection details"
+	errGetBucket             = "cannot get bucket the application key is restricted to"
+	errGetCapabilities       = "cannot get capabilities of the provider's application key"
+	errFmtMissingCapability  = "the provider's application key lacks requested capabilities: %s"
+	errResolveRefs           = "cannot resolve references"
+	errFmtGetRef             = "cannot get referenced %s %q"
+	errFmtRefNotReady        = "referenced %s %q does not have an ID yet"
+	errAddFinalizer          = "cannot add finalizer"
+	errRemoveFinalizer       = "cannot remove finalizer"
+	errUpdateCritical        = "cannot update critical annotations"
+	errReplaceApplicationKey = "cannot replace application key"
+	errKeyImmutable          = "forProvider differs from the existing application key, which can't be changed, and replacementPolicy is Reject"
+	errImportedKeyDiffers    = "forProvider differs from the imported application key, which can't be changed: set replacementPolicy to Replace to replace it"
+	errDeleteRotatedKey      = "cannot delete rotated application key"
+	errSecretUnrecoverable   = "the application key was imported rather than created, and B2 only returns the secret of a key when it is created: no connection details were published for it"
+	errCreateIncomplete      = "cannot determine creation result - remove the " + meta.AnnotationKeyExternalCreatePending + " annotation if it is safe to proceed"
+	errFmtReplaceIncomplete  = "cannot determine whether application key %s was replaced: keys %s have its name but weren't published - delete them, or remove the " + meta.AnnotationKeyExternalCreatePending + " annotation if they aren't this User's"
+
+	// finalizerName blocks deletion of a User until its application key has
+	// been deleted or orphaned.
+	finalizerName = "finalizer.managedresource.crossplane.io"
+
+	// defaultGracePeriod is how long a rotated key is kept if the User
+	// doesn't say otherwise.
+	defaultGracePeriod = time.Hour
+
+	// maxRotationHistory is the number of rotated keys kept in status. Keys
+	// that have not been deleted yet are always kept.
+	maxRotationHistory = 10
+
+	// pollInterval is how often an available User is reconciled.
+	pollInterval = 5 * time.Minute
 )
 
-// SetupUser adds a controller that reconciles User managed resources.
+// Connection detail keys published for a User, in addition to
+// clients.SecretKeyApplicationKeyID and clients.SecretKeyApplicationKey.
+const (
+	ConnectionKeyEndpoint   = "endpoint"
+	ConnectionKeyRegion     = "region"
+	ConnectionKeyBucketName = "bucketName"
+
+	// S3-style aliases of the application key ID and key, for tools that
+	// read AWS credentials.
+	ConnectionKeyAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	ConnectionKeyAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+)
+
+// SetupUser adds controllers that reconcile cluster scoped and namespaced
+// User managed resources.
 func SetupUser(mgr ctrl.Manager, o controller.Options) error {
-	r := &UserReconciler{
-		Client: mgr.GetClient(),
+	for _, s := range []scope.Scope{scope.Cluster, scope.Namespaced} {
+		r := &UserReconciler{
+			Client:      mgr.GetClient(),
+			NewClientFn: clients.NewClient,
+			Scope:       s,
+		}
+		if err := r.SetupWithManager(mgr, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWithManager registers the reconciler with the supplied manager.
+func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager, o controller.Options) error {
+	r.ManagementPoliciesEnabled = o.Features.Enabled(features.EnableAlphaManagementPolicies)
+	if r.Publisher == nil {
+		r.Publisher = managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())
+	}
+	if r.LocalPublisher == nil {
+		r.LocalPublisher = managed.NewAPILocalSecretPublisher(mgr.GetClient(), mgr.GetScheme())
+	}
+	name := r.Scope.ControllerName("user")
+	if r.Recorder == nil {
+		r.Recorder = recorder.New(mgr.GetEventRecorder(name))
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
-		Named("user-controller").
-		For(&backblazev1.User{}).
+		Named(name).
+		For(r.Scope.NewUser()).
 		Watches(&apisv1beta1.ProviderConfig{}, handler.Funcs{}).
 		Complete(r)
 }
@@ -67,6 +153,31 @@ func SetupUser(mgr ctrl.Manager, o controller.Options) error {
 // UserReconciler reconciles a User object
 type UserReconciler struct {
 	Client client.Client
+
+	// NewClientFn creates the Backblaze client used to manage application keys.
+	NewClientFn clients.NewClientFn
+
+	// Publisher publishes the connection details of a cluster scoped User
+	// to the secret named by spec.writeConnectionSecretToRef. It defaults to
+	// a publisher that creates or updates a Kubernetes Secret.
+	Publisher managed.ConnectionPublisher
+
+	// LocalPublisher publishes the connection details of a namespaced User
+	// to the secret named by spec.writeConnectionSecretToRef, in the User's
+	// namespace. It defaults to a publisher that creates or updates a
+	// Kubernetes Secret.
+	LocalPublisher managed.LocalConnectionPublisher
+
+	// ManagementPoliciesEnabled enables support for the
+	// spec.managementPolicies of cluster scoped Users.
+	ManagementPoliciesEnabled bool
+
+	// Recorder records events about the application key, such as its
+	// creation, rotation and deletion.
+	Recorder event.Recorder
+
+	// Scope of the Users reconciled.
+	Scope scope.Scope
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -75,7 +186,7 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req reconcile.Request) (
 	logger := log.FromContext(ctx).WithValues("user", req.NamespacedName)
 
 	// Fetch the User instance
-	user := &backblazev1.User{}
+	user := r.Scope.NewUser()
 	err := r.Client.Get(ctx, req.NamespacedName, user)
 	if err != nil {
 		if client.IgnoreNotFound(err) == nil {
@@ -87,15 +198,35 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 
-	logger.Info("Reconciling user", "keyName", user.Spec.ForProvider.KeyName)
+	logger.Info("Reconciling user", "keyName", user.GetForProvider().KeyName)
 
-	// Check for deletion - in this simple implementation, we let Kubernetes handle deletion
-	if !user.GetDeletionTimestamp().IsZero() {
-		return r.handleDeletion(ctx, user)
+	policy := scope.ManagementPolicies(r.ManagementPoliciesEnabled, user)
+	if err := policy.Validate(); err != nil {
+		logger.Error(err, "Invalid management policies")
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{}, r.Client.Status().Update(ctx, user)
+	}
+
+	if meta.WasDeleted(user) {
+		return r.handleDeletion(ctx, user, policy)
+	}
+
+	if policy.IsPaused() {
+		logger.Info("Reconciliation is paused via the management policies")
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcilePaused", "Reconciliation is paused via the management policies")
+		return reconcile.Result{}, r.Client.Status().Update(ctx, user)
+	}
+
+	if !meta.FinalizerExists(user, finalizerName) {
+		meta.AddFinalizer(user, finalizerName)
+		if err := r.Client.Update(ctx, user); err != nil {
+			logger.Error(err, "Failed to add finalizer")
+			return reconcile.Result{}, errors.Wrap(err, errAddFinalizer)
+		}
 	}
 
 	// Get provider config and create client
-	service, err := r.getBackblazeClient(ctx, user)
+	service, cfg, err := r.getBackblazeClient(ctx, user)
 	if err != nil {
 		logger.Error(err, "Failed to create Backblaze client")
 		r.setCondition(user, xpv1.TypeReady, "False", "ClientError", err.Error())
@@ -107,100 +238,880 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req reconcile.Request) (
 		return reconcile.Result{RequeueAfter: requeueAfter}, r.Client.Status().Update(ctx, user)
 	}
 
+	if err := r.resolveReferences(ctx, user); err != nil {
+		logger.Info("Cannot resolve references, retrying in 10 seconds", "error", err)
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", errors.Wrap(err, errResolveRefs).Error())
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, r.Client.Status().Update(ctx, user)
+	}
+
+	if policy.ShouldOnlyObserve() {
+		return r.observeOnly(ctx, user, service)
+	}
+
 	// Check if application key already exists
-	if user.Status.AtProvider.ApplicationKeyID == "" {
-		// Create application key
-		if err := r.createApplicationKey(ctx, user, service); err != nil {
-			logger.Error(err, "Failed to create application key")
+	if user.GetAtProvider().ApplicationKeyID == "" {
+		if meta.ExternalCreateIncomplete(user) {
+			logger.Info(errCreateIncomplete)
+			r.setCondition(user, xpv1.TypeReady, "False", "CreateError", errCreateIncomplete)
+			return reconcile.Result{}, r.Client.Status().Update(ctx, user)
+		}
+
+		// A key named by the external name is imported rather than
+		// created. That is also how a key is recovered when its creation
+		// was recorded but the status update that followed failed.
+		imported, err := r.importApplicationKey(ctx, user, service)
+		if (err == nil && !imported || isKeyNotFound(err)) && !policy.ShouldCreate() {
+			logger.Info("Application key does not exist and will not be created")
+			r.setCondition(user, xpv1.TypeReady, "False", "NotFound", errKeyNotFound)
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to import application key")
 			r.setCondition(user, xpv1.TypeReady, "False", "CreateError", err.Error())
 			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
 		}
+
+		// Create application key
+		if !imported {
+			if err := r.createApplicationKey(ctx, user, service, cfg.Region); err != nil {
+				logger.Error(err, "Failed to create application key")
+				r.setCondition(user, xpv1.TypeReady, "False", "CreateError", err.Error())
+				return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+			}
+		}
+	}
+
+	if err := r.resumeReplacement(ctx, user, service); err != nil {
+		logger.Error(err, "Failed to resume application key replacement")
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
 	}
 
 	// Application key exists and is ready
 	r.setCondition(user, xpv1.TypeReady, "True", "Available", "Application key is available")
+
+	now := time.Now()
+	if user.GetAtProvider().CreationTime == nil {
+		// Keys created before creation times were recorded are treated as
+		// new when they are first seen.
+		user.GetAtProvider().CreationTime = &metav1.Time{Time: now}
+	}
+
+	reason, err := r.replacementReason(ctx, user, service, now)
+	if err != nil {
+		logger.Error(err, "Failed to observe application key")
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+	}
+	if reason != "" {
+		if err := r.replaceApplicationKey(ctx, user, service, cfg.Region, reason, now); err != nil {
+			logger.Error(err, "Failed to replace application key")
+			r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+		}
+		logger.Info("Replaced application key", "reason", reason, "applicationKeyId", user.GetAtProvider().ApplicationKeyID)
+	}
+
+	if err := r.deleteRotatedKeys(ctx, user, service, now); err != nil {
+		logger.Error(err, "Failed to delete rotated application key")
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+	}
+
 	r.setCondition(user, xpv1.TypeSynced, "True", "ReconcileSuccess", "Successfully reconciled")
 
 	logger.Info("Successfully reconciled user")
-	return reconcile.Result{RequeueAfter: 5 * time.Minute}, r.Client.Status().Update(ctx, user)
+	return reconcile.Result{RequeueAfter: requeueAfter(user, now)}, r.Client.Status().Update(ctx, user)
 }
 
-func (r *UserReconciler) handleDeletion(ctx context.Context, user *backblazev1.User) (reconcile.Result, error) {
+func (r *UserReconciler) handleDeletion(ctx context.Context, user scope.User, policy managed.ManagementPoliciesChecker) (reconcile.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// Delete the associated secret
-	if err := r.deleteSecret(ctx, user); err != nil {
-		logger.Error(err, "Failed to delete application key secret")
-		// Continue with deletion even if secret deletion fails
+	if !meta.FinalizerExists(user, finalizerName) {
+		return reconcile.Result{}, nil
 	}
 
-	// For this implementation, we'll simulate application key deletion
-	// In a real implementation, you would use the Backblaze B2 API
-	// TODO: Implement actual B2 application key deletion
+	keyIDs := ownedKeyIDs(user)
+	if len(keyIDs) > 0 && policy.ShouldDelete() {
+		service, _, err := r.getBackblazeClient(ctx, user)
+		if err != nil {
+			logger.Error(err, "Failed to create Backblaze client")
+			r.setCondition(user, xpv1.TypeReady, "False", "ClientError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+		}
+
+		for _, keyID := range keyIDs {
+			err = service.DeleteApplicationKey(ctx, keyID)
+			if err != nil && !isKeyNotFound(err) {
+				logger.Error(err, "Failed to delete application key")
+				r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotDelete, errors.Wrap(err, errDeleteApplicationKey)))
+				r.setCondition(user, xpv1.TypeReady, "False", "DeleteError", errors.Wrap(err, errDeleteApplicationKey).Error())
+				return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+			}
+			if err == nil {
+				r.Recorder.Event(user, event.Normal(recorder.ReasonDeleted, fmt.Sprintf("Deleted application key %s", keyID)))
+			}
+		}
+
+		// Rotated keys still in their grace period go with the User.
+		if err := r.deleteRotatedKeys(ctx, user, service, time.Time{}); err != nil {
+			logger.Error(err, "Failed to delete rotated application key")
+			r.setCondition(user, xpv1.TypeReady, "False", "DeleteError", err.Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+		}
+	}
+
+	// Observed Users never touch secrets.
+	if !policy.ShouldOnlyObserve() {
+		if err := r.unpublishConnection(ctx, user); err != nil {
+			logger.Error(err, "Failed to unpublish connection details")
+			r.setCondition(user, xpv1.TypeReady, "False", "DeleteError", errors.Wrap(err, errUnpublishConnection).Error())
+			return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+		}
+
+		// Delete the associated secret
+		if err := r.deleteSecret(ctx, user); err != nil {
+			logger.Error(err, "Failed to delete application key secret")
+			// Continue with deletion even if secret deletion fails
+		}
+	}
+
+	meta.RemoveFinalizer(user, finalizerName)
+	if err := r.Client.Update(ctx, user); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errRemoveFinalizer)
+	}
 
 	logger.Info("User deletion handled")
 	return reconcile.Result{}, nil
 }
 
-func (r *UserReconciler) createApplicationKey(ctx context.Context, user *backblazev1.User, service *clients.BackblazeClient) error {
-	// For this implementation, we'll simulate application key creation
-	// In a real implementation, you would use the Backblaze B2 API
-	// TODO: Implement actual B2 application key creation
+func (r *UserReconciler) createApplicationKey(ctx context.Context, user scope.User, service clients.Client, region string) error {
+	params := *user.GetForProvider()
+	annotations := managed.NewRetryingCriticalAnnotationUpdater(r.Client)
+
+	// Record that a create is in flight before calling B2. Application key
+	// names needn't be unique, so this rather than a lookup by name is what
+	// stops a reconcile working from a stale cache from creating a duplicate
+	// key.
+	meta.SetExternalCreatePending(user, time.Now())
+	if err := r.Client.Update(ctx, user); err != nil {
+		return errors.Wrap(err, errUpdateCritical)
+	}
 
-	// Generate a simulated application key ID and key
-	applicationKeyID := fmt.Sprintf("K005%012d", user.GetGeneration())
-	applicationKey := fmt.Sprintf("K005%024d", user.GetGeneration()*1000)
+	key, err := newApplicationKey(ctx, service, params)
+	if err != nil {
+		err = errors.Wrap(err, errCreateApplicationKey)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotCreate, err))
+		meta.SetExternalCreateFailed(user, time.Now())
+		_ = annotations.UpdateCriticalAnnotations(ctx, user)
+		return err
+	}
+
+	// B2 only returns the secret part of a key when it is created, so a key
+	// whose credentials can't be published is useless. Delete it and let the
+	// next reconcile create another.
+	if err := r.publishConnection(ctx, user, service, region, key); err != nil {
+		_ = service.DeleteApplicationKey(ctx, key.ApplicationKeyID)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotCreate, err))
+		meta.SetExternalCreateFailed(user, time.Now())
+		_ = annotations.UpdateCriticalAnnotations(ctx, user)
+		return err
+	}
+	r.Recorder.Event(user, event.Normal(recorder.ReasonCreated, fmt.Sprintf("Created application key %s", key.ApplicationKeyID)))
+
+	meta.SetExternalName(user, key.ApplicationKeyID)
+	meta.SetExternalCreateSucceeded(user, time.Now())
+	if err := annotations.UpdateCriticalAnnotations(ctx, user); err != nil {
+		return errors.Wrap(err, errUpdateCritical)
+	}
 
 	// Update the resource status
-	user.Status.AtProvider.ApplicationKeyID = applicationKeyID
-	user.Status.AtProvider.AccountID = "simulated-account-id"
-	user.Status.AtProvider.Capabilities = user.Spec.ForProvider.Capabilities
-	if user.Spec.ForProvider.BucketID != nil {
-		user.Status.AtProvider.BucketID = user.Spec.ForProvider.BucketID
+	setKeyObservation(user, key)
+	user.GetAtProvider().CreationTime = &metav1.Time{Time: time.Now()}
+	return nil
+}
+
+// importApplicationKey adopts the existing application key named by the
+// external name of the supplied User, and returns false if it has none. A
+// key that is named but doesn't exist is an error rather than a reason to
+// create a new key.
+func (r *UserReconciler) importApplicationKey(ctx context.Context, user scope.User, service clients.Client) (bool, error) {
+	keyID := meta.GetExternalName(user)
+	if keyID == "" {
+		return false, nil
+	}
+
+	key, err := service.GetApplicationKey(ctx, keyID)
+	if isKeyNotFound(err) {
+		return false, errors.Wrapf(err, errFmtImportedKeyMissing, keyID)
+	}
+	if err != nil {
+		err = errors.Wrap(err, errImportApplicationKey)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotObserve, err))
+		return false, err
+	}
+
+	setKeyObservation(user, key)
+	return true, r.checkSecretPublished(ctx, user)
+}
+
+// observeOnly observes the application key of the supplied User, which is
+// found by its external name or else by its keyName, without changing it or
+// publishing its connection details.
+func (r *UserReconciler) observeOnly(ctx context.Context, user scope.User, service clients.Client) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var key *clients.B2CreateKeyResponse
+	var err error
+	if keyID := meta.GetExternalName(user); keyID != "" {
+		key, err = service.GetApplicationKey(ctx, keyID)
+	} else {
+		key, err = service.GetApplicationKeyByName(ctx, user.GetForProvider().KeyName)
+	}
+	if isKeyNotFound(err) {
+		logger.Info("Observed application key does not exist")
+		r.setCondition(user, xpv1.TypeReady, "False", "NotFound", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to observe application key")
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotObserve, errors.Wrap(err, errGetApplicationKey)))
+		r.setCondition(user, xpv1.TypeReady, "False", "CheckError", errors.Wrap(err, errGetApplicationKey).Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+	}
+
+	// Record the key ID, so that a key found by name is found by ID from now
+	// on.
+	if meta.GetExternalName(user) == "" {
+		meta.SetExternalName(user, key.ApplicationKeyID)
+		if err := r.updateMeta(ctx, user, func(ctx context.Context, o client.Object) error { return r.Client.Update(ctx, o) }); err != nil {
+			logger.Error(err, "Failed to record external name")
+			return reconcile.Result{}, errors.Wrap(err, errUpdateCritical)
+		}
+	}
+
+	setKeyObservation(user, key)
+	if err := r.checkSecretPublished(ctx, user); err != nil {
+		logger.Error(err, "Failed to check connection secret")
+		r.setCondition(user, xpv1.TypeSynced, "False", "ReconcileError", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, r.Client.Status().Update(ctx, user)
+	}
+
+	r.setCondition(user, xpv1.TypeReady, "True", "Available", "Application key is available")
+	r.setCondition(user, xpv1.TypeSynced, "True", "ReconcileSuccess", "Successfully reconciled")
+	return reconcile.Result{RequeueAfter: pollInterval}, r.Client.Status().Update(ctx, user)
+}
+
+// checkSecretPublished sets the SecretUnrecoverable condition of the supplied
+// User unless one of its connection secrets holds its current application
+// key, as it does when the key was created by this or an earlier User.
+func (r *UserReconciler) checkSecretPublished(ctx context.Context, user scope.User) error {
+	published, err := r.secretHolds(ctx, user, user.GetAtProvider().ApplicationKeyID)
+	if err != nil {
+		return err
+	}
+	if published {
+		r.clearSecretUnrecoverable(user)
+		return nil
+	}
+
+	r.setCondition(user, backblazev1.TypeSecretUnrecoverable, "True", "KeyImported", errSecretUnrecoverable)
+	return nil
+}
+
+// secretHolds returns true if one of the connection secrets of the supplied
+// User holds the supplied application key.
+func (r *UserReconciler) secretHolds(ctx context.Context, user scope.User, keyID string) (bool, error) {
+	for _, ref := range []*xpv1.SecretReference{connectionSecretRef(user), user.GetForProvider().WriteSecretToRef} {
+		if ref == nil {
+			continue
+		}
+		s := &corev1.Secret{}
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, s)
+		if client.IgnoreNotFound(err) != nil {
+			return false, errors.Wrap(err, errGetConnectionSecret)
+		}
+		if string(s.Data[clients.SecretKeyApplicationKeyID]) == keyID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clearSecretUnrecoverable clears the SecretUnrecoverable condition of the
+// supplied User, if it is set.
+func (r *UserReconciler) clearSecretUnrecoverable(user scope.User) {
+	if isImported(user) {
+		r.setCondition(user, backblazev1.TypeSecretUnrecoverable, "False", "SecretPublished", "The secret of the application key was published")
+	}
+}
+
+// isImported returns true if the application key of the supplied User was
+// imported, and its secret was never published by the provider.
+func isImported(user scope.User) bool {
+	return user.GetCondition(backblazev1.TypeSecretUnrecoverable).Status == corev1.ConditionTrue
+}
+
+// replacementReason observes the application key of the supplied User and
+// returns why it must be replaced, or an empty string if it needn't be.
+func (r *UserReconciler) replacementReason(ctx context.Context, user scope.User, service clients.Client, now time.Time) (string, error) {
+	key, err := service.GetApplicationKey(ctx, user.GetAtProvider().ApplicationKeyID)
+	if isKeyNotFound(err) {
+		return backblazev1.KeyReplacedNotFound, nil
+	}
+	if err != nil {
+		err = errors.Wrap(err, errGetApplicationKey)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotObserve, err))
+		return "", err
+	}
+	setKeyObservation(user, key)
+
+	if !isKeyUpToDate(*user.GetForProvider(), key) {
+		// Replacing an imported key deletes it, so that is only done when
+		// asked for explicitly.
+		switch policy := user.GetForProvider().ReplacementPolicy; {
+		case policy == backblazev1.ReplacementPolicyReject:
+			err := errors.New(errKeyImmutable)
+			r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotUpdate, err))
+			return "", err
+		case policy == "" && isImported(user):
+			err := errors.New(errImportedKeyDiffers)
+			r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotUpdate, err))
+			return "", err
+		}
+		return backblazev1.KeyReplacedSpecChange, nil
+	}
+
+	if t := nextRotation(user); !t.IsZero() && !now.Before(t) {
+		return backblazev1.KeyReplacedRotation, nil
+	}
+	return "", nil
+}
+
+// resolveReferences sets the IDs of the Buckets referenced by the User, and
+// persists them if they changed. References are resolved in the scope, and
+// namespace, of the User.
+func (r *UserReconciler) resolveReferences(ctx context.Context, user scope.User) error {
+	refs := user.GetForProvider().BucketRefs
+	if len(refs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		bucket := r.Scope.NewBucket()
+		if err := r.Client.Get(ctx, scope.ObjectKey(user, ref.Name), bucket); err != nil {
+			return errors.Wrapf(err, errFmtGetRef, backblazev1.BucketKind, ref.Name)
+		}
+		id := bucket.GetAtProvider().BucketID
+		if id == "" {
+			return errors.Errorf(errFmtRefNotReady, backblazev1.BucketKind, ref.Name)
+		}
+		ids = append(ids, id)
+	}
+
+	if cmp.Equal(user.GetForProvider().BucketIDs, ids, cmpopts.EquateEmpty()) {
+		return nil
+	}
+	user.GetForProvider().BucketIDs = ids
+	return r.Client.Update(ctx, user)
+}
+
+// isKeyUpToDate returns true if the supplied application key was created with
+// the supplied parameters. B2 application keys can't be changed once created.
+func isKeyUpToDate(params backblazev1.UserParameters, key *clients.B2CreateKeyResponse) bool {
+	return params.KeyName == key.KeyName &&
+		ptr.Deref(params.NamePrefix, "") == key.NamePrefix &&
+		cmp.Equal(params.GetBucketIDs(), key.BucketIDs, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(a, b string) bool { return a < b })) &&
+		cmp.Equal(params.GetCapabilities(), key.Capabilities, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(a, b string) bool { return a < b }))
+}
+
+// replaceApplicationKey replaces the application key of the supplied User for
+// the supplied reason. The Secret is switched to the new key and the old key
+// is recorded in the rotation history, to be deleted once its grace period has
+// passed.
+func (r *UserReconciler) replaceApplicationKey(ctx context.Context, user scope.User, service clients.Client, region, reason string, now time.Time) error {
+	oldKeyID := user.GetAtProvider().ApplicationKeyID
+
+	// Record that a replacement of the key is in flight. Unlike the critical
+	// annotation updater this doesn't retry on conflict, so a reconcile
+	// working from a stale cache can't replace a key that has already been
+	// replaced. The annotations are kept until the status records the
+	// replacement, so one whose status update is lost is resumed rather than
+	// repeated.
+	meta.SetExternalCreatePending(user, now)
+	meta.AddAnnotations(user, map[string]string{
+		backblazev1.AnnotationReplacedKeyID:     oldKeyID,
+		backblazev1.AnnotationReplacementReason: reason,
+	})
+	if err := r.updateMeta(ctx, user, func(ctx context.Context, o client.Object) error { return r.Client.Update(ctx, o) }); err != nil {
+		return errors.Wrap(err, errUpdateCritical)
+	}
+
+	annotations := managed.NewRetryingCriticalAnnotationUpdater(r.Client)
+	key, err := newApplicationKey(ctx, service, *user.GetForProvider())
+	if err != nil {
+		err = errors.Wrap(err, errReplaceApplicationKey)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotUpdate, err))
+		r.abandonReplacement(ctx, user)
+		return err
+	}
+
+	// Both credentials are replaced in a single write, so readers of the
+	// Secret never see a mismatched key ID and key.
+	if err := r.publishConnection(ctx, user, service, region, key); err != nil {
+		// Nothing can use the new key yet, so don't leak it.
+		_ = service.DeleteApplicationKey(ctx, key.ApplicationKeyID)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotUpdate, err))
+		r.abandonReplacement(ctx, user)
+		return err
+	}
+
+	if reason == backblazev1.KeyReplacedRotation {
+		r.Recorder.Event(user, event.Normal(recorder.ReasonRotatedKey, fmt.Sprintf("Rotated application key %s, replacing it with %s", oldKeyID, key.ApplicationKeyID)))
+	} else {
+		r.Recorder.Event(user, event.Normal(recorder.ReasonUpdated, fmt.Sprintf("Replaced application key %s with %s (%s)", oldKeyID, key.ApplicationKeyID, reason)))
+	}
+
+	meta.SetExternalName(user, key.ApplicationKeyID)
+	meta.SetExternalCreateSucceeded(user, time.Now())
+	err = r.updateMeta(ctx, user, annotations.UpdateCriticalAnnotations)
+
+	// The Secret already holds the new key, so record it even if the
+	// annotation couldn't be updated.
+	recordReplacement(user, oldKeyID, reason, now, key)
+	r.clearSecretUnrecoverable(user)
+
+	return errors.Wrap(err, errUpdateCritical)
+}
+
+// abandonReplacement records that the in-flight replacement of the
+// application key of the supplied User failed without creating a key.
+func (r *UserReconciler) abandonReplacement(ctx context.Context, user scope.User) {
+	meta.SetExternalCreateFailed(user, time.Now())
+	meta.RemoveAnnotations(user, backblazev1.AnnotationReplacedKeyID, backblazev1.AnnotationReplacementReason)
+	_ = r.updateMeta(ctx, user, func(ctx context.Context, o client.Object) error { return r.Client.Update(ctx, o) })
+}
+
+// resumeReplacement completes a replacement of the application key of the
+// supplied User that its annotations record but its status doesn't, for
+// example because the status update that followed it failed. Once the status
+// records the replacement the annotations are removed.
+func (r *UserReconciler) resumeReplacement(ctx context.Context, user scope.User, service clients.Client) error {
+	a := user.GetAnnotations()
+	oldKeyID, reason := a[backblazev1.AnnotationReplacedKeyID], a[backblazev1.AnnotationReplacementReason]
+	if oldKeyID == "" {
+		return nil
+	}
+	at := meta.GetExternalCreatePending(user)
+
+	if meta.ExternalCreateIncomplete(user) {
+		// The new key may have been created without its ID being recorded.
+		// Key names needn't be unique, so it is only adopted if its
+		// credentials were published.
+		key, err := r.findReplacementKey(ctx, user, service, oldKeyID)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			r.abandonReplacement(ctx, user)
+			return nil
+		}
+		meta.SetExternalName(user, key.ApplicationKeyID)
+		meta.SetExternalCreateSucceeded(user, time.Now())
+		if err := r.updateMeta(ctx, user, managed.NewRetryingCriticalAnnotationUpdater(r.Client).UpdateCriticalAnnotations); err != nil {
+			return errors.Wrap(err, errUpdateCritical)
+		}
+		recordReplacement(user, oldKeyID, reason, at, key)
+		r.clearSecretUnrecoverable(user)
+		return nil
+	}
+
+	newKeyID := meta.GetExternalName(user)
+	if newKeyID != "" && newKeyID != oldKeyID && (user.GetAtProvider().ApplicationKeyID != newKeyID || !wasReplaced(user, oldKeyID)) {
+		key, err := service.GetApplicationKey(ctx, newKeyID)
+		if isKeyNotFound(err) {
+			// The key will be replaced once the replacement is recorded.
+			key, err = &clients.B2CreateKeyResponse{ApplicationKeyID: newKeyID}, nil
+		}
+		if err != nil {
+			err = errors.Wrap(err, errGetApplicationKey)
+			r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotObserve, err))
+			return err
+		}
+		recordReplacement(user, oldKeyID, reason, at, key)
+		r.clearSecretUnrecoverable(user)
+		return nil
+	}
+
+	// The status records the replacement, or it failed before creating a
+	// key.
+	meta.RemoveAnnotations(user, backblazev1.AnnotationReplacedKeyID, backblazev1.AnnotationReplacementReason)
+	return errors.Wrap(r.updateMeta(ctx, user, func(ctx context.Context, o client.Object) error { return r.Client.Update(ctx, o) }), errUpdateCritical)
+}
+
+// findReplacementKey returns the key created to replace the supplied key of
+// the supplied User, or nil if none was. A key that has the User's key name
+// but whose credentials weren't published can't be told apart from a key
+// created by someone else, so its replacement can't be resumed.
+func (r *UserReconciler) findReplacementKey(ctx context.Context, user scope.User, service clients.Client, oldKeyID string) (*clients.B2CreateKeyResponse, error) {
+	keys, err := service.GetApplicationKeysByName(ctx, user.GetForProvider().KeyName)
+	if err != nil {
+		err = errors.Wrap(err, errGetApplicationKey)
+		r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotObserve, err))
+		return nil, err
+	}
+
+	var unknown []string
+	for i := range keys {
+		id := keys[i].ApplicationKeyID
+		if id == oldKeyID || wasReplaced(user, id) {
+			continue
+		}
+		published, err := r.secretHolds(ctx, user, id)
+		if err != nil {
+			return nil, err
+		}
+		if published {
+			return &keys[i], nil
+		}
+		unknown = append(unknown, id)
+	}
+	if len(unknown) > 0 {
+		return nil, errors.Errorf(errFmtReplaceIncomplete, oldKeyID, strings.Join(unknown, ", "))
+	}
+	return nil, nil
+}
+
+// recordReplacement records that the supplied old application key of the
+// supplied User was replaced by the supplied key at the supplied time, unless
+// its status already does.
+func recordReplacement(user scope.User, oldKeyID, reason string, at time.Time, key *clients.B2CreateKeyResponse) {
+	if !wasReplaced(user, oldKeyID) {
+		grace := defaultGracePeriod
+		if rot := user.GetForProvider().Rotation; rot != nil && rot.GracePeriod != nil {
+			grace = rot.GracePeriod.Duration
+		}
+		old := backblazev1.RotatedKey{
+			ApplicationKeyID: oldKeyID,
+			Reason:           reason,
+			RotationTime:     metav1.Time{Time: at},
+			DeletionTime:     metav1.Time{Time: at.Add(grace)},
+		}
+		if reason == backblazev1.KeyReplacedNotFound {
+			old.DeletionTime = old.RotationTime
+			old.Deleted = true
+		}
+		user.GetAtProvider().RotationHistory = append(user.GetAtProvider().RotationHistory, old)
+	}
+	setKeyObservation(user, key)
+	user.GetAtProvider().CreationTime = &metav1.Time{Time: at}
+}
+
+// wasReplaced returns true if the rotation history of the supplied User
+// records the supplied application key.
+func wasReplaced(user scope.User, keyID string) bool {
+	for _, k := range user.GetAtProvider().RotationHistory {
+		if k.ApplicationKeyID == keyID {
+			return true
+		}
 	}
-	if user.Spec.ForProvider.NamePrefix != nil {
-		user.Status.AtProvider.NamePrefix = user.Spec.ForProvider.NamePrefix
+	return false
+}
+
+// ownedKeyIDs returns the IDs of the application keys of the supplied User
+// that aren't in its rotation history: its current key, and the keys of a
+// replacement its status doesn't record yet.
+func ownedKeyIDs(user scope.User) []string {
+	var ids []string
+	candidates := []string{user.GetAtProvider().ApplicationKeyID}
+	if oldKeyID := user.GetAnnotations()[backblazev1.AnnotationReplacedKeyID]; oldKeyID != "" {
+		candidates = append(candidates, oldKeyID, meta.GetExternalName(user))
 	}
-	if user.Spec.ForProvider.ValidDurationInSeconds != nil {
-		user.Status.AtProvider.ExpirationTimestamp = user.Spec.ForProvider.ValidDurationInSeconds
+	for _, id := range candidates {
+		if id != "" && !slices.Contains(ids, id) && !wasReplaced(user, id) {
+			ids = append(ids, id)
+		}
 	}
+	return ids
+}
 
-	// Create the secret with the application key credentials
-	return r.writeSecret(ctx, user, applicationKeyID, applicationKey)
+// updateMeta updates the supplied User using the supplied function, keeping
+// the status that has been observed so far rather than the one returned by
+// the API server.
+func (r *UserReconciler) updateMeta(ctx context.Context, user scope.User, update func(context.Context, client.Object) error) error {
+	updated := user.DeepCopyObject().(scope.User)
+	if err := update(ctx, updated); err != nil {
+		return err
+	}
+	user.SetAnnotations(updated.GetAnnotations())
+	user.SetResourceVersion(updated.GetResourceVersion())
+	return nil
 }
 
-func (r *UserReconciler) getBackblazeClient(ctx context.Context, user *backblazev1.User) (*clients.BackblazeClient, error) {
-	// Determine ProviderConfig name - use "default" if not specified
-	providerConfigName := "default"
-	if user.GetProviderConfigReference() != nil {
-		providerConfigName = user.GetProviderConfigReference().Name
+// deleteRotatedKeys deletes the rotated keys of the supplied User whose grace
+// period has passed by the supplied time. A zero time deletes all of them.
+func (r *UserReconciler) deleteRotatedKeys(ctx context.Context, user scope.User, service clients.Client, now time.Time) error {
+	history := user.GetAtProvider().RotationHistory
+	for i := range history {
+		if history[i].Deleted || (!now.IsZero() && now.Before(history[i].DeletionTime.Time)) {
+			continue
+		}
+		err := service.DeleteApplicationKey(ctx, history[i].ApplicationKeyID)
+		if err != nil && !isKeyNotFound(err) {
+			err = errors.Wrap(err, errDeleteRotatedKey)
+			r.Recorder.Event(user, recorder.Warning(recorder.ReasonCannotDelete, err))
+			return err
+		}
+		if err == nil {
+			r.Recorder.Event(user, event.Normal(recorder.ReasonDeleted, fmt.Sprintf("Deleted rotated application key %s", history[i].ApplicationKeyID)))
+		}
+		history[i].Deleted = true
+	}
+	user.GetAtProvider().RotationHistory = pruneRotationHistory(history)
+	return nil
+}
+
+// pruneRotationHistory drops the oldest deleted keys from the supplied
+// history until it is no longer than maxRotationHistory.
+func pruneRotationHistory(history []backblazev1.RotatedKey) []backblazev1.RotatedKey {
+	excess := len(history) - maxRotationHistory
+	if excess <= 0 {
+		return history
 	}
+	out := make([]backblazev1.RotatedKey, 0, maxRotationHistory)
+	for _, k := range history {
+		if k.Deleted && excess > 0 {
+			excess--
+			continue
+		}
+		out = append(out, k)
+	}
+	return out
+}
 
-	pc := &apisv1beta1.ProviderConfig{}
-	// ProviderConfigs are namespaced resources - look in the same namespace as the provider
-	key := client.ObjectKey{Name: providerConfigName, Namespace: "crossplane-system"}
-	if err := r.Client.Get(ctx, key, pc); err != nil {
+// nextRotation returns when the current application key of the supplied User
+// is due to be rotated, or the zero time if it isn't rotated.
+func nextRotation(user scope.User) time.Time {
+	rot := user.GetForProvider().Rotation
+	if rot == nil {
+		return time.Time{}
+	}
+
+	obs := user.GetAtProvider()
+	var next time.Time
+	if rot.RotateBefore != nil && obs.ExpirationTimestamp != nil {
+		next = time.UnixMilli(*obs.ExpirationTimestamp).Add(-rot.RotateBefore.Duration)
+	}
+	if rot.RotationInterval != nil && obs.CreationTime != nil {
+		if t := obs.CreationTime.Add(rot.RotationInterval.Duration); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+// requeueAfter returns how long to wait before the supplied User is next
+// reconciled: the poll interval, or sooner if a rotation or the deletion of a
+// rotated key is due before then.
+func requeueAfter(user scope.User, now time.Time) time.Duration {
+	next := now.Add(pollInterval)
+	if t := nextRotation(user); !t.IsZero() && t.Before(next) {
+		next = t
+	}
+	for _, k := range user.GetAtProvider().RotationHistory {
+		if !k.Deleted && k.DeletionTime.Before(&metav1.Time{Time: next}) {
+			next = k.DeletionTime.Time
+		}
+	}
+	if d := next.Sub(now); d > time.Second {
+		return d
+	}
+	return time.Second
+}
+
+// newApplicationKey creates an application key with the supplied parameters.
+// B2 only lets a key create keys with capabilities it holds itself, so those
+// of the provider's key are checked first.
+func newApplicationKey(ctx context.Context, service clients.Client, params backblazev1.UserParameters) (*clients.B2CreateKeyResponse, error) {
+	capabilities := params.GetCapabilities()
+	held, err := service.AuthorizedCapabilities(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCapabilities)
+	}
+	if missing := missingCapabilities(held, capabilities); len(missing) > 0 {
+		return nil, errors.Errorf(errFmtMissingCapability, strings.Join(missing, ", "))
+	}
+
+	var validDuration *int
+	if params.ValidDurationInSeconds != nil {
+		d := int(*params.ValidDurationInSeconds)
+		validDuration = &d
+	}
+
+	return service.CreateApplicationKey(ctx, params.KeyName, capabilities,
+		params.GetBucketIDs(), ptr.Deref(params.NamePrefix, ""), validDuration)
+}
+
+// missingCapabilities returns the wanted capabilities that aren't held. Held
+// capabilities that weren't reported are assumed to be sufficient.
+func missingCapabilities(held, wanted []string) []string {
+	if len(held) == 0 {
+		return nil
+	}
+
+	has := make(map[string]bool, len(held))
+	for _, c := range held {
+		has[c] = true
+	}
+	var missing []string
+	for _, c := range wanted {
+		if !has[c] {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// setKeyObservation records the supplied application key as the current key
+// of the User.
+func setKeyObservation(user scope.User, key *clients.B2CreateKeyResponse) {
+	user.GetAtProvider().ApplicationKeyID = key.ApplicationKeyID
+	user.GetAtProvider().AccountID = key.AccountID
+	user.GetAtProvider().Capabilities = key.Capabilities
+	user.GetAtProvider().BucketIDs = key.BucketIDs
+	user.GetAtProvider().BucketID = nil
+	if len(key.BucketIDs) == 1 {
+		user.GetAtProvider().BucketID = ptr.To(key.BucketIDs[0])
+	}
+	user.GetAtProvider().NamePrefix = nil
+	if key.NamePrefix != "" {
+		user.GetAtProvider().NamePrefix = ptr.To(key.NamePrefix)
+	}
+	user.GetAtProvider().ExpirationTimestamp = key.ExpirationTimestamp
+}
+
+// isKeyNotFound returns true if err indicates the application key is already
+// gone.
+func isKeyNotFound(err error) bool {
+	return errors.Is(err, clients.ErrApplicationKeyNotFound)
+}
+
+func (r *UserReconciler) getBackblazeClient(ctx context.Context, user scope.User) (clients.Client, *clients.Config, error) {
+	pc, err := scope.GetProviderConfig(ctx, r.Client, user)
+	if err != nil {
 		// Check if this is a "not found" error that could be due to cache sync timing
 		if client.IgnoreNotFound(err) == nil {
 			// ProviderConfig not found - this could be a cache sync issue
 			// Return a retriable error to allow reconciliation to retry
-			return nil, errors.Wrap(err, errGetProviderConfig)
+			return nil, nil, errors.Wrap(err, errGetProviderConfig)
 		}
 		// Other errors (permission, etc.) - return immediately
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
 	cfg, err := clients.GetProviderConfig(ctx, r.Client, pc)
 	if err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
-	return clients.NewBackblazeClient(*cfg)
+	if cfg.Region == "" {
+		cfg.Region = clients.DefaultRegion
+	}
+
+	service, err := r.NewClientFn(*cfg)
+	return service, cfg, err
 }
 
-// writeSecret creates or updates the secret containing the application key credentials
-func (r *UserReconciler) writeSecret(ctx context.Context, user *backblazev1.User, applicationKeyID, applicationKey string) error {
-	secretRef := user.Spec.ForProvider.WriteSecretToRef
+// publishConnection publishes the credentials of the supplied application key
+// to the connection secret of the User, and to the deprecated
+// forProvider.writeSecretToRef secret if one is set.
+func (r *UserReconciler) publishConnection(ctx context.Context, user scope.User, service clients.Client, region string, key *clients.B2CreateKeyResponse) error {
+	cd, err := connectionDetails(ctx, service, region, key)
+	if err != nil {
+		return err
+	}
+
+	if err := r.publish(ctx, user, cd); err != nil {
+		return errors.Wrap(err, errPublishConnection)
+	}
+
+	if user.GetForProvider().WriteSecretToRef == nil {
+		return nil
+	}
+	return errors.Wrap(r.writeSecret(ctx, user, cd), errWriteSecret)
+}
+
+// publish publishes the supplied connection details to the connection secret
+// of the supplied User, using the publisher for its scope.
+func (r *UserReconciler) publish(ctx context.Context, user scope.User, cd managed.ConnectionDetails) error {
+	var err error
+	switch u := user.(type) {
+	case resource.LocalConnectionSecretOwner:
+		_, err = r.LocalPublisher.PublishConnection(ctx, u, cd)
+	case resource.ConnectionSecretOwner:
+		_, err = r.Publisher.PublishConnection(ctx, u, cd)
+	}
+	return err
+}
+
+// unpublishConnection unpublishes the connection details of the supplied
+// User, using the publisher for its scope.
+func (r *UserReconciler) unpublishConnection(ctx context.Context, user scope.User) error {
+	switch u := user.(type) {
+	case resource.LocalConnectionSecretOwner:
+		return r.LocalPublisher.UnpublishConnection(ctx, u, nil)
+	case resource.ConnectionSecretOwner:
+		return r.Publisher.UnpublishConnection(ctx, u, nil)
+	}
+	return nil
+}
+
+// connectionSecretRef returns the connection secret of the supplied User, or
+// nil if it has none. The connection secret of a namespaced User is always in
+// its own namespace.
+func connectionSecretRef(user scope.User) *xpv1.SecretReference {
+	switch u := user.(type) {
+	case resource.LocalConnectionSecretWriterTo:
+		if ref := u.GetWriteConnectionSecretToReference(); ref != nil {
+			return &xpv1.SecretReference{Name: ref.Name, Namespace: user.GetNamespace()}
+		}
+	case resource.ConnectionSecretWriterTo:
+		return u.GetWriteConnectionSecretToReference()
+	}
+	return nil
+}
+
+// connectionDetails returns the connection details of the supplied
+// application key, created in the supplied region.
+func connectionDetails(ctx context.Context, service clients.Client, region string, key *clients.B2CreateKeyResponse) (managed.ConnectionDetails, error) {
+	cd := managed.ConnectionDetails{
+		clients.SecretKeyApplicationKeyID: []byte(key.ApplicationKeyID),
+		clients.SecretKeyApplicationKey:   []byte(key.ApplicationKey),
+		ConnectionKeyEndpoint:             []byte(fmt.Sprintf(clients.DefaultEndpointFormat, region)),
+		ConnectionKeyRegion:               []byte(region),
+		ConnectionKeyAWSAccessKeyID:       []byte(key.ApplicationKeyID),
+		ConnectionKeyAWSSecretAccessKey:   []byte(key.ApplicationKey),
+	}
+
+	// A bucket name is only meaningful for a key restricted to one bucket.
+	if len(key.BucketIDs) != 1 {
+		return cd, nil
+	}
+	bucket, err := service.GetBucketByID(ctx, key.BucketIDs[0])
+	if err != nil {
+		return nil, errors.Wrap(err, errGetBucket)
+	}
+	if bucket != nil {
+		cd[ConnectionKeyBucketName] = []byte(bucket.BucketName)
+	}
+	return cd, nil
+}
+
+// writeSecret creates or updates the secret named by the deprecated
+// forProvider.writeSecretToRef.
+func (r *UserReconciler) writeSecret(ctx context.Context, user scope.User, cd managed.ConnectionDetails) error {
+	secretRef := user.GetForProvider().WriteSecretToRef
 
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -208,18 +1119,30 @@ func (r *UserReconciler) writeSecret(ctx context.Context, user *backblazev1.User
 			Namespace: secretRef.Namespace,
 		},
 		Type: corev1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"applicationKeyId": []byte(applicationKeyID),
-			"applicationKey":   []byte(applicationKey),
-		},
+		Data: cd,
 	}
 
-	return r.Client.Create(ctx, secret)
+	err := r.Client.Create(ctx, secret)
+	if !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil {
+		return err
+	}
+	existing.Data = secret.Data
+	return r.Client.Update(ctx, existing)
 }
 
-// deleteSecret removes the secret containing the application key credentials
-func (r *UserReconciler) deleteSecret(ctx context.Context, user *backblazev1.User) error {
-	secretRef := user.Spec.ForProvider.WriteSecretToRef
+// deleteSecret removes the secret named by the deprecated
+// forProvider.writeSecretToRef, if any. The connection secret is owned by the
+// User and is garbage collected with it.
+func (r *UserReconciler) deleteSecret(ctx context.Context, user scope.User) error {
+	secretRef := user.GetForProvider().WriteSecretToRef
+	if secretRef == nil {
+		return nil
+	}
 
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -231,7 +1154,7 @@ func (r *UserReconciler) deleteSecret(ctx context.Context, user *backblazev1.Use
 	return client.IgnoreNotFound(r.Client.Delete(ctx, secret))
 }
 
-func (r *UserReconciler) setCondition(user *backblazev1.User, conditionType xpv1.ConditionType, status, reason, message string) {
+func (r *UserReconciler) setCondition(user scope.User, conditionType xpv1.ConditionType, status, reason, message string) {
 	user.SetConditions(xpv1.Condition{
 		Type:               conditionType,
 		Status:             corev1.ConditionStatus(status),
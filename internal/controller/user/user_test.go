@@ -17,11 +17,30 @@ limitations under the License.
 package user
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 
+	"github.com/rossigee/provider-backblaze/apis"
 	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestUserGetKeyName(t *testing.T) {
@@ -53,8 +72,8 @@ func TestCreateApplicationKey(t *testing.T) {
 		Spec: backblazev1.UserSpec{
 			ForProvider: backblazev1.UserParameters{
 				KeyName:      "test-key",
-				Capabilities: []string{"listBuckets", "readFiles"},
-				WriteSecretToRef: xpv1.SecretReference{
+				Capabilities: []backblazev1.Capability{"listBuckets", "readFiles"},
+				WriteSecretToRef: &xpv1.SecretReference{
 					Name:      "test-secret",
 					Namespace: "default",
 				},
@@ -77,3 +96,548 @@ func TestCreateApplicationKey(t *testing.T) {
 		t.Error("Secret reference not set correctly")
 	}
 }
+
+func TestNextRotation(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := created.Add(30 * 24 * time.Hour)
+
+	tests := map[string]struct {
+		rotation *backblazev1.KeyRotation
+		expires  *int64
+		want     time.Time
+	}{
+		"NoRotation": {
+			expires: ptr.To(expires.UnixMilli()),
+		},
+		"RotateBefore": {
+			rotation: &backblazev1.KeyRotation{RotateBefore: &metav1.Duration{Duration: 24 * time.Hour}},
+			expires:  ptr.To(expires.UnixMilli()),
+			want:     expires.Add(-24 * time.Hour),
+		},
+		"RotateBeforeWithoutExpiry": {
+			rotation: &backblazev1.KeyRotation{RotateBefore: &metav1.Duration{Duration: 24 * time.Hour}},
+		},
+		"RotationInterval": {
+			rotation: &backblazev1.KeyRotation{RotationInterval: &metav1.Duration{Duration: 7 * 24 * time.Hour}},
+			want:     created.Add(7 * 24 * time.Hour),
+		},
+		"EarliestWins": {
+			rotation: &backblazev1.KeyRotation{
+				RotateBefore:     &metav1.Duration{Duration: 24 * time.Hour},
+				RotationInterval: &metav1.Duration{Duration: 90 * 24 * time.Hour},
+			},
+			expires: ptr.To(expires.UnixMilli()),
+			want:    expires.Add(-24 * time.Hour),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := &backblazev1.User{
+				Spec: backblazev1.UserSpec{ForProvider: backblazev1.UserParameters{Rotation: tc.rotation}},
+				Status: backblazev1.UserStatus{AtProvider: backblazev1.UserObservation{
+					ExpirationTimestamp: tc.expires,
+					CreationTime:        &metav1.Time{Time: created},
+				}},
+			}
+			if got := nextRotation(user); !got.Equal(tc.want) {
+				t.Errorf("nextRotation() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRequeueAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		rotation *backblazev1.KeyRotation
+		history  []backblazev1.RotatedKey
+		want     time.Duration
+	}{
+		"Poll": {
+			want: pollInterval,
+		},
+		"RotationDue": {
+			rotation: &backblazev1.KeyRotation{RotationInterval: &metav1.Duration{Duration: time.Minute}},
+			want:     time.Minute,
+		},
+		"RotatedKeyDue": {
+			history: []backblazev1.RotatedKey{{ApplicationKeyID: "old", DeletionTime: metav1.Time{Time: now.Add(30 * time.Second)}}},
+			want:    30 * time.Second,
+		},
+		"RotatedKeyDeleted": {
+			history: []backblazev1.RotatedKey{{ApplicationKeyID: "old", DeletionTime: metav1.Time{Time: now.Add(30 * time.Second)}, Deleted: true}},
+			want:    pollInterval,
+		},
+		"Overdue": {
+			rotation: &backblazev1.KeyRotation{RotationInterval: &metav1.Duration{Duration: time.Nanosecond}},
+			want:     time.Second,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := &backblazev1.User{
+				Spec: backblazev1.UserSpec{ForProvider: backblazev1.UserParameters{Rotation: tc.rotation}},
+				Status: backblazev1.UserStatus{AtProvider: backblazev1.UserObservation{
+					CreationTime:    &metav1.Time{Time: now},
+					RotationHistory: tc.history,
+				}},
+			}
+			if got := requeueAfter(user, now); got != tc.want {
+				t.Errorf("requeueAfter() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPruneRotationHistory(t *testing.T) {
+	var history []backblazev1.RotatedKey
+	for i := 0; i < maxRotationHistory+3; i++ {
+		history = append(history, backblazev1.RotatedKey{ApplicationKeyID: fmt.Sprintf("key-%d", i), Deleted: i != 1})
+	}
+
+	got := pruneRotationHistory(history)
+	if len(got) != maxRotationHistory {
+		t.Fatalf("pruneRotationHistory(): want %d keys, got %d", maxRotationHistory, len(got))
+	}
+	// key-1 is still in its grace period, so only key-0, key-2 and key-3
+	// are dropped.
+	if got[0].ApplicationKeyID != "key-1" || got[1].ApplicationKeyID != "key-4" {
+		t.Errorf("pruneRotationHistory() kept %s, %s first, want key-1, key-4", got[0].ApplicationKeyID, got[1].ApplicationKeyID)
+	}
+}
+
+func TestConnectionDetails(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.PutBucket(fake.Bucket{Name: "my-bucket", ID: "bucket-id", Type: "allPrivate", Region: "eu-central-003"})
+
+	tests := map[string]struct {
+		key  *clients.B2CreateKeyResponse
+		want managed.ConnectionDetails
+	}{
+		"Unrestricted": {
+			key: &clients.B2CreateKeyResponse{ApplicationKeyID: "key-id", ApplicationKey: "secret"},
+			want: managed.ConnectionDetails{
+				"applicationKeyId":      []byte("key-id"),
+				"applicationKey":        []byte("secret"),
+				"endpoint":              []byte("https://s3.eu-central-003.backblazeb2.com"),
+				"region":                []byte("eu-central-003"),
+				"AWS_ACCESS_KEY_ID":     []byte("key-id"),
+				"AWS_SECRET_ACCESS_KEY": []byte("secret"),
+			},
+		},
+		"RestrictedToBucket": {
+			key: &clients.B2CreateKeyResponse{ApplicationKeyID: "key-id", ApplicationKey: "secret", BucketIDs: []string{"bucket-id"}},
+			want: managed.ConnectionDetails{
+				"applicationKeyId":      []byte("key-id"),
+				"applicationKey":        []byte("secret"),
+				"endpoint":              []byte("https://s3.eu-central-003.backblazeb2.com"),
+				"region":                []byte("eu-central-003"),
+				"bucketName":            []byte("my-bucket"),
+				"AWS_ACCESS_KEY_ID":     []byte("key-id"),
+				"AWS_SECRET_ACCESS_KEY": []byte("secret"),
+			},
+		},
+		"RestrictedToBuckets": {
+			key: &clients.B2CreateKeyResponse{ApplicationKeyID: "key-id", ApplicationKey: "secret", BucketIDs: []string{"bucket-id", "other-id"}},
+			want: managed.ConnectionDetails{
+				"applicationKeyId":      []byte("key-id"),
+				"applicationKey":        []byte("secret"),
+				"endpoint":              []byte("https://s3.eu-central-003.backblazeb2.com"),
+				"region":                []byte("eu-central-003"),
+				"AWS_ACCESS_KEY_ID":     []byte("key-id"),
+				"AWS_SECRET_ACCESS_KEY": []byte("secret"),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := connectionDetails(context.Background(), backend, "eu-central-003", tc.key)
+			if err != nil {
+				t.Fatalf("connectionDetails() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("connectionDetails(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsKeyUpToDate(t *testing.T) {
+	params := backblazev1.UserParameters{
+		KeyName:      "key",
+		Capabilities: []backblazev1.Capability{"listFiles", "readFiles"},
+		BucketID:     ptr.To("bucket-id"),
+		BucketIDs:    []string{"other-id"},
+	}
+
+	tests := map[string]struct {
+		key  clients.B2CreateKeyResponse
+		want bool
+	}{
+		"UpToDate": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"listFiles", "readFiles"}, BucketIDs: []string{"bucket-id", "other-id"}},
+			want: true,
+		},
+		"CapabilitiesReordered": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"readFiles", "listFiles"}, BucketIDs: []string{"bucket-id", "other-id"}},
+			want: true,
+		},
+		"CapabilityAdded": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"listFiles"}, BucketIDs: []string{"bucket-id", "other-id"}},
+			want: false,
+		},
+		"BucketsReordered": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"listFiles", "readFiles"}, BucketIDs: []string{"other-id", "bucket-id"}},
+			want: true,
+		},
+		"BucketRemoved": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"listFiles", "readFiles"}, BucketIDs: []string{"bucket-id"}},
+			want: false,
+		},
+		"BucketChanged": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"listFiles", "readFiles"}},
+			want: false,
+		},
+		"NamePrefixChanged": {
+			key:  clients.B2CreateKeyResponse{KeyName: "key", Capabilities: []string{"listFiles", "readFiles"}, BucketIDs: []string{"bucket-id", "other-id"}, NamePrefix: "logs/"},
+			want: false,
+		},
+		"KeyNameChanged": {
+			key:  clients.B2CreateKeyResponse{KeyName: "other", Capabilities: []string{"listFiles", "readFiles"}, BucketIDs: []string{"bucket-id", "other-id"}},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isKeyUpToDate(params, &tc.key); got != tc.want {
+				t.Errorf("isKeyUpToDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUserGetCapabilities(t *testing.T) {
+	tests := map[string]struct {
+		params backblazev1.UserParameters
+		want   []string
+	}{
+		"Capabilities": {
+			params: backblazev1.UserParameters{Capabilities: []backblazev1.Capability{"readFiles", "listFiles"}},
+			want:   []string{"listFiles", "readFiles"},
+		},
+		"Preset": {
+			params: backblazev1.UserParameters{CapabilityPreset: ptr.To(backblazev1.CapabilityPresetReadOnly)},
+			want:   []string{"listBuckets", "listFiles", "readBuckets", "readFiles"},
+		},
+		"PresetAndCapabilities": {
+			params: backblazev1.UserParameters{
+				CapabilityPreset: ptr.To(backblazev1.CapabilityPresetReadOnly),
+				Capabilities:     []backblazev1.Capability{"readFiles", "shareFiles"},
+			},
+			want: []string{"listBuckets", "listFiles", "readBuckets", "readFiles", "shareFiles"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.params.GetCapabilities()); diff != "" {
+				t.Errorf("GetCapabilities(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserGetBucketIDs(t *testing.T) {
+	tests := map[string]struct {
+		params backblazev1.UserParameters
+		want   []string
+	}{
+		"Unrestricted": {
+			params: backblazev1.UserParameters{},
+			want:   []string{},
+		},
+		"BucketID": {
+			params: backblazev1.UserParameters{BucketID: ptr.To("a")},
+			want:   []string{"a"},
+		},
+		"BucketIDAndBucketIDs": {
+			params: backblazev1.UserParameters{BucketID: ptr.To("b"), BucketIDs: []string{"c", "a", "b"}},
+			want:   []string{"a", "b", "c"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.params.GetBucketIDs()); diff != "" {
+				t.Errorf("GetBucketIDs(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	tests := map[string]struct {
+		held   []string
+		wanted []string
+		want   []string
+	}{
+		"AllHeld": {
+			held:   []string{"listFiles", "readFiles", "writeFiles"},
+			wanted: []string{"listFiles", "readFiles"},
+		},
+		"SomeMissing": {
+			held:   []string{"listFiles", "readFiles"},
+			wanted: []string{"listFiles", "writeFiles", "bypassGovernance"},
+			want:   []string{"writeFiles", "bypassGovernance"},
+		},
+		"NotReported": {
+			wanted: []string{"writeKeys"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, missingCapabilities(tc.held, tc.wanted)); diff != "" {
+				t.Errorf("missingCapabilities(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsKeyNotFound(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Nil":             {},
+		"KeyNotFound":     {err: clients.ErrApplicationKeyNotFound, want: true},
+		"WrapKeyNotFound": {err: errors.Wrap(clients.ErrApplicationKeyNotFound, "cannot get application key"), want: true},
+		"SecretNotFound":  {err: errors.New(`secrets "backblaze-creds" not found`)},
+		"ProviderConfigNotFound": {
+			err: errors.New(`cannot get ProviderConfig: providerconfigs.backblaze.crossplane.io "default" not found`),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isKeyNotFound(tc.err); got != tc.want {
+				t.Errorf("isKeyNotFound(%v): want %t, got %t", tc.err, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestImportApplicationKeyMissing(t *testing.T) {
+	tests := map[string]struct {
+		externalName string
+		wantNotFound bool
+	}{
+		"NoExternalName": {},
+		"Missing": {
+			externalName: "K005000000000001",
+			wantNotFound: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := &backblazev1.User{ObjectMeta: metav1.ObjectMeta{Name: "my-user"}}
+			meta.SetExternalName(u, tc.externalName)
+			r := &UserReconciler{Recorder: event.NewNopRecorder()}
+
+			imported, err := r.importApplicationKey(context.Background(), u, fake.NewBackend())
+			if imported {
+				t.Errorf("importApplicationKey(...): want not imported")
+			}
+			if got := isKeyNotFound(err); got != tc.wantNotFound {
+				t.Errorf("importApplicationKey(...): want key not found %t, got %v", tc.wantNotFound, err)
+			}
+			if tc.wantNotFound && !strings.Contains(err.Error(), tc.externalName) {
+				t.Errorf("importApplicationKey(...): error %q does not name the key", err)
+			}
+		})
+	}
+}
+
+// newReplacementFixture returns a User whose key old-key is held by its
+// Secret, a Kubernetes client that stores them, and a B2 backend.
+func newReplacementFixture(t *testing.T, fn func(u *backblazev1.User)) (*backblazev1.User, client.Client, *fake.Backend) {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := fake.NewBackend()
+	old, err := backend.CreateApplicationKey(context.Background(), "my-key", []string{"listBuckets"}, nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-key"},
+		Data:       map[string][]byte{clients.SecretKeyApplicationKeyID: []byte(old.ApplicationKeyID)},
+	}
+	u := &backblazev1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-user"},
+		Spec: backblazev1.UserSpec{ForProvider: backblazev1.UserParameters{
+			KeyName:          "my-key",
+			Capabilities:     []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+			WriteSecretToRef: &xpv1.SecretReference{Namespace: "default", Name: "my-key"},
+		}},
+	}
+	meta.SetExternalName(u, old.ApplicationKeyID)
+	u.Status.AtProvider.ApplicationKeyID = old.ApplicationKeyID
+	if fn != nil {
+		fn(u)
+	}
+
+	c := crfake.NewClientBuilder().WithScheme(s).WithObjects(secret, u).WithStatusSubresource(u).Build()
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(u), u); err != nil {
+		t.Fatal(err)
+	}
+	return u, c, backend
+}
+
+func TestReplaceApplicationKeyRecordsReplacement(t *testing.T) {
+	u, c, backend := newReplacementFixture(t, nil)
+	oldKeyID := u.Status.AtProvider.ApplicationKeyID
+	r := &UserReconciler{Client: c, Publisher: managed.NewAPISecretPublisher(c, c.Scheme()), Recorder: event.NewNopRecorder()}
+
+	if err := r.replaceApplicationKey(context.Background(), u, backend, "us-west-004", backblazev1.KeyReplacedRotation, time.Now()); err != nil {
+		t.Fatalf("replaceApplicationKey(...): %v", err)
+	}
+
+	// Until the status is written only the annotations record the
+	// replacement, so they must have been persisted.
+	stored := &backblazev1.User{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(u), stored); err != nil {
+		t.Fatal(err)
+	}
+	if got := stored.GetAnnotations()[backblazev1.AnnotationReplacedKeyID]; got != oldKeyID {
+		t.Errorf("%s annotation: want %q, got %q", backblazev1.AnnotationReplacedKeyID, oldKeyID, got)
+	}
+	if got, want := meta.GetExternalName(stored), u.Status.AtProvider.ApplicationKeyID; got != want {
+		t.Errorf("external name: want %q, got %q", want, got)
+	}
+	if stored.Status.AtProvider.ApplicationKeyID != oldKeyID {
+		t.Errorf("stored status.atProvider.applicationKeyId: want %q, got %q", oldKeyID, stored.Status.AtProvider.ApplicationKeyID)
+	}
+}
+
+func TestResumeReplacement(t *testing.T) {
+	const oldKeyID = "K005000000000001"
+	pending := func(u *backblazev1.User) {
+		meta.AddAnnotations(u, map[string]string{
+			backblazev1.AnnotationReplacedKeyID:     oldKeyID,
+			backblazev1.AnnotationReplacementReason: backblazev1.KeyReplacedRotation,
+		})
+		meta.SetExternalCreatePending(u, time.Now().Add(-time.Minute))
+	}
+
+	type want struct {
+		keyID       string
+		annotations bool
+		history     bool
+		err         bool
+	}
+	tests := map[string]struct {
+		setup func(t *testing.T, u *backblazev1.User, c client.Client, b *fake.Backend)
+		user  func(u *backblazev1.User)
+		want  want
+	}{
+		"StatusLost": {
+			// The key was replaced and published, but the status update
+			// that followed failed.
+			user: pending,
+			setup: func(t *testing.T, u *backblazev1.User, c client.Client, b *fake.Backend) {
+				key, _ := b.CreateApplicationKey(context.Background(), "my-key", []string{"listBuckets"}, nil, "", nil)
+				meta.SetExternalName(u, key.ApplicationKeyID)
+				meta.SetExternalCreateSucceeded(u, time.Now())
+			},
+			want: want{keyID: "K005000000000002", annotations: true, history: true},
+		},
+		"StatusRecorded": {
+			user: func(u *backblazev1.User) {
+				pending(u)
+				meta.SetExternalName(u, "K005000000000002")
+				meta.SetExternalCreateSucceeded(u, time.Now())
+				u.Status.AtProvider.ApplicationKeyID = "K005000000000002"
+				u.Status.AtProvider.RotationHistory = []backblazev1.RotatedKey{{ApplicationKeyID: oldKeyID}}
+			},
+			want: want{keyID: "K005000000000002", history: true},
+		},
+		"CreatedAndPublished": {
+			// The key was created and published, but its ID wasn't
+			// recorded.
+			user: pending,
+			setup: func(t *testing.T, u *backblazev1.User, c client.Client, b *fake.Backend) {
+				key, _ := b.CreateApplicationKey(context.Background(), "my-key", []string{"listBuckets"}, nil, "", nil)
+				s := &corev1.Secret{}
+				if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-key"}, s); err != nil {
+					t.Fatal(err)
+				}
+				s.Data[clients.SecretKeyApplicationKeyID] = []byte(key.ApplicationKeyID)
+				if err := c.Update(context.Background(), s); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: want{keyID: "K005000000000002", annotations: true, history: true},
+		},
+		"CreatedNotPublished": {
+			user: pending,
+			setup: func(t *testing.T, u *backblazev1.User, c client.Client, b *fake.Backend) {
+				_, _ = b.CreateApplicationKey(context.Background(), "my-key", []string{"listBuckets"}, nil, "", nil)
+			},
+			want: want{keyID: oldKeyID, annotations: true, err: true},
+		},
+		"NotCreated": {
+			user: pending,
+			want: want{keyID: oldKeyID},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, c, backend := newReplacementFixture(t, tc.user)
+			if tc.setup != nil {
+				tc.setup(t, u, c, backend)
+			}
+			r := &UserReconciler{Client: c, Recorder: event.NewNopRecorder()}
+			created := backend.CallCount("CreateApplicationKey")
+
+			err := r.resumeReplacement(context.Background(), u, backend)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("resumeReplacement(...): want error %t, got %v", tc.want.err, err)
+			}
+			if backend.CallCount("CreateApplicationKey") != created {
+				t.Errorf("resumeReplacement(...) created an application key")
+			}
+			if got := u.Status.AtProvider.ApplicationKeyID; got != tc.want.keyID {
+				t.Errorf("status.atProvider.applicationKeyId: want %q, got %q", tc.want.keyID, got)
+			}
+			if got := wasReplaced(u, oldKeyID); got != tc.want.history {
+				t.Errorf("rotation history records %s: want %t, got %t", oldKeyID, tc.want.history, got)
+			}
+
+			// Annotations are only removed once the stored status records
+			// the replacement.
+			stored := &backblazev1.User{}
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(u), stored); err != nil {
+				t.Fatal(err)
+			}
+			_, got := stored.GetAnnotations()[backblazev1.AnnotationReplacedKeyID]
+			if got != tc.want.annotations {
+				t.Errorf("stored %s annotation: want %t, got %t", backblazev1.AnnotationReplacedKeyID, tc.want.annotations, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	backblazev2 "github.com/rossigee/provider-backblaze/apis/backblaze/v2"
+	userv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/user/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/scope"
+	"github.com/rossigee/provider-backblaze/internal/validation"
+)
+
+// maxValidDurationInSeconds is the longest an application key may be valid,
+// 1000 days.
+const maxValidDurationInSeconds = 86400000
+
+// keyNamePattern matches a valid B2 application key name.
+var keyNamePattern = regexp.MustCompile(`^[a-zA-Z0-9-]{1,100}$`)
+
+// SetupUserWebhook adds webhooks that validate cluster scoped and namespaced
+// Users when they are created or updated, and that convert cluster scoped
+// Users between API versions. v2 is the conversion hub.
+func SetupUserWebhook(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &backblazev1.User{}).
+		WithValidator(&Validator[*backblazev1.User]{}).
+		WithConverter(conversion.NewHubSpokeConverter(&backblazev2.User{},
+			conversion.NewSpokeConverter(&backblazev1.User{}, backblazev2.ConvertUserFromHub, backblazev2.ConvertUserToHub),
+		)).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr, &userv1beta1.User{}).
+		WithValidator(&Validator[*userv1beta1.User]{}).
+		Complete()
+}
+
+// A Validator validates Users of either scope.
+type Validator[T scope.User] struct{}
+
+// ValidateCreate validates a new User.
+func (v *Validator[T]) ValidateCreate(_ context.Context, u T) (admission.Warnings, error) {
+	return nil, invalid(u, validateUser(u))
+}
+
+// ValidateUpdate validates an updated User, including that the parameters B2
+// can't change haven't been if its replacementPolicy is Reject. Only problems
+// the update introduces are reported.
+func (v *Validator[T]) ValidateUpdate(_ context.Context, old, u T) (admission.Warnings, error) {
+	if validation.SkipUpdate(old, u) {
+		return nil, nil
+	}
+	errs := validation.NewErrors(validateUser(old), validateUser(u))
+	if u.GetForProvider().ReplacementPolicy == backblazev1.ReplacementPolicyReject {
+		errs = append(errs, validateUnchanged(old.GetForProvider(), u.GetForProvider())...)
+	}
+	return nil, invalid(u, errs)
+}
+
+// ValidateDelete allows all Users to be deleted.
+func (v *Validator[T]) ValidateDelete(_ context.Context, _ T) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateUser validates the supplied User.
+func validateUser(u scope.User) field.ErrorList {
+	var errs field.ErrorList
+	p := u.GetForProvider()
+	params := field.NewPath("spec", "forProvider")
+
+	if !keyNamePattern.MatchString(p.KeyName) {
+		errs = append(errs, field.Invalid(params.Child("keyName"), p.KeyName, "must be 1 to 100 letters, digits and hyphens"))
+	}
+
+	all := backblazev1.CapabilityPresets[backblazev1.CapabilityPresetAdmin]
+	for i, c := range p.Capabilities {
+		if !slices.Contains(all, c) {
+			errs = append(errs, field.NotSupported(params.Child("capabilities").Index(i), c, all))
+		}
+	}
+	if p.CapabilityPreset != nil {
+		if _, ok := backblazev1.CapabilityPresets[*p.CapabilityPreset]; !ok {
+			errs = append(errs, field.NotSupported(params.Child("capabilityPreset"), *p.CapabilityPreset, []backblazev1.CapabilityPreset{
+				backblazev1.CapabilityPresetReadOnly, backblazev1.CapabilityPresetReadWrite,
+				backblazev1.CapabilityPresetBackupWriter, backblazev1.CapabilityPresetAdmin,
+			}))
+		}
+	}
+	if len(p.Capabilities) == 0 && p.CapabilityPreset == nil {
+		errs = append(errs, field.Required(params.Child("capabilities"), "either capabilities or capabilityPreset must be set"))
+	}
+
+	if d := p.ValidDurationInSeconds; d != nil && (*d < 1 || *d > maxValidDurationInSeconds) {
+		errs = append(errs, field.Invalid(params.Child("validDurationInSeconds"), *d, "must be between 1 and 86400000 (1000 days)"))
+	}
+
+	if r := p.Rotation; r != nil {
+		rotation := params.Child("rotation")
+		for _, d := range []struct {
+			name     string
+			duration *metav1.Duration
+		}{{"rotateBefore", r.RotateBefore}, {"rotationInterval", r.RotationInterval}, {"gracePeriod", r.GracePeriod}} {
+			if d.duration != nil && d.duration.Duration < 0 {
+				errs = append(errs, field.Invalid(rotation.Child(d.name), d.duration.String(), "must not be negative"))
+			}
+		}
+		if r.RotateBefore != nil {
+			switch {
+			case p.ValidDurationInSeconds == nil:
+				errs = append(errs, field.Required(params.Child("validDurationInSeconds"), "rotation.rotateBefore requires validDurationInSeconds"))
+			case int64(r.RotateBefore.Seconds()) >= *p.ValidDurationInSeconds:
+				errs = append(errs, field.Invalid(rotation.Child("rotateBefore"), r.RotateBefore.String(), "must be shorter than validDurationInSeconds"))
+			}
+		}
+	}
+
+	// Namespaced Users can only write to Secrets in their own namespace.
+	if _, namespaced := u.(resource.LocalConnectionSecretWriterTo); namespaced && p.WriteSecretToRef != nil {
+		errs = append(errs, field.Forbidden(params.Child("writeSecretToRef"), "not supported by namespaced Users: use writeConnectionSecretToRef"))
+	}
+
+	observeOnly := slices.Equal(u.GetManagementPolicies(), xpv1.ManagementPolicies{xpv1.ManagementActionObserve})
+	if connectionSecretRef(u) == nil && p.WriteSecretToRef == nil && !observeOnly {
+		errs = append(errs, field.Required(field.NewPath("spec", "writeConnectionSecretToRef"), "either writeConnectionSecretToRef or forProvider.writeSecretToRef must be set unless the application key is only observed"))
+	}
+	return errs
+}
+
+// validateUnchanged validates that none of the parameters B2 can't change on
+// an existing application key have changed.
+func validateUnchanged(old, p *backblazev1.UserParameters) field.ErrorList {
+	var errs field.ErrorList
+	params := field.NewPath("spec", "forProvider")
+	const msg = "can't be changed when replacementPolicy is Reject"
+
+	if p.KeyName != old.KeyName {
+		errs = append(errs, field.Forbidden(params.Child("keyName"), msg))
+	}
+	if !slices.Equal(p.GetCapabilities(), old.GetCapabilities()) {
+		errs = append(errs, field.Forbidden(params.Child("capabilities"), msg))
+	}
+	// The controller writes the IDs of referenced Buckets to bucketIds, so
+	// they're only compared when the buckets aren't referenced.
+	switch {
+	case len(p.BucketRefs) > 0 || len(old.BucketRefs) > 0:
+		if !slices.Equal(refNames(p.BucketRefs), refNames(old.BucketRefs)) {
+			errs = append(errs, field.Forbidden(params.Child("bucketRefs"), msg))
+		}
+	case !slices.Equal(p.GetBucketIDs(), old.GetBucketIDs()):
+		errs = append(errs, field.Forbidden(params.Child("bucketIds"), msg))
+	}
+	if ptr.Deref(p.NamePrefix, "") != ptr.Deref(old.NamePrefix, "") {
+		errs = append(errs, field.Forbidden(params.Child("namePrefix"), msg))
+	}
+	return errs
+}
+
+// refNames returns the names of the supplied references, sorted.
+func refNames(refs []xpv1.Reference) []string {
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		names = append(names, r.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// invalid returns an Invalid error for the supplied User, or nil if there are
+// no errors.
+func invalid(u scope.User, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(u.GetObjectKind().GroupVersionKind().GroupKind(), u.GetName(), errs)
+}
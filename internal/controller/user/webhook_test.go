@@ -0,0 +1,361 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/rossigee/provider-backblaze/apis"
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	userv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/user/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/scope"
+)
+
+func TestValidateUser(t *testing.T) {
+	secret := &xpv1.SecretReference{Name: "key", Namespace: "default"}
+	tests := map[string]struct {
+		params   backblazev1.UserParameters
+		policies xpv1.ManagementPolicies
+		want     []string
+	}{
+		"Valid": {
+			params: backblazev1.UserParameters{
+				KeyName:          "my-key",
+				Capabilities:     []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+				WriteSecretToRef: secret,
+			},
+		},
+		"InvalidKeyName": {
+			params: backblazev1.UserParameters{
+				KeyName:          "my key",
+				CapabilityPreset: ptr.To(backblazev1.CapabilityPresetReadOnly),
+				WriteSecretToRef: secret,
+			},
+			want: []string{"spec.forProvider.keyName"},
+		},
+		"UnknownCapability": {
+			params: backblazev1.UserParameters{
+				KeyName:          "my-key",
+				Capabilities:     []backblazev1.Capability{backblazev1.CapabilityListBuckets, "readEverything"},
+				WriteSecretToRef: secret,
+			},
+			want: []string{"spec.forProvider.capabilities[1]"},
+		},
+		"UnknownPreset": {
+			params: backblazev1.UserParameters{
+				KeyName:          "my-key",
+				CapabilityPreset: ptr.To(backblazev1.CapabilityPreset("superUser")),
+				WriteSecretToRef: secret,
+			},
+			want: []string{"spec.forProvider.capabilityPreset"},
+		},
+		"NoCapabilities": {
+			params: backblazev1.UserParameters{KeyName: "my-key", WriteSecretToRef: secret},
+			want:   []string{"spec.forProvider.capabilities"},
+		},
+		"ValidDurationTooLong": {
+			params: backblazev1.UserParameters{
+				KeyName:                "my-key",
+				Capabilities:           []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+				ValidDurationInSeconds: ptr.To[int64](86400001),
+				WriteSecretToRef:       secret,
+			},
+			want: []string{"spec.forProvider.validDurationInSeconds"},
+		},
+		"RotateBeforeWithoutValidDuration": {
+			params: backblazev1.UserParameters{
+				KeyName:          "my-key",
+				Capabilities:     []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+				Rotation:         &backblazev1.KeyRotation{RotateBefore: &metav1.Duration{Duration: time.Hour}},
+				WriteSecretToRef: secret,
+			},
+			want: []string{"spec.forProvider.validDurationInSeconds"},
+		},
+		"RotateBeforeTooLong": {
+			params: backblazev1.UserParameters{
+				KeyName:                "my-key",
+				Capabilities:           []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+				ValidDurationInSeconds: ptr.To[int64](3600),
+				Rotation:               &backblazev1.KeyRotation{RotateBefore: &metav1.Duration{Duration: time.Hour}},
+				WriteSecretToRef:       secret,
+			},
+			want: []string{"spec.forProvider.rotation.rotateBefore"},
+		},
+		"NegativeGracePeriod": {
+			params: backblazev1.UserParameters{
+				KeyName:          "my-key",
+				Capabilities:     []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+				Rotation:         &backblazev1.KeyRotation{GracePeriod: &metav1.Duration{Duration: -time.Minute}},
+				WriteSecretToRef: secret,
+			},
+			want: []string{"spec.forProvider.rotation.gracePeriod"},
+		},
+		"NoSecret": {
+			params: backblazev1.UserParameters{
+				KeyName:      "my-key",
+				Capabilities: []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+			},
+			want: []string{"spec.writeConnectionSecretToRef"},
+		},
+		"NoSecretObserveOnly": {
+			params: backblazev1.UserParameters{
+				KeyName:      "my-key",
+				Capabilities: []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+			},
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := &backblazev1.User{Spec: backblazev1.UserSpec{ForProvider: tc.params}}
+			if tc.policies != nil {
+				u.SetManagementPolicies(tc.policies)
+			}
+			var got []string
+			for _, e := range validateUser(u) {
+				got = append(got, e.Field)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("validateUser(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateNamespacedUser(t *testing.T) {
+	params := backblazev1.UserParameters{
+		KeyName:      "my-key",
+		Capabilities: []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+	}
+	tests := map[string]struct {
+		secret  *xpv1.LocalSecretReference
+		writeTo *xpv1.SecretReference
+		want    []string
+	}{
+		"Valid": {
+			secret: &xpv1.LocalSecretReference{Name: "key"},
+		},
+		"WriteSecretToRef": {
+			secret:  &xpv1.LocalSecretReference{Name: "key"},
+			writeTo: &xpv1.SecretReference{Name: "key", Namespace: "default"},
+			want:    []string{"spec.forProvider.writeSecretToRef"},
+		},
+		"NoSecret": {
+			want: []string{"spec.writeConnectionSecretToRef"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := &userv1beta1.User{Spec: userv1beta1.UserSpec{
+				ManagementPolicies:               xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+				WriteConnectionSecretToReference: tc.secret,
+				ForProvider:                      params,
+			}}
+			u.Spec.ForProvider.WriteSecretToRef = tc.writeTo
+			var got []string
+			for _, e := range validateUser(u) {
+				got = append(got, e.Field)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("validateUser(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	secret := &xpv1.SecretReference{Name: "key", Namespace: "default"}
+	base := backblazev1.UserParameters{
+		KeyName:          "my-key",
+		Capabilities:     []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+		BucketRefs:       []xpv1.Reference{{Name: "a"}, {Name: "b"}},
+		WriteSecretToRef: secret,
+	}
+	tests := map[string]struct {
+		update  func(p *backblazev1.UserParameters)
+		wantErr bool
+	}{
+		"Unchanged": {
+			update: func(_ *backblazev1.UserParameters) {},
+		},
+		"BucketRefsReordered": {
+			update: func(p *backblazev1.UserParameters) {
+				p.BucketRefs = []xpv1.Reference{{Name: "b"}, {Name: "a"}}
+			},
+		},
+		"BucketRefsResolved": {
+			// The controller persists the IDs of the referenced Buckets.
+			update: func(p *backblazev1.UserParameters) {
+				p.BucketIDs = []string{"bucket-a-id", "bucket-b-id"}
+			},
+		},
+		"BucketIDsChanged": {
+			update: func(p *backblazev1.UserParameters) {
+				p.BucketRefs = nil
+				p.BucketIDs = []string{"bucket-a-id"}
+			},
+			wantErr: true,
+		},
+		"KeyNameChanged": {
+			update: func(p *backblazev1.UserParameters) {
+				p.KeyName = "my-other-key"
+			},
+			wantErr: true,
+		},
+		"CapabilitiesChanged": {
+			update: func(p *backblazev1.UserParameters) {
+				p.Capabilities = append(p.Capabilities, backblazev1.CapabilityReadBuckets)
+			},
+			wantErr: true,
+		},
+		"BucketRefsChanged": {
+			update: func(p *backblazev1.UserParameters) {
+				p.BucketRefs = []xpv1.Reference{{Name: "a"}}
+			},
+			wantErr: true,
+		},
+		"NamePrefixChanged": {
+			update: func(p *backblazev1.UserParameters) {
+				p.NamePrefix = ptr.To("logs/")
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := &backblazev1.User{Spec: backblazev1.UserSpec{ForProvider: *base.DeepCopy()}}
+			old.Spec.ForProvider.ReplacementPolicy = backblazev1.ReplacementPolicyReject
+			u := old.DeepCopy()
+			tc.update(&u.Spec.ForProvider)
+			_, err := (&Validator[*backblazev1.User]{}).ValidateUpdate(context.Background(), old, u)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateUpdate(...): want error %t, got %v", tc.wantErr, err)
+			}
+
+			// Without a replacementPolicy of Reject any change is allowed.
+			old.Spec.ForProvider.ReplacementPolicy = ""
+			u.Spec.ForProvider.ReplacementPolicy = ""
+			if _, err := (&Validator[*backblazev1.User]{}).ValidateUpdate(context.Background(), old, u); err != nil {
+				t.Errorf("ValidateUpdate(...) with replacementPolicy Replace: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateExisting(t *testing.T) {
+	// A User admitted before a connection secret was required.
+	old := &backblazev1.User{Spec: backblazev1.UserSpec{ForProvider: backblazev1.UserParameters{
+		KeyName:      "my-key",
+		Capabilities: []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+	}}}
+	old.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+
+	tests := map[string]struct {
+		update  func(u *backblazev1.User)
+		wantErr bool
+	}{
+		"FinalizerRemoved": {
+			update: func(u *backblazev1.User) { u.SetFinalizers(nil) },
+		},
+		"Deleting": {
+			update: func(u *backblazev1.User) {
+				u.SetDeletionTimestamp(&metav1.Time{})
+				u.Spec.ForProvider.KeyName = "my key"
+			},
+		},
+		"ExistingProblem": {
+			update: func(u *backblazev1.User) { u.Spec.ForProvider.NamePrefix = ptr.To("logs/") },
+		},
+		"NewProblem": {
+			update:  func(u *backblazev1.User) { u.Spec.ForProvider.KeyName = "my key" },
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := old.DeepCopy()
+			tc.update(u)
+			_, err := (&Validator[*backblazev1.User]{}).ValidateUpdate(context.Background(), old, u)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ValidateUpdate(...): want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateResolvedReferences(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	bucket := func(name, id string) *backblazev1.Bucket {
+		b := &backblazev1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		b.Status.AtProvider.BucketID = id
+		return b
+	}
+	u := &backblazev1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-user"},
+		Spec: backblazev1.UserSpec{ForProvider: backblazev1.UserParameters{
+			KeyName:           "my-key",
+			Capabilities:      []backblazev1.Capability{backblazev1.CapabilityListBuckets},
+			BucketRefs:        []xpv1.Reference{{Name: "a"}, {Name: "b"}},
+			ReplacementPolicy: backblazev1.ReplacementPolicyReject,
+			WriteSecretToRef:  &xpv1.SecretReference{Name: "key", Namespace: "default"},
+		}},
+	}
+
+	// Send the update the controller makes when it resolves references
+	// through the webhook, as the API server would.
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(bucket("a", "bucket-a-id"), bucket("b", "bucket-b-id"), u.DeepCopy()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				old := &backblazev1.User{}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), old); err != nil {
+					return err
+				}
+				if _, err := (&Validator[*backblazev1.User]{}).ValidateUpdate(ctx, old, obj.(*backblazev1.User)); err != nil {
+					return err
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(u), u); err != nil {
+		t.Fatal(err)
+	}
+	r := &UserReconciler{Client: c, Scope: scope.Cluster}
+	if err := r.resolveReferences(context.Background(), u); err != nil {
+		t.Fatalf("resolveReferences(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"bucket-a-id", "bucket-b-id"}, u.Spec.ForProvider.BucketIDs); diff != "" {
+		t.Errorf("resolveReferences(...): -want, +got:\n%s", diff)
+	}
+}
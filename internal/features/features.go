@@ -23,11 +23,6 @@ import (
 
 // Flags.
 const (
-	// EnableAlphaExternalSecretStores enables alpha support for
-	// External Secret Stores. See the below design for more details.
-	// https://github.com/crossplane/crossplane/blob/master/design/design-doc-external-secret-stores.md
-	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
-
 	// EnableAlphaManagementPolicies enables alpha support for
 	// Management Policies. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/master/design/design-doc-management-policies.md
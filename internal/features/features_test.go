@@ -27,11 +27,6 @@ func TestFeatureFlags(t *testing.T) {
 		flag feature.Flag
 		want string
 	}{
-		{
-			name: "EnableAlphaExternalSecretStores",
-			flag: EnableAlphaExternalSecretStores,
-			want: "EnableAlphaExternalSecretStores",
-		},
 		{
 			name: "EnableAlphaManagementPolicies",
 			flag: EnableAlphaManagementPolicies,
@@ -52,14 +47,9 @@ func TestFeatureFlagsWithRuntime(t *testing.T) {
 	flags := &feature.Flags{}
 
 	// Test enabling features
-	flags.Enable(EnableAlphaExternalSecretStores)
 	flags.Enable(EnableAlphaManagementPolicies)
 
 	// Test that flags can be enabled without error
-	if !flags.Enabled(EnableAlphaExternalSecretStores) {
-		t.Error("EnableAlphaExternalSecretStores should be enabled")
-	}
-
 	if !flags.Enabled(EnableAlphaManagementPolicies) {
 		t.Error("EnableAlphaManagementPolicies should be enabled")
 	}
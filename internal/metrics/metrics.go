@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exports Prometheus metrics about the provider's Backblaze
+// B2 API calls and managed resources. They are registered with
+// controller-runtime's registry, so they are served by the manager's metrics
+// endpoint alongside its reconcile metrics.
+package metrics
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The B2 APIs whose requests are recorded.
+const (
+	// APINative is the B2 Native API.
+	APINative = "native"
+	// APIS3 is the B2 S3 compatible API.
+	APIS3 = "s3"
+)
+
+// Results of auth refreshes and purged objects.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// codeError is the code label of requests that got no HTTP response.
+const codeError = "error"
+
+const (
+	namespace   = "backblaze"
+	subsystemB2 = "b2"
+)
+
+var (
+	// Requests counts B2 API requests by API, operation and HTTP status code.
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemB2,
+		Name:      "requests_total",
+		Help:      "Number of Backblaze B2 API requests, by API, operation and HTTP status code.",
+	}, []string{"api", "operation", "code"})
+
+	// RequestDuration observes the latency of B2 API requests by API,
+	// operation and HTTP status code.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystemB2,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of Backblaze B2 API requests, by API, operation and HTTP status code.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"api", "operation", "code"})
+
+	// RateLimited counts B2 API requests rejected with 429 Too Many Requests.
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemB2,
+		Name:      "rate_limited_total",
+		Help:      "Number of Backblaze B2 API requests rejected because of rate limiting, by API and operation.",
+	}, []string{"api", "operation"})
+
+	// AuthRefreshes counts b2_authorize_account calls made to get a new
+	// authorization token, by result.
+	AuthRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemB2,
+		Name:      "auth_refreshes_total",
+		Help:      "Number of Backblaze B2 authorization token refreshes, by result.",
+	}, []string{"result"})
+
+	// PurgedObjects counts the objects deleted, or that could not be
+	// deleted, while emptying buckets before deleting them.
+	PurgedObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "bucket",
+		Name:      "purge_objects_total",
+		Help:      "Number of objects deleted while emptying buckets before deleting them, by bucket and result.",
+	}, []string{"bucket", "result"})
+
+	// PurgesInProgress is 1 for each bucket that is being emptied.
+	PurgesInProgress = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "bucket",
+		Name:      "purge_in_progress",
+		Help:      "Whether a bucket is being emptied before it is deleted.",
+	}, []string{"bucket"})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(Requests, RequestDuration, RateLimited, AuthRefreshes, PurgedObjects, PurgesInProgress)
+}
+
+// ObserveAuthRefresh records an authorization token refresh that returned
+// the supplied error.
+func ObserveAuthRefresh(err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+	AuthRefreshes.WithLabelValues(result).Inc()
+}
+
+// StartPurge records that the named bucket is being emptied. The returned
+// function records that it no longer is.
+func StartPurge(bucket string) func() {
+	PurgesInProgress.WithLabelValues(bucket).Set(1)
+	return func() { PurgesInProgress.DeleteLabelValues(bucket) }
+}
+
+// ObservePurged records that deleted objects were deleted, and failed objects
+// could not be, while emptying the named bucket.
+func ObservePurged(bucket string, deleted, failed int) {
+	PurgedObjects.WithLabelValues(bucket, ResultSuccess).Add(float64(deleted))
+	PurgedObjects.WithLabelValues(bucket, ResultFailure).Add(float64(failed))
+}
+
+// A Transport records metrics about the B2 API requests sent through it.
+type Transport struct {
+	api  string
+	next http.RoundTripper
+}
+
+// NewTransport returns a Transport that sends requests to the supplied B2 API
+// using next.
+func NewTransport(api string, next http.RoundTripper) *Transport {
+	return &Transport{api: api, next: next}
+}
+
+// RoundTrip sends the supplied request and records its outcome.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	op := t.operation(req)
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	code := codeError
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			RateLimited.WithLabelValues(t.api, op).Inc()
+		}
+	}
+	Requests.WithLabelValues(t.api, op, code).Inc()
+	RequestDuration.WithLabelValues(t.api, op, code).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// operation returns the name of the B2 operation the supplied request calls,
+// such as b2_list_buckets or ListObjectsV2.
+func (t *Transport) operation(req *http.Request) string {
+	if t.api == APIS3 {
+		if op := awsmiddleware.GetOperationName(req.Context()); op != "" {
+			return op
+		}
+	}
+	return path.Base(req.URL.Path)
+}
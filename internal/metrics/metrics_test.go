@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/b2api/v3/b2_list_buckets" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cases := map[string]struct {
+		reason      string
+		api         string
+		url         string
+		operation   string
+		code        string
+		rateLimited float64
+	}{
+		"Native": {
+			reason:    "Native API requests should be named after the last element of their path.",
+			api:       APINative,
+			url:       srv.URL + "/b2api/v3/b2_create_key",
+			operation: "b2_create_key",
+			code:      "200",
+		},
+		"NativeRateLimited": {
+			reason:      "Requests rejected with 429 should be counted as rate limited.",
+			api:         APINative,
+			url:         srv.URL + "/b2api/v3/b2_list_buckets",
+			operation:   "b2_list_buckets",
+			code:        "429",
+			rateLimited: 1,
+		},
+		"NoResponse": {
+			reason:    "Requests that get no response should have the error code.",
+			api:       APINative,
+			url:       "http://127.0.0.1:0/b2api/v3/b2_delete_key",
+			operation: "b2_delete_key",
+			code:      codeError,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			before := testutil.ToFloat64(Requests.WithLabelValues(tc.api, tc.operation, tc.code))
+			limited := testutil.ToFloat64(RateLimited.WithLabelValues(tc.api, tc.operation))
+
+			c := &http.Client{Transport: NewTransport(tc.api, http.DefaultTransport)}
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp, err := c.Do(req); err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if got := testutil.ToFloat64(Requests.WithLabelValues(tc.api, tc.operation, tc.code)) - before; got != 1 {
+				t.Errorf("\n%s\nrequests: want 1, got %v", tc.reason, got)
+			}
+			if got := testutil.ToFloat64(RateLimited.WithLabelValues(tc.api, tc.operation)) - limited; got != tc.rateLimited {
+				t.Errorf("\n%s\nrate limited: want %v, got %v", tc.reason, tc.rateLimited, got)
+			}
+		})
+	}
+}
+
+func TestTransportS3(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<ListBucketResult><Name>my-bucket</Name><IsTruncated>false</IsTruncated></ListBucketResult>`))
+	}))
+	defer srv.Close()
+
+	c := s3.New(s3.Options{
+		Region:       "us-west-004",
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("id", "key", ""),
+		HTTPClient:   &http.Client{Transport: NewTransport(APIS3, http.DefaultTransport)},
+	})
+	before := testutil.ToFloat64(Requests.WithLabelValues(APIS3, "ListObjectsV2", "200"))
+	if _, err := c.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{Bucket: aws.String("my-bucket")}); err != nil {
+		t.Fatalf("ListObjectsV2(...): %v", err)
+	}
+	if got := testutil.ToFloat64(Requests.WithLabelValues(APIS3, "ListObjectsV2", "200")) - before; got != 1 {
+		t.Errorf("S3 requests should be named after the SDK operation: want 1 ListObjectsV2 request, got %v", got)
+	}
+}
+
+func TestObserveAuthRefresh(t *testing.T) {
+	success := testutil.ToFloat64(AuthRefreshes.WithLabelValues(ResultSuccess))
+	failure := testutil.ToFloat64(AuthRefreshes.WithLabelValues(ResultFailure))
+
+	ObserveAuthRefresh(nil)
+	ObserveAuthRefresh(errors.New("boom"))
+	ObserveAuthRefresh(errors.New("boom"))
+
+	if got := testutil.ToFloat64(AuthRefreshes.WithLabelValues(ResultSuccess)) - success; got != 1 {
+		t.Errorf("successful refreshes: want 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(AuthRefreshes.WithLabelValues(ResultFailure)) - failure; got != 2 {
+		t.Errorf("failed refreshes: want 2, got %v", got)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	const bucket = "purge-test"
+
+	done := StartPurge(bucket)
+	if got := testutil.ToFloat64(PurgesInProgress.WithLabelValues(bucket)); got != 1 {
+		t.Errorf("in progress during purge: want 1, got %v", got)
+	}
+	ObservePurged(bucket, 1000, 2)
+	ObservePurged(bucket, 10, 0)
+	done()
+
+	if got := testutil.CollectAndCount(PurgesInProgress); got != 0 {
+		t.Errorf("in progress series after purge: want 0, got %d", got)
+	}
+	if got := testutil.ToFloat64(PurgedObjects.WithLabelValues(bucket, ResultSuccess)); got != 1010 {
+		t.Errorf("deleted objects: want 1010, got %v", got)
+	}
+	if got := testutil.ToFloat64(PurgedObjects.WithLabelValues(bucket, ResultFailure)); got != 2 {
+		t.Errorf("failed objects: want 2, got %v", got)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+	policyv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/policy/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/user/v1beta1"
+)
+
+// listTimeout bounds how long a scrape waits to list each kind.
+const listTimeout = 5 * time.Second
+
+var managedResourcesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "managed_resources"),
+	"Number of managed resources, by kind and the status of their Ready and Synced conditions.",
+	[]string{"kind", "ready", "synced"}, nil,
+)
+
+// A managedKind is a kind of managed resource whose resources are counted.
+type managedKind struct {
+	groupKind schema.GroupKind
+	newList   func() client.ObjectList
+}
+
+// managedKinds are the managed resources reconciled by the provider.
+var managedKinds = []managedKind{
+	{backblazev1.BucketGroupKind, func() client.ObjectList { return &backblazev1.BucketList{} }},
+	{backblazev1.UserGroupKind, func() client.ObjectList { return &backblazev1.UserList{} }},
+	{backblazev1.PolicyGroupKind, func() client.ObjectList { return &backblazev1.PolicyList{} }},
+	{backblazev1.BucketNotificationGroupKind, func() client.ObjectList { return &backblazev1.BucketNotificationList{} }},
+	{bucketv1beta1.BucketGroupKind, func() client.ObjectList { return &bucketv1beta1.BucketList{} }},
+	{userv1beta1.UserGroupKind, func() client.ObjectList { return &userv1beta1.UserList{} }},
+	{policyv1beta1.PolicyGroupKind, func() client.ObjectList { return &policyv1beta1.PolicyList{} }},
+}
+
+// A ManagedResourceCollector counts managed resources by kind, readiness and
+// sync state each time it is scraped.
+type ManagedResourceCollector struct {
+	reader client.Reader
+	kinds  []managedKind
+}
+
+// NewManagedResourceCollector returns a collector that lists managed
+// resources using the supplied reader, which is usually the manager's cache.
+func NewManagedResourceCollector(r client.Reader) *ManagedResourceCollector {
+	return &ManagedResourceCollector{reader: r, kinds: managedKinds}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ManagedResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+}
+
+// Collect implements prometheus.Collector. Kinds that can't be listed, for
+// example because the cache hasn't synced yet, are omitted.
+func (c *ManagedResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, k := range c.kinds {
+		counts, err := c.count(k)
+		if err != nil {
+			continue
+		}
+		for s, n := range counts {
+			ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(n), k.groupKind.String(), s.ready, s.synced)
+		}
+	}
+}
+
+// conditionState is the status of a managed resource's Ready and Synced
+// conditions.
+type conditionState struct {
+	ready  string
+	synced string
+}
+
+// count returns how many resources of the supplied kind are in each
+// condition state.
+func (c *ManagedResourceCollector) count(k managedKind) (map[conditionState]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+
+	l := k.newList()
+	if err := c.reader.List(ctx, l); err != nil {
+		return nil, err
+	}
+	counts := map[conditionState]int{}
+	err := meta.EachListItem(l, func(o runtime.Object) error {
+		mg, ok := o.(resource.Conditioned)
+		if !ok {
+			return errors.Errorf("%T has no conditions", o)
+		}
+		counts[conditionState{
+			ready:  string(mg.GetCondition(xpv1.TypeReady).Status),
+			synced: string(mg.GetCondition(xpv1.TypeSynced).Status),
+		}]++
+		return nil
+	})
+	return counts, err
+}
+
+// SetupManagedResourceMetrics registers a ManagedResourceCollector that reads
+// from the manager's cache with controller-runtime's metrics registry.
+func SetupManagedResourceMetrics(mgr ctrl.Manager) error {
+	return errors.Wrap(crmetrics.Registry.Register(NewManagedResourceCollector(mgr.GetCache())), "cannot register managed resource metrics")
+}
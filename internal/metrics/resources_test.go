@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rossigee/provider-backblaze/apis"
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+)
+
+func TestManagedResourceCollector(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	ready := &backblazev1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "ready"}}
+	ready.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	ready2 := &backblazev1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "ready2"}}
+	ready2.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	failing := &backblazev1.Bucket{ObjectMeta: metav1.ObjectMeta{Name: "failing"}}
+	failing.SetConditions(xpv1.Creating(), xpv1.ReconcileError(errors.New("boom")))
+	user := &backblazev1.User{ObjectMeta: metav1.ObjectMeta{Name: "new"}}
+	namespaced := &bucketv1beta1.Bucket{ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "ready"}}
+	namespaced.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(ready, ready2, failing, user, namespaced).Build()
+
+	want := `
+# HELP backblaze_managed_resources Number of managed resources, by kind and the status of their Ready and Synced conditions.
+# TYPE backblaze_managed_resources gauge
+backblaze_managed_resources{kind="Bucket.backblaze.crossplane.io",ready="False",synced="False"} 1
+backblaze_managed_resources{kind="Bucket.backblaze.crossplane.io",ready="True",synced="True"} 2
+backblaze_managed_resources{kind="Bucket.bucket.backblaze.m.crossplane.io",ready="True",synced="True"} 1
+backblaze_managed_resources{kind="User.backblaze.crossplane.io",ready="Unknown",synced="Unknown"} 1
+`
+	if err := testutil.CollectAndCompare(NewManagedResourceCollector(c), strings.NewReader(want)); err != nil {
+		t.Errorf("CollectAndCompare(...): %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recorder records Kubernetes events about managed resources.
+package recorder
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/rossigee/provider-backblaze/internal/clients"
+)
+
+// MaxNoteLength is the longest note the events.k8s.io API accepts.
+const MaxNoteLength = 1024
+
+// Reasons of events about external resources. They are those recorded by
+// crossplane-runtime's managed reconciler, so events read the same for every
+// provider.
+const (
+	ReasonCreated       event.Reason = "CreatedExternalResource"
+	ReasonUpdated       event.Reason = "UpdatedExternalResource"
+	ReasonDeleted       event.Reason = "DeletedExternalResource"
+	ReasonCannotObserve event.Reason = "CannotObserveExternalResource"
+	ReasonCannotCreate  event.Reason = "CannotCreateExternalResource"
+	ReasonCannotUpdate  event.Reason = "CannotUpdateExternalResource"
+	ReasonCannotDelete  event.Reason = "CannotDeleteExternalResource"
+
+	// ReasonRotatedKey is recorded when an application key is replaced
+	// because it was due to be rotated.
+	ReasonRotatedKey event.Reason = "RotatedApplicationKey"
+)
+
+// AnnotationB2ErrorCode is the event annotation that holds the code of the B2
+// API error a warning event was recorded for.
+const AnnotationB2ErrorCode = "backblaze.crossplane.io/b2-error-code"
+
+// Warning returns a warning event for the supplied error. If the error was
+// returned by the B2 API its code is added to the message, where kubectl
+// shows it, and as the AnnotationB2ErrorCode annotation.
+func Warning(r event.Reason, err error) event.Event {
+	code := clients.ErrorCode(err)
+	if code == "" {
+		return event.Warning(r, err)
+	}
+	return event.Warning(r, fmt.Errorf("B2 error code %s: %w", code, err), AnnotationB2ErrorCode, code)
+}
+
+// A Recorder records events using the events.k8s.io API. Unlike
+// crossplane-runtime's APIRecorder it sets the action the API requires, to
+// the event's reason, and truncates notes that are too long.
+type Recorder struct {
+	kube events.EventRecorder
+}
+
+// New returns a Recorder that records events using the supplied
+// EventRecorder.
+func New(r events.EventRecorder) *Recorder {
+	return &Recorder{kube: r}
+}
+
+// Event records the supplied event.
+func (r *Recorder) Event(obj runtime.Object, e event.Event) {
+	r.kube.Eventf(obj, nil, string(e.Type), string(e.Reason), string(e.Reason), "%s", truncate(e.Message, MaxNoteLength))
+}
+
+// WithAnnotations returns the Recorder. The events.k8s.io API does not
+// support annotations on events.
+func (r *Recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+// truncate returns s shortened to at most n bytes. It is cut at the start of
+// a rune, so that a multi-byte character isn't split.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	i := n - 3
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i] + "..."
+}
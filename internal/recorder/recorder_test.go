@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/google/go-cmp/cmp"
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/rossigee/provider-backblaze/internal/clients"
+)
+
+func TestEvent(t *testing.T) {
+	cases := map[string]struct {
+		e    event.Event
+		want string
+	}{
+		"Normal": {
+			e:    event.Normal("Updated", "updated the thing"),
+			want: "Normal Updated updated the thing",
+		},
+		"Warning": {
+			e:    event.Warning("CannotUpdate", errors.New("boom")),
+			want: "Warning CannotUpdate boom",
+		},
+		"Truncated": {
+			e:    event.Normal("Updated", strings.Repeat("x", MaxNoteLength+1)),
+			want: "Normal Updated " + strings.Repeat("x", MaxNoteLength-3) + "...",
+		},
+		"TruncatedAtRune": {
+			// Each "é" is two bytes, so the cut falls in the middle of one,
+			// which is dropped.
+			e:    event.Normal("Updated", strings.Repeat("é", MaxNoteLength/2+1)),
+			want: "Normal Updated " + strings.Repeat("é", (MaxNoteLength-4)/2) + "...",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := events.NewFakeRecorder(1)
+			New(fake).Event(&corev1.ConfigMap{}, tc.e)
+			if diff := cmp.Diff(tc.want, <-fake.Events); diff != "" {
+				t.Errorf("Event(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWarning(t *testing.T) {
+	cases := map[string]struct {
+		err         error
+		wantMessage string
+		wantCode    string
+	}{
+		"B2Error": {
+			err:         pkgerrors.Wrap(&clients.B2Error{Status: 400, Code: "bad_request", Message: "Invalid bucketId"}, "cannot create application key"),
+			wantMessage: "B2 error code bad_request: cannot create application key: bad_request (400): Invalid bucketId",
+			wantCode:    "bad_request",
+		},
+		"OtherError": {
+			err:         errors.New("boom"),
+			wantMessage: "boom",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := Warning(ReasonCannotCreate, tc.err)
+			if e.Type != event.TypeWarning || e.Reason != ReasonCannotCreate {
+				t.Errorf("Warning(...): want %s %s, got %s %s", event.TypeWarning, ReasonCannotCreate, e.Type, e.Reason)
+			}
+			if diff := cmp.Diff(tc.wantMessage, e.Message); diff != "" {
+				t.Errorf("Warning(...): message -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantCode, e.Annotations[AnnotationB2ErrorCode]); diff != "" {
+				t.Errorf("Warning(...): %s annotation -want, +got:\n%s", AnnotationB2ErrorCode, diff)
+			}
+		})
+	}
+}
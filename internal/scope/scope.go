@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scope lets one controller reconcile both the cluster scoped managed
+// resources of the backblaze.crossplane.io group and their namespaced
+// counterparts in the backblaze.m.crossplane.io groups, which share the same
+// parameters and observations.
+package scope
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+	policyv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/policy/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/user/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errFmtCredentialsNamespace = "ProviderConfig %q reads its credentials from namespace %q, but resources in namespace %q may only use credentials in their own namespace"
+)
+
+// ProviderNamespace is the namespace the ProviderConfigs of cluster scoped
+// resources are read from.
+const ProviderNamespace = "crossplane-system"
+
+// defaultProviderConfig is the ProviderConfig used by resources that don't
+// reference one.
+const defaultProviderConfig = "default"
+
+// A Scope is the scope of the managed resources a controller reconciles.
+type Scope int
+
+// Scopes of managed resources.
+const (
+	// Cluster scoped resources of the backblaze.crossplane.io group.
+	Cluster Scope = iota
+
+	// Namespaced resources of the backblaze.m.crossplane.io groups.
+	Namespaced
+)
+
+// ControllerName returns the name of the controller that reconciles
+// resources of the supplied kind, such as "bucket", in this scope.
+func (s Scope) ControllerName(kind string) string {
+	if s == Namespaced {
+		return "namespaced-" + kind + "-controller"
+	}
+	return kind + "-controller"
+}
+
+// A Managed resource of either scope.
+type Managed interface {
+	resource.Object
+	resource.Conditioned
+
+	GetManagementPolicies() xpv1.ManagementPolicies
+}
+
+// A Bucket of either scope.
+type Bucket interface {
+	Managed
+
+	GetBucketName() string
+	GetForProvider() *backblazev1.BucketParameters
+	GetAtProvider() *backblazev1.BucketObservation
+}
+
+// A User of either scope.
+type User interface {
+	Managed
+
+	GetKeyName() string
+	GetForProvider() *backblazev1.UserParameters
+	GetAtProvider() *backblazev1.UserObservation
+}
+
+// A Policy of either scope.
+type Policy interface {
+	Managed
+
+	GetPolicyName() string
+	GetForProvider() *backblazev1.PolicyParameters
+	GetAtProvider() *backblazev1.PolicyObservation
+}
+
+// NewBucket returns an empty Bucket of this scope.
+func (s Scope) NewBucket() Bucket {
+	if s == Namespaced {
+		return &bucketv1beta1.Bucket{}
+	}
+	return &backblazev1.Bucket{}
+}
+
+// NewUser returns an empty User of this scope.
+func (s Scope) NewUser() User {
+	if s == Namespaced {
+		return &userv1beta1.User{}
+	}
+	return &backblazev1.User{}
+}
+
+// NewPolicy returns an empty Policy of this scope.
+func (s Scope) NewPolicy() Policy {
+	if s == Namespaced {
+		return &policyv1beta1.Policy{}
+	}
+	return &backblazev1.Policy{}
+}
+
+// ManagementPolicies returns the management policies of the supplied
+// resource. Cluster scoped resources also honour their deletion policy, and
+// only honour management policies if they are enabled. Namespaced resources
+// have no deletion policy, so theirs are always honoured.
+func ManagementPolicies(enabled bool, mg Managed) managed.ManagementPoliciesChecker {
+	if o, ok := mg.(resource.Orphanable); ok {
+		return managed.NewLegacyManagementPoliciesResolver(enabled, mg.GetManagementPolicies(), o.GetDeletionPolicy())
+	}
+	return managed.NewManagementPoliciesResolver(true, mg.GetManagementPolicies())
+}
+
+// ObjectKey returns the key of the named resource that the supplied resource
+// references. Namespaced resources can only reference resources in their own
+// namespace.
+func ObjectKey(mg client.Object, name string) client.ObjectKey {
+	return client.ObjectKey{Namespace: mg.GetNamespace(), Name: name}
+}
+
+// GetProviderConfig returns the ProviderConfig referenced by the supplied
+// managed resource. Cluster scoped resources use ProviderConfigs in the
+// ProviderNamespace. Namespaced resources use those in their own namespace,
+// which must also read their credentials from that namespace.
+func GetProviderConfig(ctx context.Context, c client.Client, mg client.Object) (*apisv1beta1.ProviderConfig, error) {
+	key := client.ObjectKey{Namespace: ProviderNamespace, Name: defaultProviderConfig}
+	switch r := mg.(type) {
+	case resource.TypedProviderConfigReferencer:
+		key.Namespace = mg.GetNamespace()
+		if ref := r.GetProviderConfigReference(); ref != nil {
+			key.Name = ref.Name
+		}
+	case resource.ProviderConfigReferencer:
+		if ref := r.GetProviderConfigReference(); ref != nil {
+			key.Name = ref.Name
+		}
+	}
+
+	pc := &apisv1beta1.ProviderConfig{}
+	if err := c.Get(ctx, key, pc); err != nil {
+		return nil, err
+	}
+
+	if ns := mg.GetNamespace(); ns != "" {
+		if ref := pc.Spec.Credentials.SecretRef; ref != nil && ref.Namespace != ns {
+			return nil, errors.Errorf(errFmtCredentialsNamespace, pc.GetName(), ref.Namespace, ns)
+		}
+	}
+	return pc, nil
+}
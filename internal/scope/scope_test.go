@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/rossigee/provider-backblaze/apis"
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+)
+
+func providerConfig(namespace, name, secretNamespace string) *apisv1beta1.ProviderConfig {
+	pc := &apisv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	pc.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+	pc.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Namespace: secretNamespace, Name: "creds"},
+	}
+	return pc
+}
+
+func TestGetProviderConfig(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		providerConfig(ProviderNamespace, "default", ProviderNamespace),
+		providerConfig(ProviderNamespace, "other", ProviderNamespace),
+		providerConfig("team-a", "default", "team-a"),
+		providerConfig("team-a", "borrowed", ProviderNamespace),
+	).Build()
+
+	tests := map[string]struct {
+		mg      client.Object
+		want    client.ObjectKey
+		wantErr bool
+	}{
+		"ClusterDefault": {
+			mg:   &backblazev1.Bucket{},
+			want: client.ObjectKey{Namespace: ProviderNamespace, Name: "default"},
+		},
+		"ClusterReferenced": {
+			mg: &backblazev1.Bucket{Spec: backblazev1.BucketSpec{
+				ProviderConfigReference: &xpv1.Reference{Name: "other"},
+			}},
+			want: client.ObjectKey{Namespace: ProviderNamespace, Name: "other"},
+		},
+		"NamespacedDefault": {
+			mg:   &bucketv1beta1.Bucket{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}},
+			want: client.ObjectKey{Namespace: "team-a", Name: "default"},
+		},
+		"NamespacedNotFound": {
+			mg:      &bucketv1beta1.Bucket{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b"}},
+			wantErr: true,
+		},
+		"NamespacedCredentialsInOtherNamespace": {
+			mg: &bucketv1beta1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+				Spec: bucketv1beta1.BucketSpec{
+					ProviderConfigReference: &xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "borrowed"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pc, err := GetProviderConfig(context.Background(), c, tc.mg)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("GetProviderConfig(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, client.ObjectKeyFromObject(pc)); diff != "" {
+				t.Errorf("GetProviderConfig(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestManagementPolicies(t *testing.T) {
+	observeOnly := xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	keep := xpv1.ManagementPolicies{
+		xpv1.ManagementActionObserve, xpv1.ManagementActionCreate,
+		xpv1.ManagementActionUpdate, xpv1.ManagementActionLateInitialize,
+	}
+
+	tests := map[string]struct {
+		enabled         bool
+		mg              Managed
+		wantInvalid     bool
+		wantDelete      bool
+		wantOnlyObserve bool
+	}{
+		"ClusterDefault": {
+			mg:         &backblazev1.Bucket{Spec: backblazev1.BucketSpec{DeletionPolicy: xpv1.DeletionDelete}},
+			wantDelete: true,
+		},
+		"ClusterOrphaned": {
+			mg: &backblazev1.Bucket{Spec: backblazev1.BucketSpec{DeletionPolicy: xpv1.DeletionOrphan}},
+		},
+		"ClusterPoliciesRejectedUnlessEnabled": {
+			mg: &backblazev1.Bucket{Spec: backblazev1.BucketSpec{
+				DeletionPolicy:     xpv1.DeletionDelete,
+				ManagementPolicies: observeOnly,
+			}},
+			wantInvalid: true,
+		},
+		"ClusterObserveOnly": {
+			enabled: true,
+			mg: &backblazev1.Bucket{Spec: backblazev1.BucketSpec{
+				DeletionPolicy:     xpv1.DeletionDelete,
+				ManagementPolicies: observeOnly,
+			}},
+			wantOnlyObserve: true,
+		},
+		"NamespacedDefault": {
+			mg:         &bucketv1beta1.Bucket{Spec: bucketv1beta1.BucketSpec{ManagementPolicies: xpv1.ManagementPolicies{xpv1.ManagementActionAll}}},
+			wantDelete: true,
+		},
+		"NamespacedKept": {
+			mg: &bucketv1beta1.Bucket{Spec: bucketv1beta1.BucketSpec{ManagementPolicies: keep}},
+		},
+		"NamespacedObserveOnly": {
+			mg:              &bucketv1beta1.Bucket{Spec: bucketv1beta1.BucketSpec{ManagementPolicies: observeOnly}},
+			wantOnlyObserve: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := ManagementPolicies(tc.enabled, tc.mg)
+			err := p.Validate()
+			if gotInvalid := err != nil; gotInvalid != tc.wantInvalid {
+				t.Fatalf("Validate(): want error %t, got %v", tc.wantInvalid, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := p.ShouldDelete(); got != tc.wantDelete {
+				t.Errorf("ShouldDelete(): want %t, got %t", tc.wantDelete, got)
+			}
+			if got := p.ShouldOnlyObserve(); got != tc.wantOnlyObserve {
+				t.Errorf("ShouldOnlyObserve(): want %t, got %t", tc.wantOnlyObserve, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation helps the admission webhooks validate updates without
+// blocking changes to resources that were admitted under older rules.
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SkipUpdate returns true if an update from old to o needn't be validated,
+// because o is being deleted or its spec hasn't changed. Such updates only
+// change metadata or status, for example to remove a finalizer, and must be
+// allowed even if the resource would no longer be admitted.
+func SkipUpdate(old, o client.Object) bool {
+	if o.GetDeletionTimestamp() != nil {
+		return true
+	}
+	was, err := runtime.DefaultUnstructuredConverter.ToUnstructured(old)
+	if err != nil {
+		return false
+	}
+	is, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return false
+	}
+	return equality.Semantic.DeepEqual(was["spec"], is["spec"])
+}
+
+// NewErrors returns those of errs that aren't also in old, being the errors
+// of the resource before it was updated. Errors are matched by type, field
+// and detail, so an update isn't rejected for a problem it didn't introduce.
+func NewErrors(old, errs field.ErrorList) field.ErrorList {
+	type key struct {
+		typ    field.ErrorType
+		field  string
+		detail string
+	}
+	existing := make(map[key]bool, len(old))
+	for _, e := range old {
+		existing[key{e.Type, e.Field, e.Detail}] = true
+	}
+
+	var fresh field.ErrorList
+	for _, e := range errs {
+		if !existing[key{e.Type, e.Field, e.Detail}] {
+			fresh = append(fresh, e)
+		}
+	}
+	return fresh
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validation
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+)
+
+func TestSkipUpdate(t *testing.T) {
+	tests := map[string]struct {
+		update func(b *backblazev1.Bucket)
+		want   bool
+	}{
+		"SpecUnchanged": {
+			update: func(b *backblazev1.Bucket) { b.SetFinalizers(nil) },
+			want:   true,
+		},
+		"Deleting": {
+			update: func(b *backblazev1.Bucket) {
+				b.SetDeletionTimestamp(&metav1.Time{})
+				b.Spec.ForProvider.BucketType = "allPublic"
+			},
+			want: true,
+		},
+		"SpecChanged": {
+			update: func(b *backblazev1.Bucket) { b.Spec.ForProvider.BucketType = "allPublic" },
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := &backblazev1.Bucket{Spec: backblazev1.BucketSpec{ForProvider: backblazev1.BucketParameters{BucketName: "my-bucket", BucketType: "allPrivate"}}}
+			old.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+			b := old.DeepCopy()
+			tc.update(b)
+			if got := SkipUpdate(old, b); got != tc.want {
+				t.Errorf("SkipUpdate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	name := field.NewPath("spec", "name")
+	size := field.NewPath("spec", "size")
+	old := field.ErrorList{field.Invalid(name, "a b", "must not contain spaces")}
+	errs := field.ErrorList{
+		field.Invalid(name, "a  b", "must not contain spaces"),
+		field.Required(size, "size is required"),
+	}
+
+	var got []string
+	for _, e := range NewErrors(old, errs) {
+		got = append(got, e.Field)
+	}
+	if diff := cmp.Diff([]string{"spec.size"}, got); diff != "" {
+		t.Errorf("NewErrors(...): -want, +got:\n%s", diff)
+	}
+}
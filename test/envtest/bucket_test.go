@@ -0,0 +1,1068 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newBucket(name string, dp xpv1.DeletionPolicy, bdp backblazev1.BucketDeletionPolicy) *backblazev1.Bucket {
+	return &backblazev1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.BucketSpec{
+			DeletionPolicy: dp,
+			ForProvider: backblazev1.BucketParameters{
+				BucketName:           name,
+				BucketType:           "allPrivate",
+				Region:               "us-west-001",
+				BucketDeletionPolicy: bdp,
+				LifecycleRules: []backblazev1.LifecycleRule{{
+					FileNamePrefix:           "tmp/",
+					DaysFromHidingToDeleting: ptr.To(1),
+				}},
+			},
+		},
+	}
+}
+
+// importBucket sets the external name of cr so that it manages the existing
+// bucket of the same name.
+func importBucket(cr *backblazev1.Bucket) *backblazev1.Bucket {
+	meta.SetExternalName(cr, cr.Spec.ForProvider.BucketName)
+	return cr
+}
+
+// callsFor counts the calls to op whose first argument is name.
+func callsFor(op, name string) int {
+	n := 0
+	for _, c := range backend.Calls() {
+		if c.Op == op && len(c.Args) > 0 && c.Args[0] == name {
+			n++
+		}
+	}
+	return n
+}
+
+func TestBucketCreateUpdateDelete(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-lifecycle"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+
+	waitForFinalizer(t, cr)
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if _, ok := backend.Bucket(name); !ok {
+		t.Fatalf("bucket %s was not created in B2", name)
+	}
+	if got := meta.GetExternalName(cr); got != name {
+		t.Errorf("external name: want %q, got %q", name, got)
+	}
+	if got := cr.Status.AtProvider.BucketName; got != name {
+		t.Errorf("status.atProvider.bucketName: want %q, got %q", name, got)
+	}
+	if got := cr.Status.AtProvider.Region; got != "us-west-001" {
+		t.Errorf("status.atProvider.region: want us-west-001, got %q", got)
+	}
+
+	// Lifecycle rules can't be set at creation, so they are applied as an
+	// update to the new bucket.
+	if bk, _ := backend.Bucket(name); len(bk.LifecycleRules) != 1 {
+		t.Errorf("lifecycle rules: want 1, got %v", bk.LifecycleRules)
+	}
+
+	// Updating the spec must update the bucket in place.
+	cr.Spec.ForProvider.BucketType = "allPublic"
+	if err := k8s.Update(ctx, cr); err != nil {
+		t.Fatalf("cannot update Bucket: %v", err)
+	}
+	waitFor(t, "bucket type to be updated", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return bk.Type == "allPublic", nil
+	})
+	if n := callsFor("CreateBucket", name); n != 1 {
+		t.Errorf("CreateBucket calls: want 1, got %d", n)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %s still exists in B2 after deletion", name)
+	}
+	if n := callsFor("DeleteAllObjectsInBucket", name); n != 0 {
+		t.Errorf("DeleteAllObjectsInBucket calls with DeleteIfEmpty: want 0, got %d", n)
+	}
+}
+
+func TestBucketDeleteAll(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-delete-all"
+
+	// The bucket already exists and holds objects.
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPrivate", Region: "us-west-001", Objects: 3})
+
+	cr := importBucket(newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteAll))
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if n := callsFor("DeleteAllObjectsInBucket", name); n != 1 {
+		t.Errorf("DeleteAllObjectsInBucket calls: want 1, got %d", n)
+	}
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %s still exists in B2 after deletion", name)
+	}
+}
+
+func TestBucketDeleteIfEmptyWithObjects(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-not-empty"
+
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPrivate", Region: "us-west-001", Objects: 1})
+
+	cr := importBucket(newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty))
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+
+	// The bucket can't be deleted, so the finalizer must hold the resource.
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "DeleteError")
+	if !meta.FinalizerExists(cr, "finalizer.managedresource.crossplane.io") {
+		t.Error("finalizer was removed although the bucket could not be deleted")
+	}
+	if _, ok := backend.Bucket(name); !ok {
+		t.Errorf("bucket %s was deleted although it was not empty", name)
+	}
+}
+
+func TestBucketOrphan(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-orphan"
+
+	cr := newBucket(name, xpv1.DeletionOrphan, backblazev1.DeleteAll)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if _, ok := backend.Bucket(name); !ok {
+		t.Errorf("orphaned bucket %s was deleted from B2", name)
+	}
+	if n := callsFor("DeleteBucket", name) + callsFor("DeleteAllObjectsInBucket", name); n != 0 {
+		t.Errorf("delete calls for orphaned bucket: want 0, got %d", n)
+	}
+}
+
+func TestBucketCreateError(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-create-error"
+
+	backend.SetError("CreateBucket", errors.New("boom"))
+	defer backend.SetError("CreateBucket", nil)
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "CreateError")
+
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %s exists although creation failed", name)
+	}
+}
+
+// touch changes an annotation on obj so that its controller reconciles it
+// without waiting for the next poll. An object that has already gone needs no
+// reconcile.
+func touch(t *testing.T, obj client.Object) {
+	t.Helper()
+	ctx := context.Background()
+	waitFor(t, "touch "+obj.GetName(), func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return kerrors.IsNotFound(err), err
+		}
+		meta.AddAnnotations(obj, map[string]string{"envtest/touched": time.Now().Format(time.RFC3339Nano)})
+		err := k8s.Update(ctx, obj)
+		return err == nil || kerrors.IsNotFound(err), err
+	})
+}
+
+func TestBucketDriftCorrected(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-drift"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	// Someone makes the bucket public and adds a CORS rule in the console.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) {
+		bk.Type = "allPublic"
+		bk.LifecycleRules = nil
+		bk.CORSRules = []clients.B2CORSRule{{CorsRuleName: "console", AllowedOrigins: []string{"*"}, AllowedOperations: []string{"s3_get"}}}
+	})
+	drifted, _ := backend.Bucket(name)
+
+	touch(t, cr)
+	waitFor(t, "drift to be corrected", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return bk.Type == "allPrivate" && len(bk.CORSRules) == 0 && len(bk.LifecycleRules) == 1, nil
+	})
+
+	waitFor(t, "status to report the new revision", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		return cr.Status.AtProvider.Revision > drifted.Revision, nil
+	})
+	if got := cr.Status.AtProvider.BucketID; got != drifted.ID {
+		t.Errorf("status.atProvider.bucketId: want %q, got %q", drifted.ID, got)
+	}
+}
+
+func TestBucketUpdateConflict(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-conflict"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+
+	backend.SetError("UpdateBucket", &clients.B2Error{Status: 409, Code: clients.B2ErrorCodeConflict, Message: "ifRevisionIs does not match"})
+	defer backend.SetError("UpdateBucket", nil)
+
+	backend.ModifyBucket(name, func(bk *fake.Bucket) { bk.Type = "allPublic" })
+	touch(t, cr)
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+
+	// Once the conflict clears the controller retries without waiting for
+	// the next poll.
+	backend.SetError("UpdateBucket", nil)
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+	if bk, _ := backend.Bucket(name); bk.Type != "allPrivate" {
+		t.Errorf("bucket type: want allPrivate, got %s", bk.Type)
+	}
+}
+
+func TestBucketRegionImmutable(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-region-immutable"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	cr.Spec.ForProvider.Region = "eu-central-003"
+	err := k8s.Update(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("changing region: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "region is immutable") {
+		t.Errorf("changing region: want immutability message, got %v", err)
+	}
+}
+
+func TestBucketRegionMismatch(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-region-mismatch"
+
+	// The bucket already exists, but in a different region.
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPrivate", Region: "eu-central-003"})
+
+	cr := importBucket(newBucket(name, xpv1.DeletionOrphan, backblazev1.DeleteIfEmpty))
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+
+	if c := cr.GetCondition(xpv1.TypeSynced); !strings.Contains(c.Message, "eu-central-003") {
+		t.Errorf("Synced message does not name the actual region: %s", c.Message)
+	}
+	if got := cr.Status.AtProvider.Region; got != "eu-central-003" {
+		t.Errorf("status.atProvider.region: want eu-central-003, got %q", got)
+	}
+	if n := callsFor("UpdateBucket", name); n != 0 {
+		t.Errorf("UpdateBucket calls for bucket in the wrong region: want 0, got %d", n)
+	}
+}
+
+func TestBucketRegionUnset(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-region-unset"
+
+	// The bucket is outside the ProviderConfig's region, and has drifted.
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPublic", Region: "eu-central-003"})
+
+	cr := importBucket(newBucket(name, xpv1.DeletionOrphan, backblazev1.DeleteIfEmpty))
+	cr.Spec.ForProvider.Region = ""
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	t.Cleanup(func() { _ = k8s.Delete(ctx, cr) })
+
+	// A region that was never set isn't enforced, so the drift is corrected.
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+	if cr.Spec.ForProvider.Region != "" {
+		t.Errorf("spec.forProvider.region: want unset, got %q", cr.Spec.ForProvider.Region)
+	}
+	if got := cr.Status.AtProvider.Region; got != "eu-central-003" {
+		t.Errorf("status.atProvider.region: want eu-central-003, got %q", got)
+	}
+	if bk, _ := backend.Bucket(name); bk.Type != "allPrivate" {
+		t.Errorf("bucket type: want allPrivate, got %q", bk.Type)
+	}
+}
+
+func TestBucketImport(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-import"
+
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPublic", Region: "us-west-001"})
+	existing, _ := backend.Bucket(name)
+
+	cr := importBucket(newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty))
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+
+	if got := cr.Status.AtProvider.BucketID; got != existing.ID {
+		t.Errorf("status.atProvider.bucketId: want %q, got %q", existing.ID, got)
+	}
+	if n := callsFor("CreateBucket", name); n != 0 {
+		t.Errorf("CreateBucket calls for imported bucket: want 0, got %d", n)
+	}
+	// Once imported, the bucket is managed like any other.
+	if bk, _ := backend.Bucket(name); bk.Type != "allPrivate" {
+		t.Errorf("bucket type: want allPrivate, got %s", bk.Type)
+	}
+}
+
+func TestBucketNotManaged(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-not-managed"
+
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPublic", Region: "us-west-001"})
+
+	// Without an external name the resource must not adopt the bucket.
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteAll)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "NotManaged")
+	if c := cr.GetCondition(xpv1.TypeReady); !strings.Contains(c.Message, "crossplane.io/external-name") {
+		t.Errorf("Ready message does not explain how to import the bucket: %s", c.Message)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+
+	bk, ok := backend.Bucket(name)
+	if !ok {
+		t.Fatalf("unmanaged bucket %s was deleted", name)
+	}
+	if bk.Type != "allPublic" {
+		t.Errorf("unmanaged bucket was updated: type %s", bk.Type)
+	}
+	if n := callsFor("UpdateBucket", name) + callsFor("DeleteAllObjectsInBucket", name); n != 0 {
+		t.Errorf("calls changing the unmanaged bucket: want 0, got %d", n)
+	}
+}
+
+func TestBucketUpgradeAdoptsManagedBucket(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-upgrade"
+
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPublic", Region: "us-west-001"})
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "NotManaged")
+
+	// Earlier releases recorded only the bucket name in the status of the
+	// buckets they managed, and no external name.
+	waitFor(t, "status of an earlier release", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Status.AtProvider = backblazev1.BucketObservation{BucketName: name}
+		return k8s.Status().Update(ctx, cr) == nil, nil
+	})
+
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+	if got := meta.GetExternalName(cr); got != name {
+		t.Errorf("external name: want %q, got %q", name, got)
+	}
+	if bk, _ := backend.Bucket(name); bk.Type != "allPrivate" {
+		t.Errorf("bucket type: want allPrivate, got %q", bk.Type)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("adopted bucket %s was not deleted", name)
+	}
+}
+
+func TestBucketNameTaken(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-name-taken"
+
+	backend.TakeBucketName(name)
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "NameTaken")
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+	if n := callsFor("DeleteBucket", name); n != 0 {
+		t.Errorf("DeleteBucket calls for a bucket owned by another account: want 0, got %d", n)
+	}
+}
+
+func TestBucketObserveOnly(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-observe-only"
+
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPublic", Region: "eu-central-003"})
+	existing, _ := backend.Bucket(name)
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteAll)
+	cr.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if got := cr.Status.AtProvider.BucketID; got != existing.ID {
+		t.Errorf("status.atProvider.bucketId: want %q, got %q", existing.ID, got)
+	}
+	if got := cr.Status.AtProvider.BucketType; got != "allPublic" {
+		t.Errorf("status.atProvider.bucketType: want allPublic, got %q", got)
+	}
+	if got := cr.Status.AtProvider.Region; got != "eu-central-003" {
+		t.Errorf("status.atProvider.region: want eu-central-003, got %q", got)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if bk, ok := backend.Bucket(name); !ok || bk.Type != "allPublic" {
+		t.Errorf("observed bucket was changed or deleted: %+v", bk)
+	}
+	if n := callsFor("UpdateBucket", name) + callsFor("DeleteBucket", name) + callsFor("DeleteAllObjectsInBucket", name); n != 0 {
+		t.Errorf("calls changing the observed bucket: want 0, got %d", n)
+	}
+}
+
+func TestBucketObserveOnlyNotFound(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-observe-only-missing"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "NotFound")
+
+	if n := callsFor("CreateBucket", name); n != 0 {
+		t.Errorf("CreateBucket calls with managementPolicies [Observe]: want 0, got %d", n)
+	}
+}
+
+func TestBucketInfo(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-info"
+
+	pc := &apisv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "envtest-tagged", Namespace: providerNamespace},
+		Spec: apisv1beta1.ProviderConfigSpec{
+			Credentials: defaultProviderCredentials(),
+			DefaultBucketInfo: &apisv1beta1.DefaultBucketInfo{
+				Tags:                map[string]string{"cost-center": "shared"},
+				ClusterName:         "envtest",
+				IncludeResourceName: true,
+			},
+		},
+	}
+	if err := k8s.Create(ctx, pc); err != nil {
+		t.Fatalf("cannot create ProviderConfig: %v", err)
+	}
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ProviderConfigReference = &xpv1.Reference{Name: pc.GetName()}
+	cr.Spec.ForProvider.BucketInfo = map[string]string{
+		"cost-center":   "team-a",
+		"Cache-Control": "max-age=3600",
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+
+	want := map[string]string{
+		"cost-center":        "team-a",
+		"Cache-Control":      "max-age=3600",
+		"crossplane-cluster": "envtest",
+		"crossplane-name":    name,
+	}
+	bk, _ := backend.Bucket(name)
+	if !reflect.DeepEqual(bk.Info, want) {
+		t.Errorf("bucket info: want %v, got %v", want, bk.Info)
+	}
+	if got := cr.Status.AtProvider.BucketInfo["cost-center"]; got != "team-a" {
+		t.Errorf("status.atProvider.bucketInfo[cost-center]: want team-a, got %q", got)
+	}
+
+	// An entry changed in the console is restored.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) { bk.Info["cost-center"] = "someone-else" })
+	touch(t, cr)
+	waitFor(t, "bucket info drift to be corrected", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return bk.Info["cost-center"] == "team-a", nil
+	})
+
+	// An entry added in the console is kept when desired entries change.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) { bk.Info["owner"] = "console" })
+	waitFor(t, "Cache-Control to be changed", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.BucketInfo["Cache-Control"] = "no-cache"
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitFor(t, "Cache-Control to be updated", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return bk.Info["Cache-Control"] == "no-cache", nil
+	})
+	if bk, _ := backend.Bucket(name); bk.Info["owner"] != "console" {
+		t.Errorf("bucket info set in the console: want it kept, got %v", bk.Info)
+	}
+}
+
+func TestBucketInfoTooManyEntries(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-info-too-many"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ForProvider.BucketInfo = map[string]string{}
+	for i := range 9 {
+		cr.Spec.ForProvider.BucketInfo[fmt.Sprintf("key-%d", i)] = "value"
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+
+	// Entries added in the console are kept, so the merged bucket info
+	// would have more entries than B2 allows.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) {
+		bk.Info["owner"] = "console"
+		bk.Info["team"] = "console"
+	})
+	updates := callsFor("UpdateBucket", name)
+	touch(t, cr)
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+	if c := cr.GetCondition(xpv1.TypeSynced); !strings.Contains(c.Message, "11 bucketInfo entries") {
+		t.Errorf("Synced condition: want a message about 11 entries, got %q", c.Message)
+	}
+	if n := callsFor("UpdateBucket", name); n != updates {
+		t.Errorf("UpdateBucket calls: want %d, got %d", updates, n)
+	}
+}
+
+func TestBucketInfoCleared(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-info-cleared"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ForProvider.BucketInfo = map[string]string{"Cache-Control": "max-age=3600"}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+	if got := cr.Status.AtProvider.ManagedBucketInfo; !reflect.DeepEqual(got, []string{"Cache-Control"}) {
+		t.Errorf("status.atProvider.managedBucketInfo: want [Cache-Control], got %v", got)
+	}
+
+	// Removing the last entry removes it from the bucket too.
+	waitFor(t, "bucket info to be cleared", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.BucketInfo = nil
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitFor(t, "bucket info to be removed", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return len(bk.Info) == 0, nil
+	})
+	waitFor(t, "managed bucket info to be forgotten", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		return len(cr.Status.AtProvider.ManagedBucketInfo) == 0, nil
+	})
+
+	// Bucket info set elsewhere is then left alone.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) { bk.Info = map[string]string{"owner": "console"} })
+	touch(t, cr)
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+	if bk, _ := backend.Bucket(name); bk.Info["owner"] != "console" {
+		t.Errorf("bucket info set in the console: want it kept, got %v", bk.Info)
+	}
+}
+
+func TestBucketDefaultEncryption(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-sse"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ForProvider.DefaultServerSideEncryption = &backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeB2}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+
+	want := backblazev1.ServerSideEncryption{Mode: backblazev1.SSEModeB2, Algorithm: "AES256"}
+	if got := cr.Status.AtProvider.DefaultServerSideEncryption; got == nil || *got != want {
+		t.Errorf("status.atProvider.defaultServerSideEncryption: want %+v, got %+v", want, got)
+	}
+
+	// Encryption disabled in the console is re-enabled.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) { bk.Encryption = nil })
+	touch(t, cr)
+	waitFor(t, "default encryption to be restored", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return bk.Encryption != nil && bk.Encryption.Mode != nil && *bk.Encryption.Mode == backblazev1.SSEModeB2, nil
+	})
+}
+
+func TestBucketFileLock(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-file-lock"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteAll)
+	cr.Spec.ForProvider.FileLockEnabled = true
+	cr.Spec.ForProvider.DefaultRetention = &backblazev1.DefaultRetention{
+		Mode:   backblazev1.RetentionModeGovernance,
+		Period: backblazev1.RetentionPeriod{Duration: 30, Unit: "days"},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+
+	bk, _ := backend.Bucket(name)
+	if !bk.FileLockEnabled {
+		t.Error("file lock was not enabled when the bucket was created")
+	}
+	if bk.Retention == nil || bk.Retention.Mode == nil || *bk.Retention.Mode != backblazev1.RetentionModeGovernance {
+		t.Errorf("default retention: want governance, got %+v", bk.Retention)
+	}
+	if !cr.Status.AtProvider.FileLockEnabled {
+		t.Error("status.atProvider.fileLockEnabled: want true")
+	}
+	if got := cr.Status.AtProvider.DefaultRetention; got == nil || *got != *cr.Spec.ForProvider.DefaultRetention {
+		t.Errorf("status.atProvider.defaultRetention: want %+v, got %+v", cr.Spec.ForProvider.DefaultRetention, got)
+	}
+
+	// File lock can't be disabled once the bucket exists.
+	cr.Spec.ForProvider.FileLockEnabled = false
+	cr.Spec.ForProvider.DefaultRetention = nil
+	err := k8s.Update(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("disabling file lock: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "fileLockEnabled can only be set when the bucket is created") {
+		t.Errorf("disabling file lock: want immutability message, got %v", err)
+	}
+}
+
+func TestBucketRetentionRequiresFileLock(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cr := newBucket("envtest-bucket-retention-no-lock", xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ForProvider.DefaultRetention = &backblazev1.DefaultRetention{
+		Mode:   backblazev1.RetentionModeCompliance,
+		Period: backblazev1.RetentionPeriod{Duration: 1, Unit: "years"},
+	}
+	err := k8s.Create(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("creating Bucket: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "defaultRetention requires fileLockEnabled") {
+		t.Errorf("creating Bucket: want fileLockEnabled message, got %v", err)
+	}
+}
+
+func TestBucketFileLockMismatch(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-file-lock-mismatch"
+
+	// An imported bucket without file lock can't gain it.
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPrivate", Region: "us-west-001"})
+
+	cr := importBucket(newBucket(name, xpv1.DeletionOrphan, backblazev1.DeleteIfEmpty))
+	cr.Spec.ForProvider.FileLockEnabled = true
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+	if c := cr.GetCondition(xpv1.TypeSynced); !strings.Contains(c.Message, "file lock") {
+		t.Errorf("Synced message: want file lock mismatch, got %q", c.Message)
+	}
+}
+
+func TestBucketDeleteAllWithLockedObjects(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-locked-objects"
+
+	backend.PutBucket(fake.Bucket{Name: name, Type: "allPrivate", Region: "us-west-001", FileLockEnabled: true, Objects: 3, LockedObjects: 2})
+
+	cr := importBucket(newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteAll))
+	cr.Spec.ForProvider.FileLockEnabled = true
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+
+	// No objects may be purged while some are still locked.
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "ObjectsLocked")
+	if c := cr.GetCondition(xpv1.TypeReady); !strings.Contains(c.Message, `"locked-0"`) {
+		t.Errorf("Ready message: want the first locked object, got %q", c.Message)
+	}
+	if n := callsFor("DeleteAllObjectsInBucket", name); n != 0 {
+		t.Errorf("DeleteAllObjectsInBucket calls with locked objects: want 0, got %d", n)
+	}
+	if bk, _ := backend.Bucket(name); bk.Objects != 3 {
+		t.Errorf("objects: want 3, got %d", bk.Objects)
+	}
+
+	// Once the retention expires the bucket is emptied and deleted.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) { bk.LockedObjects = 0 })
+	touch(t, cr)
+	waitForGone(t, cr)
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %s still exists in B2 after deletion", name)
+	}
+}
+
+func TestBucketReplication(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-replication"
+
+	dest := newBucket(name+"-dest", xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	key := newUser(name+"-key", xpv1.DeletionDelete)
+	for _, obj := range []client.Object{dest, key} {
+		if err := k8s.Create(ctx, obj); err != nil {
+			t.Fatalf("cannot create %s: %v", obj.GetName(), err)
+		}
+	}
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ForProvider.ReplicationConfiguration = &backblazev1.ReplicationConfiguration{
+		AsSource: &backblazev1.ReplicationSource{
+			SourceApplicationKeyRef: &xpv1.Reference{Name: key.GetName()},
+			Rules: []backblazev1.ReplicationRule{{
+				Name:                 "backups",
+				DestinationBucketRef: &xpv1.Reference{Name: dest.GetName()},
+				FileNamePrefix:       "backups/",
+			}},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionTrue, "ReconcileSuccess")
+	waitForCondition(t, dest, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	waitForCondition(t, key, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	// References are resolved into the spec.
+	src := cr.Spec.ForProvider.ReplicationConfiguration.AsSource
+	if got := ptr.Deref(src.SourceApplicationKeyID, ""); got != key.Status.AtProvider.ApplicationKeyID {
+		t.Errorf("sourceApplicationKeyId: want %q, got %q", key.Status.AtProvider.ApplicationKeyID, got)
+	}
+	if got := ptr.Deref(src.Rules[0].DestinationBucketID, ""); got != dest.Status.AtProvider.BucketID {
+		t.Errorf("destinationBucketId: want %q, got %q", dest.Status.AtProvider.BucketID, got)
+	}
+
+	bk, _ := backend.Bucket(name)
+	if bk.Replication == nil || bk.Replication.AsReplicationSource == nil || len(bk.Replication.AsReplicationSource.ReplicationRules) != 1 {
+		t.Fatalf("replication: want one rule, got %+v", bk.Replication)
+	}
+	want := backblazev1.ReplicationRuleObservation{
+		Name:                "backups",
+		DestinationBucketID: dest.Status.AtProvider.BucketID,
+		FileNamePrefix:      "backups/",
+		Priority:            1,
+		State:               backblazev1.ReplicationRuleEnabled,
+	}
+	if r := cr.Status.AtProvider.Replication; r == nil || len(r.Rules) != 1 || r.Rules[0] != want {
+		t.Errorf("status.atProvider.replication: want rule %+v, got %+v", want, r)
+	}
+
+	// A rule paused in the console is enabled again.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) {
+		bk.Replication.AsReplicationSource.ReplicationRules[0].IsEnabled = false
+	})
+	touch(t, cr)
+	waitFor(t, "replication rule to be enabled", func() (bool, error) {
+		bk, _ := backend.Bucket(name)
+		return bk.Replication.AsReplicationSource.ReplicationRules[0].IsEnabled, nil
+	})
+}
+
+func TestBucketReplicationUnresolved(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-replication-unresolved"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	cr.Spec.ForProvider.ReplicationConfiguration = &backblazev1.ReplicationConfiguration{
+		AsSource: &backblazev1.ReplicationSource{
+			SourceApplicationKeyID: ptr.To("K005000000000000"),
+			Rules: []backblazev1.ReplicationRule{{
+				Name:                 "missing",
+				DestinationBucketRef: &xpv1.Reference{Name: "envtest-bucket-does-not-exist"},
+			}},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+	if c := cr.GetCondition(xpv1.TypeSynced); !strings.Contains(c.Message, "cannot resolve references") {
+		t.Errorf("Synced message: want reference error, got %q", c.Message)
+	}
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %s was created before its references were resolved", name)
+	}
+}
+
+func TestBucketValidation(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		update func(p *backblazev1.BucketParameters)
+		want   string
+	}{
+		"NameTooShort": {
+			update: func(p *backblazev1.BucketParameters) { p.BucketName = "abc" },
+			want:   "bucketName must be 6 to 63 letters, digits and hyphens",
+		},
+		"NameInvalidCharacters": {
+			update: func(p *backblazev1.BucketParameters) { p.BucketName = "envtest_bucket.name" },
+			want:   "bucketName must be 6 to 63 letters, digits and hyphens",
+		},
+		"NameReservedPrefix": {
+			update: func(p *backblazev1.BucketParameters) { p.BucketName = "b2-envtest-bucket" },
+			want:   "bucketName must not start with b2-",
+		},
+		"LifecycleUploadingToHidingZero": {
+			update: func(p *backblazev1.BucketParameters) { p.LifecycleRules[0].DaysFromUploadingToHiding = ptr.To(0) },
+			want:   "daysFromUploadingToHiding must be at least 1",
+		},
+		"LifecycleHidingToDeletingZero": {
+			update: func(p *backblazev1.BucketParameters) { p.LifecycleRules[0].DaysFromHidingToDeleting = ptr.To(0) },
+			want:   "daysFromHidingToDeleting must be at least 1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := newBucket("envtest-bucket-validation", xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+			tc.update(&cr.Spec.ForProvider)
+			err := k8s.Create(ctx, cr)
+			if !kerrors.IsInvalid(err) {
+				t.Fatalf("creating Bucket: want Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("creating Bucket: want %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestBucketNameImmutable(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-name-immutable"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	cr.Spec.ForProvider.BucketName = name + "-renamed"
+	err := k8s.Update(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("changing bucketName: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "bucketName is immutable") {
+		t.Errorf("changing bucketName: want immutability message, got %v", err)
+	}
+
+	if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+		t.Fatalf("cannot get Bucket: %v", err)
+	}
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestBucketRegionPresenceImmutable(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		name   string
+		before string
+		after  string
+	}{
+		"Removed": {
+			name:   "envtest-bucket-region-removed",
+			before: "us-west-001",
+		},
+		"Added": {
+			name:  "envtest-bucket-region-added",
+			after: "us-west-001",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := newBucket(tc.name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+			cr.Spec.ForProvider.Region = tc.before
+			if err := k8s.Create(ctx, cr); err != nil {
+				t.Fatalf("cannot create Bucket: %v", err)
+			}
+			waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+			cr.Spec.ForProvider.Region = tc.after
+			err := k8s.Update(ctx, cr)
+			if !kerrors.IsInvalid(err) {
+				t.Fatalf("changing region from %q to %q: want Invalid error, got %v", tc.before, tc.after, err)
+			}
+			if !strings.Contains(err.Error(), "region is immutable") {
+				t.Errorf("changing region: want immutability message, got %v", err)
+			}
+
+			if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+				t.Fatalf("cannot get Bucket: %v", err)
+			}
+			if err := k8s.Delete(ctx, cr); err != nil {
+				t.Fatalf("cannot delete Bucket: %v", err)
+			}
+			waitForGone(t, cr)
+		})
+	}
+}
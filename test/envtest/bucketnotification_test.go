@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// notificationRule returns the named notification rule of the named bucket.
+func notificationRule(bucketName, ruleName string) (clients.B2NotificationRule, bool) {
+	bk, _ := backend.Bucket(bucketName)
+	for _, r := range bk.NotificationRules {
+		if r.Name == ruleName {
+			return r, true
+		}
+	}
+	return clients.B2NotificationRule{}, false
+}
+
+func TestBucketNotificationLifecycle(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-notification"
+	signingSecret := "abcdefghijklmnopqrstuvwxyz012345"
+
+	bucket := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	headers := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-headers", Namespace: testNamespace},
+		StringData: map[string]string{"Authorization": "Bearer token"},
+	}
+	signing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-signing", Namespace: testNamespace},
+		StringData: map[string]string{"secret": signingSecret},
+	}
+	cr := &backblazev1.BucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.BucketNotificationSpec{
+			DeletionPolicy: xpv1.DeletionDelete,
+			ForProvider: backblazev1.BucketNotificationParameters{
+				BucketRef:        &xpv1.Reference{Name: name},
+				EventTypes:       []backblazev1.NotificationEventType{"b2:ObjectCreated:*"},
+				ObjectNamePrefix: "uploads/",
+				URL:              "https://example.com/hook",
+				CustomHeadersSecretRef: &xpv1.SecretReference{
+					Name:      headers.GetName(),
+					Namespace: testNamespace,
+				},
+				SigningSecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: signing.GetName(), Namespace: testNamespace},
+					Key:             "secret",
+				},
+			},
+		},
+	}
+
+	if err := k8s.Create(ctx, bucket); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, bucket, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	// A rule created in the console must survive this resource's changes.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) {
+		bk.NotificationRules = []clients.B2NotificationRule{{Name: "console-rule", EventTypes: []string{"b2:ObjectDeleted:*"}, IsEnabled: true}}
+	})
+
+	for _, s := range []*corev1.Secret{headers, signing} {
+		if err := k8s.Create(ctx, s); err != nil {
+			t.Fatalf("cannot create Secret: %v", err)
+		}
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create BucketNotification: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	rule, ok := notificationRule(name, name)
+	if !ok {
+		t.Fatalf("notification rule %q was not created", name)
+	}
+	if rule.TargetConfiguration.URL != "https://example.com/hook" || rule.ObjectNamePrefix != "uploads/" || !rule.IsEnabled {
+		t.Errorf("unexpected rule: %+v", rule)
+	}
+	if got := rule.TargetConfiguration.HmacSha256SigningSecret; got != signingSecret {
+		t.Errorf("signing secret: want %q, got %q", signingSecret, got)
+	}
+	wantHeaders := []clients.B2NotificationHeader{{Name: "Authorization", Value: "Bearer token"}}
+	if got := rule.TargetConfiguration.CustomHeaders; len(got) != 1 || got[0] != wantHeaders[0] {
+		t.Errorf("custom headers: want %v, got %v", wantHeaders, got)
+	}
+	if _, ok := notificationRule(name, "console-rule"); !ok {
+		t.Error("console-rule was removed when the managed rule was created")
+	}
+	if got := ptr.Deref(cr.Spec.ForProvider.BucketID, ""); got != bucket.Status.AtProvider.BucketID {
+		t.Errorf("spec.forProvider.bucketId: want %q, got %q", bucket.Status.AtProvider.BucketID, got)
+	}
+
+	// A URL changed in the console is restored.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) {
+		for i := range bk.NotificationRules {
+			if bk.NotificationRules[i].Name == name {
+				bk.NotificationRules[i].TargetConfiguration.URL = "https://attacker.example.com"
+			}
+		}
+	})
+	touch(t, cr)
+	waitFor(t, "notification URL to be restored", func() (bool, error) {
+		r, _ := notificationRule(name, name)
+		return r.TargetConfiguration.URL == "https://example.com/hook", nil
+	})
+
+	// A rule suspended by B2 is reported.
+	backend.ModifyBucket(name, func(bk *fake.Bucket) {
+		for i := range bk.NotificationRules {
+			if bk.NotificationRules[i].Name == name {
+				bk.NotificationRules[i].IsSuspended = true
+				bk.NotificationRules[i].SuspensionReason = "webhook returned 500"
+			}
+		}
+	})
+	touch(t, cr)
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "Suspended")
+	if !cr.Status.AtProvider.IsSuspended {
+		t.Error("status.atProvider.isSuspended: want true")
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete BucketNotification: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if _, ok := notificationRule(name, name); ok {
+		t.Errorf("notification rule %q still exists after deletion", name)
+	}
+	if _, ok := notificationRule(name, "console-rule"); !ok {
+		t.Error("console-rule was removed when the managed rule was deleted")
+	}
+}
+
+func TestBucketNotificationInvalidSigningSecret(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-notification-bad-secret"
+
+	backend.PutBucket(fake.Bucket{Name: name, ID: "fakebucketbadsecret", Type: "allPrivate", Region: "us-west-001"})
+
+	signing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		StringData: map[string]string{"secret": "too-short"},
+	}
+	if err := k8s.Create(ctx, signing); err != nil {
+		t.Fatalf("cannot create Secret: %v", err)
+	}
+
+	cr := &backblazev1.BucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.BucketNotificationSpec{
+			ForProvider: backblazev1.BucketNotificationParameters{
+				BucketID:   ptr.To("fakebucketbadsecret"),
+				EventTypes: []backblazev1.NotificationEventType{"b2:ObjectCreated:*"},
+				URL:        "https://example.com/hook",
+				SigningSecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: name, Namespace: testNamespace},
+					Key:             "secret",
+				},
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create BucketNotification: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+
+	if n := callsFor("SetBucketNotificationRules", "fakebucketbadsecret"); n != 0 {
+		t.Errorf("SetBucketNotificationRules calls: want 0, got %d", n)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	backblazev2 "github.com/rossigee/provider-backblaze/apis/backblaze/v2"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUserV2ReadAsV1(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-v2"
+	backend.PutBucket(fake.Bucket{Name: name, ID: "fakebucketv2", Type: "allPrivate", Region: clients.DefaultRegion})
+
+	cr := &backblazev2.User{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev2.UserSpec{
+			ProviderConfigReference: &xpv1.Reference{Name: "default"},
+			WriteConnectionSecretToReference: &xpv1.SecretReference{
+				Name:      name + "-creds",
+				Namespace: testNamespace,
+			},
+			ForProvider: backblazev2.UserParameters{
+				KeyName: name,
+				Capabilities: backblazev2.UserCapabilities{
+					Preset: ptr.To(backblazev1.CapabilityPresetReadOnly),
+					Grants: []backblazev1.Capability{"writeFiles"},
+				},
+				BucketIDs: []string{"fakebucketv2"},
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create v2 User: %v", err)
+	}
+	t.Cleanup(func() { _ = k8s.Delete(ctx, cr) })
+
+	// The controllers reconcile v1 Users, so a v2 User only becomes Ready if
+	// it converts to v1 and its status converts back.
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	v1 := &backblazev1.User{}
+	if err := k8s.Get(ctx, client.ObjectKey{Name: name}, v1); err != nil {
+		t.Fatalf("cannot get User as v1: %v", err)
+	}
+	p := v1.Spec.ForProvider
+	if p.CapabilityPreset == nil || *p.CapabilityPreset != backblazev1.CapabilityPresetReadOnly {
+		t.Errorf("v1 capabilityPreset: want %q, got %v", backblazev1.CapabilityPresetReadOnly, p.CapabilityPreset)
+	}
+	if diff := cmp.Diff([]backblazev1.Capability{"writeFiles"}, p.Capabilities); diff != "" {
+		t.Errorf("v1 capabilities: -want, +got:\n%s", diff)
+	}
+	if p.BucketID != nil {
+		t.Errorf("v1 bucketId: want nil, got %q", *p.BucketID)
+	}
+	if got := v1.Status.AtProvider.BucketID; got == nil || *got != "fakebucketv2" {
+		t.Errorf("v1 status.atProvider.bucketId: want %q, got %v", "fakebucketv2", got)
+	}
+	if v1.Spec.ProviderConfigReference == nil || v1.Spec.ProviderConfigReference.Name != "default" {
+		t.Errorf("v1 providerConfigReference: want default, got %v", v1.Spec.ProviderConfigReference)
+	}
+}
+
+func TestUserV1BucketIDReadAsV2(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-v1-bucket-id"
+	backend.PutBucket(fake.Bucket{Name: name, ID: "fakebucketv1", Type: "allPrivate", Region: clients.DefaultRegion})
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.BucketID = ptr.To("fakebucketv1")
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	t.Cleanup(func() { _ = k8s.Delete(ctx, cr) })
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	v2 := &backblazev2.User{}
+	if err := k8s.Get(ctx, client.ObjectKey{Name: name}, v2); err != nil {
+		t.Fatalf("cannot get User as v2: %v", err)
+	}
+	if diff := cmp.Diff([]string{"fakebucketv1"}, v2.Spec.ForProvider.BucketIDs); diff != "" {
+		t.Errorf("v2 bucketIds: -want, +got:\n%s", diff)
+	}
+	if got := v2.GetAnnotations()[backblazev2.AnnotationV1BucketID]; got != "fakebucketv1" {
+		t.Errorf("v2 %s annotation: want %q, got %q", backblazev2.AnnotationV1BucketID, "fakebucketv1", got)
+	}
+
+	// The v1 bucketId survives being stored as v2.
+	if err := k8s.Get(ctx, client.ObjectKey{Name: name}, cr); err != nil {
+		t.Fatalf("cannot get User as v1: %v", err)
+	}
+	if got := cr.Spec.ForProvider.BucketID; got == nil || *got != "fakebucketv1" {
+		t.Errorf("v1 bucketId: want %q, got %v", "fakebucketv1", got)
+	}
+	if len(cr.Spec.ForProvider.BucketIDs) != 0 {
+		t.Errorf("v1 bucketIds: want none, got %v", cr.Spec.ForProvider.BucketIDs)
+	}
+	if _, ok := cr.GetAnnotations()[backblazev2.AnnotationV1BucketID]; ok {
+		t.Errorf("v1 User has the %s annotation", backblazev2.AnnotationV1BucketID)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestBucketEvents(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-events"
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if e := waitForEvent(t, cr, recorder.ReasonCreated); e.Type != corev1.EventTypeNormal {
+		t.Errorf("%s event: want type %s, got %s", recorder.ReasonCreated, corev1.EventTypeNormal, e.Type)
+	}
+
+	waitFor(t, "bucketType to be changed", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.BucketType = "allPublic"
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitForEvent(t, cr, recorder.ReasonUpdated)
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+	waitForEvent(t, cr, recorder.ReasonDeleted)
+}
+
+func TestUserEvents(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-events"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	keyID := cr.Status.AtProvider.ApplicationKeyID
+	if e := waitForEvent(t, cr, recorder.ReasonCreated); !strings.Contains(e.Note, keyID) {
+		t.Errorf("%s event: want a note naming %s, got %q", recorder.ReasonCreated, keyID, e.Note)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+	waitForEvent(t, cr, recorder.ReasonDeleted)
+}
+
+func TestUserCreateErrorEvent(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-create-error-event"
+
+	backend.SetError("CreateApplicationKey", &clients.B2Error{Status: 403, Code: "cap_exceeded", Message: "Usage cap exceeded"})
+	defer backend.SetError("CreateApplicationKey", nil)
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	t.Cleanup(func() { _ = k8s.Delete(ctx, cr) })
+
+	e := waitForEvent(t, cr, recorder.ReasonCannotCreate)
+	if e.Type != corev1.EventTypeWarning {
+		t.Errorf("%s event: want type %s, got %s", recorder.ReasonCannotCreate, corev1.EventTypeWarning, e.Type)
+	}
+	if !strings.HasPrefix(e.Note, "B2 error code cap_exceeded: ") {
+		t.Errorf("%s event: want a note starting with the B2 error code, got %q", recorder.ReasonCannotCreate, e.Note)
+	}
+}
+
+// newNotification returns a BucketNotification of a rule of the supplied
+// bucket, which is deleted with it.
+func newNotification(name, bucketID string) *backblazev1.BucketNotification {
+	return &backblazev1.BucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.BucketNotificationSpec{
+			DeletionPolicy: xpv1.DeletionDelete,
+			ForProvider: backblazev1.BucketNotificationParameters{
+				BucketID:   ptr.To(bucketID),
+				EventTypes: []backblazev1.NotificationEventType{"b2:ObjectCreated:*"},
+				URL:        "https://example.com/hook",
+			},
+		},
+	}
+}
+
+func TestBucketNotificationEvents(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-notification-events"
+
+	backend.PutBucket(fake.Bucket{Name: name, ID: "fakebucketevents", Type: "allPrivate", Region: "us-west-001"})
+
+	cr := newNotification(name, "fakebucketevents")
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create BucketNotification: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if e := waitForEvent(t, cr, recorder.ReasonCreated); !strings.Contains(e.Note, name) {
+		t.Errorf("%s event: want a note naming %s, got %q", recorder.ReasonCreated, name, e.Note)
+	}
+
+	waitFor(t, "url to be changed", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.URL = "https://example.com/other"
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitForEvent(t, cr, recorder.ReasonUpdated)
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete BucketNotification: %v", err)
+	}
+	waitForGone(t, cr)
+	waitForEvent(t, cr, recorder.ReasonDeleted)
+}
+
+func TestBucketNotificationCreateErrorEvent(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-notification-create-error-event"
+
+	backend.PutBucket(fake.Bucket{Name: name, ID: "fakebucketcreateerror", Type: "allPrivate", Region: "us-west-001"})
+	backend.SetError("SetBucketNotificationRules", &clients.B2Error{Status: 400, Code: "bad_request", Message: "Invalid webhook URL"})
+	defer backend.SetError("SetBucketNotificationRules", nil)
+
+	cr := newNotification(name, "fakebucketcreateerror")
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create BucketNotification: %v", err)
+	}
+	t.Cleanup(func() { _ = k8s.Delete(ctx, cr) })
+
+	e := waitForEvent(t, cr, recorder.ReasonCannotCreate)
+	if e.Type != corev1.EventTypeWarning {
+		t.Errorf("%s event: want type %s, got %s", recorder.ReasonCannotCreate, corev1.EventTypeWarning, e.Type)
+	}
+	if !strings.HasPrefix(e.Note, "B2 error code bad_request: ") {
+		t.Errorf("%s event: want a note starting with the B2 error code, got %q", recorder.ReasonCannotCreate, e.Note)
+	}
+}
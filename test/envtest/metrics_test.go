@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestManagedResourceMetrics(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-bucket-metrics"
+	kind := backblazev1.BucketGroupKind.String()
+
+	before := managedResourceCount(t, kind, corev1.ConditionTrue, corev1.ConditionTrue)
+
+	cr := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	t.Cleanup(func() { _ = k8s.Delete(ctx, cr) })
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	// The collector reads from the manager's cache, which may lag behind the
+	// API server.
+	waitFor(t, fmt.Sprintf("%s Ready and Synced count above %v", kind, before), func() (bool, error) {
+		return managedResourceCount(t, kind, corev1.ConditionTrue, corev1.ConditionTrue) > before, nil
+	})
+}
+
+// managedResourceCount returns the backblaze_managed_resources gauge for the
+// supplied kind and condition statuses.
+func managedResourceCount(t *testing.T, kind string, ready, synced corev1.ConditionStatus) float64 {
+	t.Helper()
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(managedResources)
+	mfs, err := r.Gather()
+	if err != nil {
+		t.Fatalf("cannot gather managed resource metrics: %v", err)
+	}
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			l := map[string]string{}
+			for _, lp := range m.GetLabel() {
+				l[lp.GetName()] = lp.GetValue()
+			}
+			if l["kind"] == kind && l["ready"] == string(ready) && l["synced"] == string(synced) {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	bucketv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/bucket/v1beta1"
+	policyv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/policy/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-backblaze/apis/namespaced/user/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newNamespacedBucket(name string) *bucketv1beta1.Bucket {
+	return &bucketv1beta1.Bucket{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tenantNamespace},
+		Spec: bucketv1beta1.BucketSpec{
+			ForProvider: backblazev1.BucketParameters{
+				BucketName:           name,
+				BucketType:           "allPrivate",
+				Region:               "us-west-001",
+				BucketDeletionPolicy: backblazev1.DeleteIfEmpty,
+			},
+		},
+	}
+}
+
+func TestNamespacedBucketCreateDelete(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-namespaced-bucket"
+
+	cr := newNamespacedBucket(name)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+
+	waitForFinalizer(t, cr)
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if got := cr.Spec.ProviderConfigReference; got == nil || got.Kind != "ProviderConfig" || got.Name != "default" {
+		t.Errorf("spec.providerConfigRef: want default ProviderConfig, got %v", got)
+	}
+	b, ok := backend.Bucket(name)
+	if !ok {
+		t.Fatalf("bucket %q was not created in B2", name)
+	}
+	if got := cr.Status.AtProvider.BucketID; got != b.ID {
+		t.Errorf("status.atProvider.bucketId: want %q, got %q", b.ID, got)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %q still exists in B2 after deletion", name)
+	}
+}
+
+func TestNamespacedBucketKept(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-namespaced-bucket-kept"
+
+	// Without the Delete management policy the bucket outlives the Bucket,
+	// as it would with the Orphan deletion policy of a cluster scoped one.
+	cr := newNamespacedBucket(name)
+	cr.Spec.ManagementPolicies = xpv1.ManagementPolicies{
+		xpv1.ManagementActionObserve, xpv1.ManagementActionCreate,
+		xpv1.ManagementActionUpdate, xpv1.ManagementActionLateInitialize,
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+	if _, ok := backend.Bucket(name); !ok {
+		t.Errorf("kept bucket %q was deleted from B2", name)
+	}
+	if n := callsFor("DeleteBucket", name); n != 0 {
+		t.Errorf("DeleteBucket calls for kept bucket: want 0, got %d", n)
+	}
+}
+
+func TestNamespacedUserConnectionSecret(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-namespaced-user"
+
+	b := newNamespacedBucket(name)
+	if err := k8s.Create(ctx, b); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+
+	cr := &userv1beta1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tenantNamespace},
+		Spec: userv1beta1.UserSpec{
+			WriteConnectionSecretToReference: &xpv1.LocalSecretReference{Name: name + "-conn"},
+			ForProvider: backblazev1.UserParameters{
+				KeyName:      name,
+				Capabilities: []backblazev1.Capability{"listFiles", "readFiles"},
+				BucketRefs:   []xpv1.Reference{{Name: name}},
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+
+	// The referenced Bucket is resolved in the namespace of the User.
+	waitForCondition(t, b, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	keyID := cr.Status.AtProvider.ApplicationKeyID
+	key, ok := backend.Key(keyID)
+	if !ok {
+		t.Fatalf("application key %q was not created in B2", keyID)
+	}
+	if len(key.BucketIDs) != 1 || key.BucketIDs[0] != b.Status.AtProvider.BucketID {
+		t.Errorf("application key bucketIds: want [%s], got %v", b.Status.AtProvider.BucketID, key.BucketIDs)
+	}
+
+	s := &corev1.Secret{}
+	if err := k8s.Get(ctx, client.ObjectKey{Namespace: tenantNamespace, Name: name + "-conn"}, s); err != nil {
+		t.Fatalf("cannot get connection Secret: %v", err)
+	}
+	if got := string(s.Data[clients.SecretKeyApplicationKeyID]); got != keyID {
+		t.Errorf("connection secret %s: want %q, got %q", clients.SecretKeyApplicationKeyID, keyID, got)
+	}
+	if got := string(s.Data[clients.SecretKeyApplicationKey]); got != key.ApplicationKey {
+		t.Errorf("connection secret %s: want %q, got %q", clients.SecretKeyApplicationKey, key.ApplicationKey, got)
+	}
+	if c := metav1.GetControllerOf(s); c == nil || c.UID != cr.GetUID() {
+		t.Errorf("connection secret controller: want User %s, got %v", cr.GetUID(), c)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+	if _, ok := backend.Key(keyID); ok {
+		t.Errorf("application key %q still exists in B2 after deletion", keyID)
+	}
+	if err := k8s.Delete(ctx, b); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, b)
+}
+
+func TestNamespacedUserValidation(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		spec userv1beta1.UserSpec
+		want string
+	}{
+		"WriteSecretToRef": {
+			spec: userv1beta1.UserSpec{
+				WriteConnectionSecretToReference: &xpv1.LocalSecretReference{Name: "creds"},
+				ForProvider: backblazev1.UserParameters{
+					Capabilities:     []backblazev1.Capability{"listBuckets"},
+					WriteSecretToRef: &xpv1.SecretReference{Name: "creds", Namespace: testNamespace},
+				},
+			},
+			want: "forProvider.writeSecretToRef is not supported by namespaced Users",
+		},
+		"NoConnectionSecret": {
+			spec: userv1beta1.UserSpec{
+				ForProvider: backblazev1.UserParameters{
+					Capabilities: []backblazev1.Capability{"listBuckets"},
+				},
+			},
+			want: "writeConnectionSecretToRef",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &userv1beta1.User{
+				ObjectMeta: metav1.ObjectMeta{Name: "envtest-namespaced-user-invalid", Namespace: tenantNamespace},
+				Spec:       tc.spec,
+			}
+			err := k8s.Create(ctx, cr)
+			if !kerrors.IsInvalid(err) {
+				t.Fatalf("creating User: want Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("creating User: want %q message, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestNamespacedPolicy(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-namespaced-policy"
+
+	b := newNamespacedBucket(bucketName)
+	if err := k8s.Create(ctx, b); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, b, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	cr := &policyv1beta1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName, Namespace: tenantNamespace},
+		Spec: policyv1beta1.PolicySpec{
+			ForProvider: allowAll(bucketName),
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	doc := cr.Status.AtProvider.PolicyDocument
+	if got, _ := backend.Policy(bucketName); got != doc {
+		t.Errorf("bucket policy: want %s, got %s", doc, got)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if err := k8s.Delete(ctx, b); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, b)
+}
+
+func TestNamespacedProviderConfigCredentialsElsewhere(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-namespaced-borrowed"
+
+	// A ProviderConfig in the tenant namespace may not read the provider's
+	// credentials from another namespace.
+	pc := &apisv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tenantNamespace},
+		Spec: apisv1beta1.ProviderConfigSpec{
+			BackblazeRegion: clients.DefaultRegion,
+			Credentials:     providerCredentials(providerNamespace),
+		},
+	}
+	if err := k8s.Create(ctx, pc); err != nil {
+		t.Fatalf("cannot create ProviderConfig: %v", err)
+	}
+
+	cr := newNamespacedBucket(name)
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: name}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "ClientError")
+	if c := cr.GetCondition(xpv1.TypeReady); !strings.Contains(c.Message, "may only use credentials in their own namespace") {
+		t.Errorf("Ready message: want credentials namespace error, got %q", c.Message)
+	}
+	if _, ok := backend.Bucket(name); ok {
+		t.Errorf("bucket %q was created in B2 with another namespace's credentials", name)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, cr)
+}
@@ -0,0 +1,478 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// allowAll returns parameters that let everyone do anything with the named
+// bucket and its objects.
+func allowAll(bucket string) backblazev1.PolicyParameters {
+	return backblazev1.PolicyParameters{
+		Bucket: ptr.To(bucket),
+		Statements: []backblazev1.PolicyStatement{{
+			Effect:     backblazev1.PolicyEffectAllow,
+			Principals: []string{"*"},
+			Actions:    []string{"s3:*"},
+			Resources: []backblazev1.PolicyResource{
+				{Bucket: ptr.To(bucket)},
+				{Bucket: ptr.To(bucket), Prefix: ptr.To("")},
+			},
+		}},
+	}
+}
+
+func TestPolicyBucketPolicy(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-policy-bucket"
+
+	backend.PutBucket(fake.Bucket{Name: bucketName, ID: "fakepolicybucket", Type: "allPrivate", Region: "us-west-001"})
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "envtest-policy-bucket-policy"},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: allowAll(bucketName),
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if c := cr.GetCondition(xpv1.TypeSynced); c.Status != corev1.ConditionTrue {
+		t.Errorf("Synced: want True, got %s (%s)", c.Status, c.Message)
+	}
+
+	if got := cr.Status.AtProvider.PolicyName; got != cr.GetName() {
+		t.Errorf("status.atProvider.policyName: want %q, got %q", cr.GetName(), got)
+	}
+	doc := cr.Status.AtProvider.PolicyDocument
+	if !strings.Contains(doc, "arn:aws:s3:::envtest-policy-bucket/*") {
+		t.Errorf("status.atProvider.policyDocument does not grant the bucket: %s", doc)
+	}
+	if got, _ := backend.Policy(bucketName); got != doc {
+		t.Errorf("bucket policy: want %s, got %s", doc, got)
+	}
+
+	// An equivalent policy, as B2 might return it, is not replaced.
+	equivalent := `{"Statement":[{"Resource":["arn:aws:s3:::envtest-policy-bucket/*","arn:aws:s3:::envtest-policy-bucket"],"Action":"S3:*","Effect":"Allow","Principal":"*"}],"Version":"2012-10-17"}`
+	if err := backend.PutBucketPolicy(ctx, bucketName, equivalent); err != nil {
+		t.Fatalf("cannot put bucket policy: %v", err)
+	}
+	puts, gets := callsFor("PutBucketPolicy", bucketName), callsFor("GetBucketPolicy", bucketName)
+	touch(t, cr)
+	waitFor(t, "bucket policy to be observed", func() (bool, error) {
+		return callsFor("GetBucketPolicy", bucketName) > gets, nil
+	})
+	if got := callsFor("PutBucketPolicy", bucketName); got != puts {
+		t.Errorf("PutBucketPolicy calls after an equivalent policy was observed: want %d, got %d", puts, got)
+	}
+
+	// A changed policy is restored, and the change is recorded in an event.
+	changed := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::envtest-policy-bucket/*"}]}`
+	if err := backend.PutBucketPolicy(ctx, bucketName, changed); err != nil {
+		t.Fatalf("cannot put bucket policy: %v", err)
+	}
+	touch(t, cr)
+	waitFor(t, "bucket policy to be restored", func() (bool, error) {
+		got, _ := backend.Policy(bucketName)
+		return got == doc, nil
+	})
+	waitFor(t, "UpdatedBucketPolicy event", func() (bool, error) {
+		events := &eventsv1.EventList{}
+		if err := k8s.List(ctx, events); err != nil {
+			return false, err
+		}
+		for _, e := range events.Items {
+			if e.Regarding.Name == cr.GetName() && e.Reason == "UpdatedBucketPolicy" && strings.Contains(e.Note, `+ "arn:aws:s3:::envtest-policy-bucket",`) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestPolicyAllowBucket(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-policy-allow-bucket"
+
+	backend.PutBucket(fake.Bucket{Name: bucketName, ID: "fakepolicyallowbucket", Type: "allPrivate", Region: "us-west-001"})
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: backblazev1.PolicyParameters{
+				AllowBucket: ptr.To(bucketName),
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+
+	// The generated statement has no principal, so it is never put.
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "InvalidPolicy")
+	if msg := cr.GetCondition(xpv1.TypeSynced).Message; !strings.Contains(msg, "a Principal is required") {
+		t.Errorf("Synced message %q does not report the missing principal", msg)
+	}
+	if n := callsFor("PutBucketPolicy", bucketName); n != 0 {
+		t.Errorf("PutBucketPolicy calls for an allowBucket Policy: want 0, got %d", n)
+	}
+	if got, ok := backend.Policy(bucketName); ok {
+		t.Errorf("bucket policy: want none, got %s", got)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestPolicyStatements(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-policy-statements"
+
+	bucket := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, bucket); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: backblazev1.PolicyParameters{
+				Statements: []backblazev1.PolicyStatement{{
+					Actions:    []string{"s3:GetObject"},
+					Principals: []string{"*"},
+					Resources: []backblazev1.PolicyResource{{
+						BucketRef: &xpv1.Reference{Name: name},
+						Prefix:    ptr.To("public/"),
+					}},
+				}},
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if got := ptr.Deref(cr.Spec.ForProvider.Statements[0].Resources[0].Bucket, ""); got != name {
+		t.Errorf("statements[0].resources[0].bucket: want %q, got %q", name, got)
+	}
+	if doc := cr.Status.AtProvider.PolicyDocument; !strings.Contains(doc, `"arn:aws:s3:::`+name+`/public/*"`) {
+		t.Errorf("status.atProvider.policyDocument does not grant the prefix: %s", doc)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if err := k8s.Delete(ctx, bucket); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, bucket)
+}
+
+func TestPolicyValidation(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		params backblazev1.PolicyParameters
+		want   string
+	}{
+		"AllowBucketAndRawPolicy": {
+			params: backblazev1.PolicyParameters{
+				AllowBucket: ptr.To("envtest-policy-bucket"),
+				RawPolicy:   ptr.To(`{"Version":"2012-10-17","Statement":[]}`),
+			},
+			want: "only one of allowBucket, rawPolicy and statements may be set",
+		},
+		"NothingSet": {
+			params: backblazev1.PolicyParameters{Bucket: ptr.To("envtest-policy-bucket")},
+			want:   "one of allowBucket, rawPolicy, statements, publicReadPrefix, readOnlyBucket or denyInsecureTransport must be set",
+		},
+		"TemplateWithoutBucket": {
+			params: backblazev1.PolicyParameters{DenyInsecureTransport: ptr.To(true)},
+			want:   "publicReadPrefix and denyInsecureTransport require bucket, bucketRef, allowBucket or readOnlyBucket",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &backblazev1.Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: "envtest-policy-validation"},
+				Spec:       backblazev1.PolicySpec{ForProvider: tc.params},
+			}
+			err := k8s.Create(ctx, cr)
+			if !kerrors.IsInvalid(err) {
+				t.Fatalf("creating Policy: want Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("creating Policy: want %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestPolicyInvalidDocument(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "envtest-policy-invalid-document"},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: backblazev1.PolicyParameters{
+				Bucket:    ptr.To("envtest-policy-bucket"),
+				RawPolicy: ptr.To(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutBucketPolicy","Resource":"arn:aws:s3:::other-bucket"}]}`),
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "InvalidPolicy")
+	msg := cr.GetCondition(xpv1.TypeSynced).Message
+	for _, want := range []string{`action "s3:PutBucketPolicy" is not supported`, `not in bucket "envtest-policy-bucket"`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Synced message %q does not contain %q", msg, want)
+		}
+	}
+	if got := cr.Status.AtProvider.PolicyName; got != "" {
+		t.Errorf("status.atProvider.policyName: want empty, got %q", got)
+	}
+}
+
+func TestPolicyTemplates(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-policy-templates"
+
+	bucket := newBucket(name, xpv1.DeletionDelete, backblazev1.DeleteIfEmpty)
+	if err := k8s.Create(ctx, bucket); err != nil {
+		t.Fatalf("cannot create Bucket: %v", err)
+	}
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: backblazev1.PolicyParameters{
+				BucketRef:             &xpv1.Reference{Name: name},
+				PublicReadPrefix:      ptr.To("downloads/"),
+				DenyInsecureTransport: ptr.To(true),
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if got := ptr.Deref(cr.Spec.ForProvider.Bucket, ""); got != name {
+		t.Errorf("spec.forProvider.bucket: want %q, got %q", name, got)
+	}
+	got, ok := backend.Policy(name)
+	if !ok {
+		t.Fatalf("bucket %q has no policy", name)
+	}
+	for _, want := range []string{`"Sid": "PublicRead"`, `"arn:aws:s3:::` + name + `/downloads/*"`, `"Sid": "DenyInsecureTransport"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("bucket policy does not contain %s: %s", want, got)
+		}
+	}
+
+	// Deleting the Policy revokes the public read access it granted.
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if got, ok := backend.Policy(name); ok {
+		t.Errorf("bucket policy after the Policy was deleted: want none, got %s", got)
+	}
+	if err := k8s.Delete(ctx, bucket); err != nil {
+		t.Fatalf("cannot delete Bucket: %v", err)
+	}
+	waitForGone(t, bucket)
+}
+
+func TestPolicyDelete(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-policy-delete"
+
+	backend.PutBucket(fake.Bucket{Name: bucketName, ID: "fakepolicydelete", Type: "allPrivate", Region: "us-west-001"})
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: backblazev1.PolicyParameters{
+				Bucket:           ptr.To(bucketName),
+				PublicReadPrefix: ptr.To("public/"),
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if _, ok := backend.Policy(bucketName); !ok {
+		t.Fatalf("bucket %q has no policy", bucketName)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if got, ok := backend.Policy(bucketName); ok {
+		t.Errorf("bucket policy after the Policy was deleted: want none, got %s", got)
+	}
+	waitForEvent(t, cr, recorder.ReasonDeleted)
+}
+
+func TestPolicyDeleteReplaced(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-policy-replaced"
+
+	backend.PutBucket(fake.Bucket{Name: bucketName, ID: "fakepolicyreplaced", Type: "allPrivate", Region: "us-west-001"})
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: allowAll(bucketName),
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	// A policy put on the bucket by someone else isn't deleted with the
+	// Policy that it replaced.
+	replaced := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::envtest-policy-replaced/*"}]}`
+	if err := backend.PutBucketPolicy(ctx, bucketName, replaced); err != nil {
+		t.Fatalf("cannot put bucket policy: %v", err)
+	}
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if n := callsFor("DeleteBucketPolicy", bucketName); n != 0 {
+		t.Errorf("DeleteBucketPolicy calls for a replaced policy: want 0, got %d", n)
+	}
+	if got, _ := backend.Policy(bucketName); got != replaced {
+		t.Errorf("replaced bucket policy: want %s, got %s", replaced, got)
+	}
+}
+
+func TestPolicyObserveOnly(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-policy-observe"
+
+	backend.PutBucket(fake.Bucket{Name: bucketName, ID: "fakepolicyobserve", Type: "allPrivate", Region: "us-west-001"})
+	live := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::envtest-policy-observe/*"}]}`
+	if err := backend.PutBucketPolicy(ctx, bucketName, live); err != nil {
+		t.Fatalf("cannot put bucket policy: %v", err)
+	}
+	puts := callsFor("PutBucketPolicy", bucketName)
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: backblazev1.PolicySpec{
+			ForProvider: allowAll(bucketName),
+		},
+	}
+	cr.SetManagementPolicies(xpv1.ManagementPolicies{xpv1.ManagementActionObserve})
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+
+	// The live policy differs, but may only be observed.
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "NotUpToDate")
+	if got := callsFor("PutBucketPolicy", bucketName); got != puts {
+		t.Errorf("PutBucketPolicy calls for an observed Policy: want %d, got %d", puts, got)
+	}
+	if got, _ := backend.Policy(bucketName); got != live {
+		t.Errorf("observed bucket policy: want %s, got %s", live, got)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if got, _ := backend.Policy(bucketName); got != live {
+		t.Errorf("bucket policy after the observed Policy was deleted: want %s, got %s", live, got)
+	}
+}
+
+func TestPolicyOrphan(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	bucketName := "envtest-policy-orphan"
+
+	backend.PutBucket(fake.Bucket{Name: bucketName, ID: "fakepolicyorphan", Type: "allPrivate", Region: "us-west-001"})
+
+	cr := &backblazev1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+		Spec: backblazev1.PolicySpec{
+			DeletionPolicy: xpv1.DeletionOrphan,
+			ForProvider:    allowAll(bucketName),
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create Policy: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	doc := cr.Status.AtProvider.PolicyDocument
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete Policy: %v", err)
+	}
+	waitForGone(t, cr)
+	if n := callsFor("DeleteBucketPolicy", bucketName); n != 0 {
+		t.Errorf("DeleteBucketPolicy calls with the Orphan deletion policy: want 0, got %d", n)
+	}
+	if got, _ := backend.Policy(bucketName); got != doc {
+		t.Errorf("orphaned bucket policy: want %s, got %s", doc, got)
+	}
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/go-logr/logr"
+
+	"github.com/rossigee/provider-backblaze/apis"
+	apisv1beta1 "github.com/rossigee/provider-backblaze/apis/v1beta1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+	"github.com/rossigee/provider-backblaze/internal/controller/bucket"
+	"github.com/rossigee/provider-backblaze/internal/controller/bucketnotification"
+	"github.com/rossigee/provider-backblaze/internal/controller/policy"
+	"github.com/rossigee/provider-backblaze/internal/controller/user"
+	"github.com/rossigee/provider-backblaze/internal/features"
+	"github.com/rossigee/provider-backblaze/internal/metrics"
+	"github.com/rossigee/provider-backblaze/internal/scope"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// Suite configuration
+const (
+	// providerNamespace is where the controllers look for ProviderConfigs.
+	providerNamespace = "crossplane-system"
+	// testNamespace holds the Secrets written by the controllers.
+	testNamespace = "envtest"
+	// tenantNamespace holds namespaced managed resources, and the
+	// credentials and ProviderConfig they use.
+	tenantNamespace = "envtest-tenant"
+
+	waitTimeout  = 30 * time.Second
+	pollInterval = 100 * time.Millisecond
+)
+
+var (
+	// k8s is an uncached client for the envtest API server.
+	k8s client.Client
+	// backend is the fake Backblaze B2 account shared by every controller.
+	backend *fake.Backend
+	// managedResources counts managed resources in the manager's cache.
+	managedResources *metrics.ManagedResourceCollector
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run starts the API server and controllers, if envtest binaries are
+// available, then runs the tests. Tests call requireEnv to skip themselves
+// when the environment could not be started.
+func run(m *testing.M) int {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("KUBEBUILDER_ASSETS is not set - envtest suite will be skipped")
+		return m.Run()
+	}
+
+	// The webhook server logs through controller-runtime's global logger.
+	ctrl.SetLogger(logr.Discard())
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "package", "crds")},
+		ErrorIfCRDPathMissing: true,
+		// Point the CRDs' conversion webhooks at the manager's webhook
+		// server. Converters are registered with the webhook server, not
+		// the scheme.
+		WebhookInstallOptions: envtest.WebhookInstallOptions{IgnoreSchemeConvertible: true},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Printf("cannot start envtest: %v\n", err)
+		return 1
+	}
+	defer func() {
+		_ = env.Stop()
+	}()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		fmt.Printf("cannot add client-go types to scheme: %v\n", err)
+		return 1
+	}
+	if err := apis.AddToScheme(scheme); err != nil {
+		fmt.Printf("cannot add Backblaze APIs to scheme: %v\n", err)
+		return 1
+	}
+
+	wo := env.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    wo.LocalServingHost,
+			Port:    wo.LocalServingPort,
+			CertDir: wo.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		fmt.Printf("cannot create manager: %v\n", err)
+		return 1
+	}
+	if err := user.SetupUserWebhook(mgr); err != nil {
+		fmt.Printf("cannot set up User webhooks: %v\n", err)
+		return 1
+	}
+
+	managedResources = metrics.NewManagedResourceCollector(mgr.GetCache())
+
+	backend = fake.NewBackend()
+	flags := &feature.Flags{}
+	flags.Enable(features.EnableAlphaManagementPolicies)
+	o := controller.Options{Logger: logging.NewNopLogger(), Features: flags}
+	setups := []func(ctrl.Manager, controller.Options) error{
+		(&bucket.BucketReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn()}).SetupWithManager,
+		(&user.UserReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn()}).SetupWithManager,
+		(&policy.PolicyReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn()}).SetupWithManager,
+		(&bucketnotification.BucketNotificationReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn()}).SetupWithManager,
+		(&bucket.BucketReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn(), Scope: scope.Namespaced}).SetupWithManager,
+		(&user.UserReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn(), Scope: scope.Namespaced}).SetupWithManager,
+		(&policy.PolicyReconciler{Client: mgr.GetClient(), NewClientFn: backend.NewClientFn(), Scope: scope.Namespaced}).SetupWithManager,
+	}
+	for _, setup := range setups {
+		if err := setup(mgr, o); err != nil {
+			fmt.Printf("cannot set up controller: %v\n", err)
+			return 1
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Printf("manager stopped: %v\n", err)
+		}
+	}()
+
+	// Users are stored as v2, so nothing can write a v1 User until the
+	// conversion webhook is serving.
+	if err := waitForWebhookServer(ctx, mgr); err != nil {
+		fmt.Printf("webhook server did not start: %v\n", err)
+		return 1
+	}
+
+	k8s, err = client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Printf("cannot create client: %v\n", err)
+		return 1
+	}
+	if err := createFixtures(ctx); err != nil {
+		fmt.Printf("cannot create fixtures: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// waitForWebhookServer waits until the manager's webhook server accepts
+// connections.
+func waitForWebhookServer(ctx context.Context, mgr ctrl.Manager) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+	check := mgr.GetWebhookServer().StartedChecker()
+	for {
+		err := check(nil)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// createFixtures creates the namespaces, credentials and default
+// ProviderConfigs the controllers need.
+func createFixtures(ctx context.Context) error {
+	objs := []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}}
+	for _, ns := range []string{providerNamespace, tenantNamespace} {
+		objs = append(objs,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "backblaze-creds", Namespace: ns},
+				Data: map[string][]byte{
+					clients.SecretKeyApplicationKeyID: []byte("fake-key-id"),
+					clients.SecretKeyApplicationKey:   []byte("fake-key"),
+				},
+			},
+			&apisv1beta1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: ns},
+				Spec: apisv1beta1.ProviderConfigSpec{
+					BackblazeRegion: clients.DefaultRegion,
+					Credentials:     providerCredentials(ns),
+				},
+			},
+		)
+	}
+	for _, o := range objs {
+		if err := k8s.Create(ctx, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultProviderCredentials returns credentials that reference the fake
+// Backblaze credentials Secret.
+func defaultProviderCredentials() apisv1beta1.ProviderCredentials {
+	return providerCredentials(providerNamespace)
+}
+
+// providerCredentials returns credentials that reference the fake Backblaze
+// credentials Secret in the supplied namespace.
+func providerCredentials(namespace string) apisv1beta1.ProviderCredentials {
+	return apisv1beta1.ProviderCredentials{
+		Source: xpv1.CredentialsSourceSecret,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+			SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "backblaze-creds", Namespace: namespace},
+			},
+		},
+	}
+}
+
+// requireEnv skips the calling test if the envtest API server is not running.
+func requireEnv(t *testing.T) {
+	t.Helper()
+	if k8s == nil {
+		t.Skip("Skipping envtest tests - KUBEBUILDER_ASSETS must point at etcd and kube-apiserver binaries")
+	}
+}
+
+// waitFor polls fn until it returns true, failing the test on timeout.
+func waitFor(t *testing.T, what string, fn func() (bool, error)) {
+	t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		ok, err := fn()
+		if ok {
+			return
+		}
+		lastErr = err
+		time.Sleep(pollInterval)
+	}
+	t.Fatalf("timed out waiting for %s (last error: %v)", what, lastErr)
+}
+
+// conditioned is a managed resource with status conditions.
+type conditioned interface {
+	client.Object
+	GetCondition(ct xpv1.ConditionType) xpv1.Condition
+}
+
+// waitForCondition waits until obj has a condition of type ct with the
+// supplied status and reason.
+func waitForCondition(t *testing.T, obj conditioned, ct xpv1.ConditionType, status corev1.ConditionStatus, reason xpv1.ConditionReason) {
+	t.Helper()
+	waitFor(t, fmt.Sprintf("%s %s=%s (%s)", obj.GetName(), ct, status, reason), func() (bool, error) {
+		if err := k8s.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			return false, err
+		}
+		c := obj.GetCondition(ct)
+		if c.Status != status || c.Reason != reason {
+			return false, fmt.Errorf("got %s=%s (%s): %s", ct, c.Status, c.Reason, c.Message)
+		}
+		return true, nil
+	})
+}
+
+// waitForFinalizer waits until obj carries the managed resource finalizer.
+func waitForFinalizer(t *testing.T, obj client.Object) {
+	t.Helper()
+	waitFor(t, obj.GetName()+" finalizer", func() (bool, error) {
+		if err := k8s.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			return false, err
+		}
+		for _, f := range obj.GetFinalizers() {
+			if f == "finalizer.managedresource.crossplane.io" {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// waitForGone waits until obj has been removed from the API server.
+func waitForGone(t *testing.T, obj client.Object) {
+	t.Helper()
+	waitFor(t, obj.GetName()+" to be deleted", func() (bool, error) {
+		err := k8s.Get(context.Background(), client.ObjectKeyFromObject(obj), obj)
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// waitForEvent waits until an event with the supplied reason has been
+// recorded about obj, and returns it. Events about cluster scoped objects are
+// recorded in the default namespace.
+func waitForEvent(t *testing.T, obj client.Object, reason event.Reason) eventsv1.Event {
+	t.Helper()
+	ns := obj.GetNamespace()
+	if ns == "" {
+		ns = metav1.NamespaceDefault
+	}
+	var found eventsv1.Event
+	waitFor(t, fmt.Sprintf("%s event about %s", reason, obj.GetName()), func() (bool, error) {
+		l := &eventsv1.EventList{}
+		if err := k8s.List(context.Background(), l, client.InNamespace(ns)); err != nil {
+			return false, err
+		}
+		for _, e := range l.Items {
+			if e.Regarding.UID == obj.GetUID() && e.Reason == string(reason) {
+				found = e
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return found
+}
@@ -0,0 +1,831 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	backblazev1 "github.com/rossigee/provider-backblaze/apis/backblaze/v1"
+	"github.com/rossigee/provider-backblaze/internal/clients"
+	"github.com/rossigee/provider-backblaze/internal/clients/fake"
+	"github.com/rossigee/provider-backblaze/internal/recorder"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newUser(name string, dp xpv1.DeletionPolicy) *backblazev1.User {
+	return &backblazev1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.UserSpec{
+			DeletionPolicy: dp,
+			ForProvider: backblazev1.UserParameters{
+				KeyName:      name,
+				Capabilities: []backblazev1.Capability{"listBuckets", "readFiles"},
+				WriteSecretToRef: &xpv1.SecretReference{
+					Name:      name + "-creds",
+					Namespace: testNamespace,
+				},
+			},
+		},
+	}
+}
+
+func getSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	s := &corev1.Secret{}
+	err := k8s.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: name}, s)
+	return s, err
+}
+
+func TestUserCreateDelete(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-lifecycle"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+
+	waitForFinalizer(t, cr)
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	if c := cr.GetCondition(xpv1.TypeSynced); c.Status != corev1.ConditionTrue {
+		t.Errorf("Synced: want True, got %s (%s)", c.Status, c.Message)
+	}
+
+	keyID := cr.Status.AtProvider.ApplicationKeyID
+	key, ok := backend.Key(keyID)
+	if !ok {
+		t.Fatalf("application key %q was not created in B2", keyID)
+	}
+	if key.KeyName != name {
+		t.Errorf("key name: want %q, got %q", name, key.KeyName)
+	}
+	if got := meta.GetExternalName(cr); got != keyID {
+		t.Errorf("external name: want %q, got %q", keyID, got)
+	}
+	if got := cr.Status.AtProvider.AccountID; got == "" {
+		t.Error("status.atProvider.accountId was not populated")
+	}
+
+	s, err := getSecret(ctx, name+"-creds")
+	if err != nil {
+		t.Fatalf("cannot get application key Secret: %v", err)
+	}
+	if got := string(s.Data[clients.SecretKeyApplicationKeyID]); got != keyID {
+		t.Errorf("secret %s: want %q, got %q", clients.SecretKeyApplicationKeyID, keyID, got)
+	}
+	if got := string(s.Data[clients.SecretKeyApplicationKey]); got != key.ApplicationKey {
+		t.Errorf("secret %s: want %q, got %q", clients.SecretKeyApplicationKey, key.ApplicationKey, got)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if _, ok := backend.Key(keyID); ok {
+		t.Errorf("application key %q still exists in B2 after deletion", keyID)
+	}
+	if _, err := getSecret(ctx, name+"-creds"); !kerrors.IsNotFound(err) {
+		t.Errorf("application key Secret: want NotFound, got %v", err)
+	}
+	if n := callsFor("CreateApplicationKey", name); n != 1 {
+		t.Errorf("CreateApplicationKey calls: want 1, got %d", n)
+	}
+}
+
+func TestUserOrphan(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-orphan"
+
+	cr := newUser(name, xpv1.DeletionOrphan)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	keyID := cr.Status.AtProvider.ApplicationKeyID
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+
+	if _, ok := backend.Key(keyID); !ok {
+		t.Errorf("orphaned application key %q was deleted from B2", keyID)
+	}
+	if n := callsFor("DeleteApplicationKey", keyID); n != 0 {
+		t.Errorf("DeleteApplicationKey calls for orphaned key: want 0, got %d", n)
+	}
+}
+
+func TestUserCreateError(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-create-error"
+
+	backend.SetError("CreateApplicationKey", errors.New("boom"))
+	defer backend.SetError("CreateApplicationKey", nil)
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "CreateError")
+
+	if got := cr.Status.AtProvider.ApplicationKeyID; got != "" {
+		t.Errorf("status.atProvider.applicationKeyId: want empty, got %q", got)
+	}
+	if _, err := getSecret(ctx, name+"-creds"); !kerrors.IsNotFound(err) {
+		t.Errorf("application key Secret: want NotFound, got %v", err)
+	}
+}
+
+func TestUserRotation(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-rotation"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.Rotation = &backblazev1.KeyRotation{
+		RotationInterval: &metav1.Duration{Duration: 3 * time.Second},
+		GracePeriod:      &metav1.Duration{Duration: time.Second},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	firstKeyID := cr.Status.AtProvider.ApplicationKeyID
+
+	// The first key is replaced, then deleted once its grace period is over.
+	waitFor(t, "first key to be rotated and deleted", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		h := cr.Status.AtProvider.RotationHistory
+		return len(h) > 0 && h[0].ApplicationKeyID == firstKeyID && h[0].Deleted, nil
+	})
+	if _, ok := backend.Key(firstKeyID); ok {
+		t.Errorf("rotated application key %q still exists in B2", firstKeyID)
+	}
+	if e := waitForEvent(t, cr, recorder.ReasonRotatedKey); !strings.Contains(e.Note, firstKeyID) {
+		t.Errorf("%s event: want a note naming %s, got %q", recorder.ReasonRotatedKey, firstKeyID, e.Note)
+	}
+
+	// Stop rotating, so that the Secret and status settle on one key.
+	waitFor(t, "rotation to be disabled", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.Rotation = nil
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitFor(t, "Secret to hold the current key", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		s, err := getSecret(ctx, name+"-creds")
+		if err != nil {
+			return false, err
+		}
+		keyID := cr.Status.AtProvider.ApplicationKeyID
+		key, ok := backend.Key(keyID)
+		return ok && keyID != firstKeyID &&
+			string(s.Data[clients.SecretKeyApplicationKeyID]) == keyID &&
+			string(s.Data[clients.SecretKeyApplicationKey]) == key.ApplicationKey &&
+			meta.GetExternalName(cr) == keyID, nil
+	})
+
+	keyIDs := []string{cr.Status.AtProvider.ApplicationKeyID}
+	for _, k := range cr.Status.AtProvider.RotationHistory {
+		keyIDs = append(keyIDs, k.ApplicationKeyID)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+
+	// Rotated keys still in their grace period are deleted with the User.
+	for _, id := range keyIDs {
+		if _, ok := backend.Key(id); ok {
+			t.Errorf("application key %q still exists in B2 after deletion", id)
+		}
+	}
+}
+
+func TestUserRotateBeforeRequiresExpiry(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cr := newUser("envtest-user-rotate-before", xpv1.DeletionDelete)
+	cr.Spec.ForProvider.Rotation = &backblazev1.KeyRotation{
+		RotateBefore: &metav1.Duration{Duration: time.Hour},
+	}
+	err := k8s.Create(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("creating User: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "rotation.rotateBefore requires validDurationInSeconds") {
+		t.Errorf("creating User: want validDurationInSeconds message, got %v", err)
+	}
+
+	cr.Spec.ForProvider.ValidDurationInSeconds = ptr.To[int64](1800)
+	err = k8s.Create(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("creating User: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "rotation.rotateBefore must be shorter than validDurationInSeconds") {
+		t.Errorf("creating User: want shorter than message, got %v", err)
+	}
+}
+
+func TestUserConnectionDetails(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-connection"
+
+	backend.PutBucket(fake.Bucket{Name: name, ID: "fakebucketconnection", Type: "allPrivate", Region: clients.DefaultRegion})
+
+	// A connection secret left over from an earlier attempt is updated
+	// rather than failing the create.
+	stale := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-conn", Namespace: testNamespace},
+		Type:       resource.SecretTypeConnection,
+		StringData: map[string]string{clients.SecretKeyApplicationKeyID: "stale"},
+	}
+	if err := k8s.Create(ctx, stale); err != nil {
+		t.Fatalf("cannot create Secret: %v", err)
+	}
+
+	cr := &backblazev1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: backblazev1.UserSpec{
+			DeletionPolicy: xpv1.DeletionDelete,
+			WriteConnectionSecretToReference: &xpv1.SecretReference{
+				Name:      name + "-conn",
+				Namespace: testNamespace,
+			},
+			ForProvider: backblazev1.UserParameters{
+				KeyName:      name,
+				Capabilities: []backblazev1.Capability{"listFiles", "readFiles"},
+				BucketID:     ptr.To("fakebucketconnection"),
+			},
+		},
+	}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	keyID := cr.Status.AtProvider.ApplicationKeyID
+	key, _ := backend.Key(keyID)
+	s, err := getSecret(ctx, name+"-conn")
+	if err != nil {
+		t.Fatalf("cannot get connection Secret: %v", err)
+	}
+	want := map[string]string{
+		clients.SecretKeyApplicationKeyID: keyID,
+		clients.SecretKeyApplicationKey:   key.ApplicationKey,
+		"endpoint":                        "https://s3." + clients.DefaultRegion + ".backblazeb2.com",
+		"region":                          clients.DefaultRegion,
+		"bucketName":                      name,
+		"AWS_ACCESS_KEY_ID":               keyID,
+		"AWS_SECRET_ACCESS_KEY":           key.ApplicationKey,
+	}
+	for k, v := range want {
+		if got := string(s.Data[k]); got != v {
+			t.Errorf("connection secret %s: want %q, got %q", k, v, got)
+		}
+	}
+	if c := metav1.GetControllerOf(s); c == nil || c.UID != cr.GetUID() {
+		t.Errorf("connection secret controller: want User %s, got %v", cr.GetUID(), c)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestUserMultipleBuckets(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-buckets"
+
+	buckets := []*backblazev1.Bucket{
+		newBucket(name+"-a", xpv1.DeletionDelete, backblazev1.DeleteIfEmpty),
+		newBucket(name+"-b", xpv1.DeletionDelete, backblazev1.DeleteIfEmpty),
+	}
+	for _, b := range buckets {
+		if err := k8s.Create(ctx, b); err != nil {
+			t.Fatalf("cannot create Bucket: %v", err)
+		}
+	}
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.BucketRefs = []xpv1.Reference{{Name: name + "-a"}, {Name: name + "-b"}}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+
+	// The User waits for the referenced Buckets to have IDs.
+	for _, b := range buckets {
+		waitForCondition(t, b, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	want := []string{buckets[0].Status.AtProvider.BucketID, buckets[1].Status.AtProvider.BucketID}
+	if diff := cmp.Diff(want, cr.Spec.ForProvider.BucketIDs); diff != "" {
+		t.Errorf("spec.forProvider.bucketIds: -want, +got:\n%s", diff)
+	}
+	key, _ := backend.Key(cr.Status.AtProvider.ApplicationKeyID)
+	if diff := cmp.Diff(want, key.BucketIDs, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("application key bucketIds: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(want, cr.Status.AtProvider.BucketIDs, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("status.atProvider.bucketIds: -want, +got:\n%s", diff)
+	}
+	if cr.Status.AtProvider.BucketID != nil {
+		t.Errorf("status.atProvider.bucketId: want nil for a key restricted to two buckets, got %q", *cr.Status.AtProvider.BucketID)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+	for _, b := range buckets {
+		if err := k8s.Delete(ctx, b); err != nil {
+			t.Fatalf("cannot delete Bucket: %v", err)
+		}
+		waitForGone(t, b)
+	}
+}
+
+func TestUserImport(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-import"
+
+	existing, err := backend.CreateApplicationKey(ctx, name, []string{"listBuckets", "readFiles"}, nil, "", nil)
+	if err != nil {
+		t.Fatalf("cannot create application key: %v", err)
+	}
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	meta.SetExternalName(cr, existing.ApplicationKeyID)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, backblazev1.TypeSecretUnrecoverable, corev1.ConditionTrue, "KeyImported")
+
+	if got := cr.Status.AtProvider.ApplicationKeyID; got != existing.ApplicationKeyID {
+		t.Errorf("status.atProvider.applicationKeyId: want %q, got %q", existing.ApplicationKeyID, got)
+	}
+	if c := cr.GetCondition(xpv1.TypeReady); c.Status != corev1.ConditionTrue {
+		t.Errorf("Ready: want True, got %s (%s)", c.Status, c.Message)
+	}
+	// The pre-existing key is the only one created.
+	if n := callsFor("CreateApplicationKey", name); n != 1 {
+		t.Errorf("CreateApplicationKey calls: want 1, got %d", n)
+	}
+	if _, err := getSecret(ctx, name+"-creds"); !kerrors.IsNotFound(err) {
+		t.Errorf("getting secret of an imported key: want NotFound, got %v", err)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+	if _, ok := backend.Key(existing.ApplicationKeyID); ok {
+		t.Errorf("imported application key %s still exists after deletion", existing.ApplicationKeyID)
+	}
+}
+
+func TestUserImportMissingKey(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		name     string
+		policies xpv1.ManagementPolicies
+		reason   xpv1.ConditionReason
+	}{
+		"Create": {
+			name:     "envtest-user-import-missing",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			reason:   "CreateError",
+		},
+		"NoCreate": {
+			name:     "envtest-user-import-missing-nocreate",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionUpdate, xpv1.ManagementActionDelete},
+			reason:   "NotFound",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := newUser(tc.name, xpv1.DeletionDelete)
+			cr.Spec.ManagementPolicies = tc.policies
+			meta.SetExternalName(cr, "K005000000000404")
+			if err := k8s.Create(ctx, cr); err != nil {
+				t.Fatalf("cannot create User: %v", err)
+			}
+
+			// A key named by the external name that doesn't exist is
+			// never replaced by a new key.
+			waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, tc.reason)
+			if n := callsFor("CreateApplicationKey", tc.name); n != 0 {
+				t.Errorf("CreateApplicationKey calls: want 0, got %d", n)
+			}
+
+			if err := k8s.Delete(ctx, cr); err != nil {
+				t.Fatalf("cannot delete User: %v", err)
+			}
+			waitForGone(t, cr)
+		})
+	}
+}
+
+func TestUserObserveOnly(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-observe"
+
+	existing, err := backend.CreateApplicationKey(ctx, name, []string{"listFiles"}, nil, "logs/", nil)
+	if err != nil {
+		t.Fatalf("cannot create application key: %v", err)
+	}
+
+	// An observed User needn't name a secret, and is found by its keyName.
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.WriteSecretToRef = nil
+	cr.Spec.ManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	if got := meta.GetExternalName(cr); got != existing.ApplicationKeyID {
+		t.Errorf("external name: want %q, got %q", existing.ApplicationKeyID, got)
+	}
+	obs := cr.Status.AtProvider
+	if obs.ApplicationKeyID != existing.ApplicationKeyID || ptr.Deref(obs.NamePrefix, "") != "logs/" || strings.Join(obs.Capabilities, ",") != "listFiles" {
+		t.Errorf("status.atProvider: got %+v", obs)
+	}
+	if c := cr.GetCondition(backblazev1.TypeSecretUnrecoverable); c.Status != corev1.ConditionTrue {
+		t.Errorf("SecretUnrecoverable: want True, got %s", c.Status)
+	}
+	// The key differs from forProvider, but is never replaced.
+	if n := callsFor("CreateApplicationKey", name); n != 1 {
+		t.Errorf("CreateApplicationKey calls: want 1, got %d", n)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+	if _, ok := backend.Key(existing.ApplicationKeyID); !ok {
+		t.Errorf("observed application key %s was deleted", existing.ApplicationKeyID)
+	}
+}
+
+func TestUserRequiresSecretRef(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cr := newUser("envtest-user-no-secret", xpv1.DeletionDelete)
+	cr.Spec.ForProvider.WriteSecretToRef = nil
+	err := k8s.Create(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("creating User: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "either writeConnectionSecretToRef or forProvider.writeSecretToRef must be set") {
+		t.Errorf("creating User: want secret reference message, got %v", err)
+	}
+}
+
+func TestUserImportedKeyDiffers(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-import-differs"
+
+	existing, err := backend.CreateApplicationKey(ctx, name, []string{"listBuckets", "readFiles"}, nil, "", nil)
+	if err != nil {
+		t.Fatalf("cannot create application key: %v", err)
+	}
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.Capabilities = []backblazev1.Capability{"listBuckets", "readFiles", "writeFiles"}
+	meta.SetExternalName(cr, existing.ApplicationKeyID)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+
+	// An imported key isn't replaced, which would delete it, unless asked.
+	waitForCondition(t, cr, xpv1.TypeSynced, corev1.ConditionFalse, "ReconcileError")
+	if c := cr.GetCondition(xpv1.TypeSynced); !strings.Contains(c.Message, "set replacementPolicy to Replace") {
+		t.Errorf("Synced message: want replacementPolicy hint, got %q", c.Message)
+	}
+	if got := cr.Status.AtProvider.ApplicationKeyID; got != existing.ApplicationKeyID {
+		t.Errorf("status.atProvider.applicationKeyId: want %q, got %q", existing.ApplicationKeyID, got)
+	}
+	if n := callsFor("CreateApplicationKey", name); n != 1 {
+		t.Errorf("CreateApplicationKey calls: want 1, got %d", n)
+	}
+
+	waitFor(t, "replacementPolicy to be updated", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.ReplacementPolicy = backblazev1.ReplacementPolicyReplace
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitFor(t, "application key to be replaced", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		return cr.Status.AtProvider.ApplicationKeyID != existing.ApplicationKeyID, nil
+	})
+	if c := cr.GetCondition(backblazev1.TypeSecretUnrecoverable); c.Status != corev1.ConditionFalse {
+		t.Errorf("SecretUnrecoverable of a replaced key: want False, got %s", c.Status)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestUserReplaceOnSpecChange(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-replace"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	oldKeyID := cr.Status.AtProvider.ApplicationKeyID
+
+	// B2 keys can't be changed, so new capabilities need a new key.
+	waitFor(t, "capabilities to be updated", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		cr.Spec.ForProvider.Capabilities = []backblazev1.Capability{"listBuckets", "readFiles", "writeFiles"}
+		return k8s.Update(ctx, cr) == nil, nil
+	})
+	waitFor(t, "application key to be replaced", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		return cr.Status.AtProvider.ApplicationKeyID != oldKeyID, nil
+	})
+
+	newKeyID := cr.Status.AtProvider.ApplicationKeyID
+	key, _ := backend.Key(newKeyID)
+	if len(key.Capabilities) != 3 {
+		t.Errorf("replacement key capabilities: want 3, got %v", key.Capabilities)
+	}
+	s, err := getSecret(ctx, name+"-creds")
+	if err != nil {
+		t.Fatalf("cannot get application key Secret: %v", err)
+	}
+	if got := string(s.Data[clients.SecretKeyApplicationKeyID]); got != newKeyID {
+		t.Errorf("secret %s: want %q, got %q", clients.SecretKeyApplicationKeyID, newKeyID, got)
+	}
+
+	// The old key stays usable for the grace period.
+	h := cr.Status.AtProvider.RotationHistory
+	if len(h) != 1 || h[0].ApplicationKeyID != oldKeyID || h[0].Reason != backblazev1.KeyReplacedSpecChange || h[0].Deleted {
+		t.Errorf("status.atProvider.rotationHistory: want pending %s, got %+v", oldKeyID, h)
+	}
+	if _, ok := backend.Key(oldKeyID); !ok {
+		t.Errorf("replaced application key %q was deleted before its grace period", oldKeyID)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+	for _, id := range []string{oldKeyID, newKeyID} {
+		if _, ok := backend.Key(id); ok {
+			t.Errorf("application key %q still exists in B2 after deletion", id)
+		}
+	}
+}
+
+func TestUserReplaceDeletedKey(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-replace-deleted"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+	oldKeyID := cr.Status.AtProvider.ApplicationKeyID
+
+	// A key deleted outside of Crossplane is replaced.
+	if err := backend.DeleteApplicationKey(ctx, oldKeyID); err != nil {
+		t.Fatalf("cannot delete application key: %v", err)
+	}
+	touch(t, cr)
+	waitFor(t, "application key to be replaced", func() (bool, error) {
+		if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+			return false, err
+		}
+		return cr.Status.AtProvider.ApplicationKeyID != oldKeyID, nil
+	})
+	h := cr.Status.AtProvider.RotationHistory
+	if len(h) != 1 || h[0].Reason != backblazev1.KeyReplacedNotFound || !h[0].Deleted {
+		t.Errorf("status.atProvider.rotationHistory: want deleted %s, got %+v", oldKeyID, h)
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestUserReplacementRejected(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-replace-rejected"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.ReplacementPolicy = backblazev1.ReplacementPolicyReject
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	changes := map[string]func(p *backblazev1.UserParameters){
+		"namePrefix": func(p *backblazev1.UserParameters) { p.NamePrefix = ptr.To("logs/") },
+		"bucketIds":  func(p *backblazev1.UserParameters) { p.BucketIDs = []string{"fakebucketrejected"} },
+	}
+	for field, change := range changes {
+		var err error
+		waitFor(t, field+" update to be rejected", func() (bool, error) {
+			if err := k8s.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+				return false, err
+			}
+			change(&cr.Spec.ForProvider)
+			err = k8s.Update(ctx, cr)
+			return !kerrors.IsConflict(err), err
+		})
+		if !kerrors.IsInvalid(err) {
+			t.Fatalf("updating User %s: want Invalid error, got %v", field, err)
+		}
+		if !strings.Contains(err.Error(), "can't be changed when replacementPolicy is Reject") {
+			t.Errorf("updating User %s: want replacementPolicy message, got %v", field, err)
+		}
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestUserCapabilityPreset(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-preset"
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.Capabilities = nil
+	cr.Spec.ForProvider.CapabilityPreset = ptr.To(backblazev1.CapabilityPresetBackupWriter)
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionTrue, "Available")
+
+	key, _ := backend.Key(cr.Status.AtProvider.ApplicationKeyID)
+	want := backblazev1.UserParameters{CapabilityPreset: cr.Spec.ForProvider.CapabilityPreset}
+	if got := key.Capabilities; strings.Join(got, ",") != strings.Join(want.GetCapabilities(), ",") {
+		t.Errorf("key capabilities: want %v, got %v", want.GetCapabilities(), got)
+	}
+	for _, c := range key.Capabilities {
+		if c == string(backblazev1.CapabilityDeleteFiles) {
+			t.Errorf("backupWriter key can delete files: %v", key.Capabilities)
+		}
+	}
+
+	if err := k8s.Delete(ctx, cr); err != nil {
+		t.Fatalf("cannot delete User: %v", err)
+	}
+	waitForGone(t, cr)
+}
+
+func TestUserInvalidCapability(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	cr := newUser("envtest-user-bad-capability", xpv1.DeletionDelete)
+	cr.Spec.ForProvider.Capabilities = []backblazev1.Capability{"listFiles", "writeFile"}
+	err := k8s.Create(ctx, cr)
+	if !kerrors.IsInvalid(err) {
+		t.Fatalf("creating User: want Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "writeFile") {
+		t.Errorf("creating User: want writeFile in message, got %v", err)
+	}
+}
+
+func TestUserMissingProviderCapability(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	name := "envtest-user-missing-capability"
+
+	backend.SetAuthorizedCapabilities([]string{"listBuckets", "listFiles", "readFiles", "writeKeys"})
+	defer backend.SetAuthorizedCapabilities(nil)
+
+	cr := newUser(name, xpv1.DeletionDelete)
+	cr.Spec.ForProvider.Capabilities = []backblazev1.Capability{"listFiles", "bypassGovernance"}
+	if err := k8s.Create(ctx, cr); err != nil {
+		t.Fatalf("cannot create User: %v", err)
+	}
+	waitForCondition(t, cr, xpv1.TypeReady, corev1.ConditionFalse, "CreateError")
+
+	if c := cr.GetCondition(xpv1.TypeReady); !strings.Contains(c.Message, "bypassGovernance") {
+		t.Errorf("Ready message: want bypassGovernance, got %q", c.Message)
+	}
+	if n := callsFor("CreateApplicationKey", name); n != 0 {
+		t.Errorf("CreateApplicationKey calls: want 0, got %d", n)
+	}
+}
+
+func TestUserValidation(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		update func(p *backblazev1.UserParameters)
+		want   string
+	}{
+		"InvalidKeyName": {
+			update: func(p *backblazev1.UserParameters) { p.KeyName = "envtest user" },
+			want:   "keyName must be 1 to 100 letters, digits and hyphens",
+		},
+		"ValidDurationTooLong": {
+			update: func(p *backblazev1.UserParameters) { p.ValidDurationInSeconds = ptr.To[int64](86400001) },
+			want:   "should be less than or equal to 86400000",
+		},
+		"NegativeGracePeriod": {
+			update: func(p *backblazev1.UserParameters) {
+				p.Rotation = &backblazev1.KeyRotation{
+					RotationInterval: &metav1.Duration{Duration: time.Hour},
+					GracePeriod:      &metav1.Duration{Duration: -time.Minute},
+				}
+			},
+			want: "rotation durations must not be negative",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := newUser("envtest-user-validation", xpv1.DeletionDelete)
+			tc.update(&cr.Spec.ForProvider)
+			err := k8s.Create(ctx, cr)
+			if !kerrors.IsInvalid(err) {
+				t.Fatalf("creating User: want Invalid error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("creating User: want %q, got %v", tc.want, err)
+			}
+		})
+	}
+}